@@ -8,43 +8,20 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
 	"os"
 	"strings"
 
-	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/john-na4/multicall3/go/multicall"
+	"github.com/john-na4/multicall3/go/provider"
 	"github.com/joho/godotenv"
 )
 
-// Multicall3 ABI - only the functions we need
-const multicall3ABI = `[
-	{
-		"inputs": [
-			{
-				"components": [
-					{"internalType": "address", "name": "target", "type": "address"},
-					{"internalType": "bytes", "name": "callData", "type": "bytes"}
-				],
-				"internalType": "struct Multicall3.Call[]",
-				"name": "calls",
-				"type": "tuple[]"
-			}
-		],
-		"name": "aggregate",
-		"outputs": [
-			{"internalType": "uint256", "name": "blockNumber", "type": "uint256"},
-			{"internalType": "bytes[]", "name": "returnData", "type": "bytes[]"}
-		],
-		"stateMutability": "payable",
-		"type": "function"
-	}
-]`
-
 // DAI ABI - only the functions we need
 const daiABI = `[
 	{"constant": true, "inputs": [], "name": "symbol", "outputs": [{"internalType": "string", "name": "", "type": "string"}], "payable": false, "stateMutability": "view", "type": "function"},
@@ -54,119 +31,76 @@ const daiABI = `[
 
 // Known contract addresses
 var (
-	multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
-	daiAddress        = common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
-	vitalikAddress    = common.HexToAddress("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+	daiAddress     = common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	vitalikAddress = common.HexToAddress("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
 )
 
-// Call represents a single call in the multicall
-type Call struct {
-	Target   common.Address `json:"target"`
-	CallData []byte         `json:"callData"`
-}
-
-// AggregateResult represents the result of a multicall aggregate
-type AggregateResult struct {
-	BlockNumber *big.Int
-	ReturnData  [][]byte
-}
-
 func main() {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using system environment variables")
 	}
 
-	// Get RPC URL from environment
+	// Get RPC URL from environment. It may be an http(s):// or ws(s):// URL or the path of an IPC
+	// socket, or several of them separated by commas to fail over between them.
 	rpcURL := os.Getenv("MAINNET_RPC_URL")
 	if rpcURL == "" {
 		log.Fatal("MAINNET_RPC_URL environment variable is required")
 	}
 
-	// Connect to Ethereum client
-	client, err := ethclient.Dial(rpcURL)
+	// Connect to the Ethereum nodes
+	client, err := provider.Dial(context.Background(), strings.Split(rpcURL, ","))
 	if err != nil {
 		log.Fatalf("Failed to connect to the Ethereum client: %v", err)
 	}
 	defer client.Close()
 
-	// Parse ABIs
-	multicallABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	// Create the multicall client
+	multicallClient, err := multicall.NewClient(client)
 	if err != nil {
-		log.Fatalf("Failed to parse Multicall3 ABI: %v", err)
+		log.Fatalf("Failed to create multicall client: %v", err)
 	}
 
+	// Parse ABIs
 	daiABIParsed, err := abi.JSON(strings.NewReader(daiABI))
 	if err != nil {
 		log.Fatalf("Failed to parse DAI ABI: %v", err)
 	}
 
-	// Prepare calls
-	var calls []Call
-	symbolData, err := daiABIParsed.Pack("symbol")
-	if err != nil {
-		log.Fatalf("Failed to pack symbol call: %v", err)
-	}
-	calls = append(calls, Call{Target: daiAddress, CallData: symbolData})
-
-	decimalsData, err := daiABIParsed.Pack("decimals")
-	if err != nil {
-		log.Fatalf("Failed to pack decimals call: %v", err)
-	}
-	calls = append(calls, Call{Target: daiAddress, CallData: decimalsData})
-
-	balanceData, err := daiABIParsed.Pack("balanceOf", vitalikAddress)
-	if err != nil {
-		log.Fatalf("Failed to pack balanceOf call: %v", err)
-	}
-	calls = append(calls, Call{Target: daiAddress, CallData: balanceData})
-
-	// Pack the multicall
-	multicallData, err := multicallABI.Pack("aggregate", calls)
-	if err != nil {
-		log.Fatalf("Failed to pack multicall: %v", err)
-	}
-
-	// Execute the multicall
-	msg := ethereum.CallMsg{
-		To:   &multicall3Address,
-		Data: multicallData,
-	}
-	result, err := client.CallContract(context.Background(), msg, nil)
-	if err != nil {
+	// Prepare typed calls, each of which decodes its own return value
+	blockNumber := multicall.View[*big.Int](multicall.ABI, "getBlockNumber")
+	symbol := multicall.View[string](daiABIParsed, "symbol")
+	decimals := multicall.View[uint8](daiABIParsed, "decimals")
+	daiBalance := multicall.View[*big.Int](daiABIParsed, "balanceOf", vitalikAddress)
+
+	// Build and execute the multicall. The block number is fetched in the same batch by calling
+	// the Multicall3 contract itself.
+	_, err = multicallClient.NewBatch().
+		AddCall(multicallClient.Address(), blockNumber).
+		AddCall(daiAddress, symbol).
+		AddCall(daiAddress, decimals).
+		AddCall(daiAddress, daiBalance).
+		Execute(context.Background())
+	// A call whose return data cannot be decoded does not fail the others: its typed call
+	// reports the error instead of a value
+	if err != nil && !errors.Is(err, multicall.ErrDecode) {
 		log.Fatalf("Failed to execute multicall: %v", err)
 	}
-
-	// Unpack the result
-	var aggregateResult AggregateResult
-	err = multicallABI.UnpackIntoInterface(&aggregateResult, "aggregate", result)
 	if err != nil {
-		log.Fatalf("Failed to unpack multicall result: %v", err)
+		log.Printf("Some results could not be decoded: %v", err)
 	}
 
-	var symbol string
-	err = daiABIParsed.UnpackIntoInterface(&symbol, "symbol", aggregateResult.ReturnData[0])
-	if err != nil {
-		log.Fatalf("Failed to unpack symbol: %v", err)
-	}
-
-	var decimals uint8
-	err = daiABIParsed.UnpackIntoInterface(&decimals, "decimals", aggregateResult.ReturnData[1])
-	if err != nil {
-		log.Fatalf("Failed to unpack decimals: %v", err)
-	}
+	// display results
+	fmt.Printf("Block Number: %s\n", blockNumber.Value().String())
+	fmt.Printf("DAI Symbol: %s\n", symbol.Value())
+	fmt.Printf("DAI Decimals: %d\n", decimals.Value())
 
-	var daiBalance *big.Int
-	err = daiABIParsed.UnpackIntoInterface(&daiBalance, "balanceOf", aggregateResult.ReturnData[2])
+	// Convert DAI balance to human readable format
+	balance, err := daiBalance.Get()
 	if err != nil {
-		log.Fatalf("Failed to unpack balance: %v", err)
+		fmt.Printf("Vitalik's %s balance: unavailable (%v)\n", symbol.Value(), err)
+		return
 	}
-	// Convert DAI balance to human readable format
-	daiBalanceFloat := new(big.Float).Quo(new(big.Float).SetInt(daiBalance), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
-
-	// display results
-	fmt.Printf("Block Number: %s\n", aggregateResult.BlockNumber.String())
-	fmt.Printf("DAI Symbol: %s\n", symbol)
-	fmt.Printf("DAI Decimals: %d\n", decimals)
-	fmt.Printf("Vitalik's %s balance: %s\n", symbol, daiBalanceFloat.Text('f', 18))
+	daiBalanceFloat := new(big.Float).Quo(new(big.Float).SetInt(balance), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals.Value())), nil)))
+	fmt.Printf("Vitalik's %s balance: %s\n", symbol.Value(), daiBalanceFloat.Text('f', 18))
 }
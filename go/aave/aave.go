@@ -0,0 +1,197 @@
+// Package aave reads the positions of Aave V3 accounts and the state of its
+// reserves in bulk through Multicall3, for liquidation monitors:
+//
+//	accounts, err := aave.Accounts(ctx, client, aave.EthereumPool, borrowers)
+//	for _, account := range accounts {
+//		if account.Err == nil && account.Liquidatable() {
+//			fmt.Println(account.Address, account.HealthFactor)
+//		}
+//	}
+package aave
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// EthereumPool is the address of the Aave V3 Pool on Ethereum mainnet. The
+// Pool has another address on every other chain.
+var EthereumPool = common.HexToAddress("0x87870Bca3F3fD6335C3F4ce8392D69350B4fA4E2")
+
+// ABI is the part of the Aave V3 Pool ABI used by the helpers
+var ABI = helper.MustParseABI("aave", poolABI)
+
+const poolABI = `[
+	{"type":"function","name":"getUserAccountData","stateMutability":"view","inputs":[{"name":"user","type":"address"}],"outputs":[{"name":"totalCollateralBase","type":"uint256"},{"name":"totalDebtBase","type":"uint256"},{"name":"availableBorrowsBase","type":"uint256"},{"name":"currentLiquidationThreshold","type":"uint256"},{"name":"ltv","type":"uint256"},{"name":"healthFactor","type":"uint256"}]},
+	{"type":"function","name":"getReserveData","stateMutability":"view","inputs":[{"name":"asset","type":"address"}],"outputs":[{"name":"","type":"tuple","components":[
+		{"name":"configuration","type":"tuple","components":[{"name":"data","type":"uint256"}]},
+		{"name":"liquidityIndex","type":"uint128"},
+		{"name":"currentLiquidityRate","type":"uint128"},
+		{"name":"variableBorrowIndex","type":"uint128"},
+		{"name":"currentVariableBorrowRate","type":"uint128"},
+		{"name":"currentStableBorrowRate","type":"uint128"},
+		{"name":"lastUpdateTimestamp","type":"uint40"},
+		{"name":"id","type":"uint16"},
+		{"name":"aTokenAddress","type":"address"},
+		{"name":"stableDebtTokenAddress","type":"address"},
+		{"name":"variableDebtTokenAddress","type":"address"},
+		{"name":"interestRateStrategyAddress","type":"address"},
+		{"name":"accruedToTreasury","type":"uint128"},
+		{"name":"unbacked","type":"uint128"},
+		{"name":"isolationModeTotalDebt","type":"uint128"}
+	]}]}
+]`
+
+// WAD is 1e18, the scale of health factors
+var WAD = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// RAY is 1e27, the scale of indexes and rates
+var RAY = new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil)
+
+// accountData are the outputs of getUserAccountData
+type accountData struct {
+	TotalCollateralBase         *big.Int
+	TotalDebtBase               *big.Int
+	AvailableBorrowsBase        *big.Int
+	CurrentLiquidationThreshold *big.Int
+	Ltv                         *big.Int
+	HealthFactor                *big.Int
+}
+
+// Account is the position of an account across all reserves. Amounts are in
+// the base currency of the Pool's price oracle, USD with 8 decimals on most
+// deployments. Fields the Pool failed to return are left as zero values, and
+// Err reports why.
+type Account struct {
+	Address         common.Address
+	TotalCollateral *big.Int
+	TotalDebt       *big.Int
+	// AvailableBorrows is how much more the account may borrow
+	AvailableBorrows *big.Int
+	// LiquidationThreshold and LTV are the weighted averages over the
+	// account's collateral, in basis points
+	LiquidationThreshold uint64
+	LTV                  uint64
+	// HealthFactor is scaled by WAD. Accounts without debt have the maximum
+	// uint256 value.
+	HealthFactor *big.Int
+	Err          error
+}
+
+// Liquidatable reports whether the account's health factor is below 1, as
+// it must be for the account to be liquidated
+func (a Account) Liquidatable() bool {
+	return a.HealthFactor != nil && a.HealthFactor.Cmp(WAD) < 0
+}
+
+// Accounts fetches the account data of every account from pool in a single
+// batch, returning them in the order of the accounts. The error is only set
+// if the batch itself failed, the failures of an account are reported by its
+// Account.Err.
+func Accounts(ctx context.Context, client *multicall.Client, pool common.Address, accounts []common.Address, opts ...multicall.CallOption) ([]Account, error) {
+	batch := client.NewBatch()
+	calls := make([]*multicall.CallOf[accountData], len(accounts))
+	for i, account := range accounts {
+		calls[i] = multicall.View[accountData](ABI, "getUserAccountData", account)
+		batch.AddCall(pool, calls[i]).AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	state := make([]Account, len(accounts))
+	for i, account := range accounts {
+		state[i] = Account{Address: account}
+		if state[i].Err = results[i].Error(pool, fmt.Sprintf("getUserAccountData(%s)", account)); state[i].Err != nil {
+			continue
+		}
+		data := calls[i].Value()
+		state[i].TotalCollateral = data.TotalCollateralBase
+		state[i].TotalDebt = data.TotalDebtBase
+		state[i].AvailableBorrows = data.AvailableBorrowsBase
+		state[i].LiquidationThreshold = data.CurrentLiquidationThreshold.Uint64()
+		state[i].LTV = data.Ltv.Uint64()
+		state[i].HealthFactor = data.HealthFactor
+	}
+	return state, nil
+}
+
+// reserveData is the output of getReserveData
+type reserveData struct {
+	Configuration               struct{ Data *big.Int }
+	LiquidityIndex              *big.Int
+	CurrentLiquidityRate        *big.Int
+	VariableBorrowIndex         *big.Int
+	CurrentVariableBorrowRate   *big.Int
+	CurrentStableBorrowRate     *big.Int
+	LastUpdateTimestamp         *big.Int
+	Id                          uint16
+	ATokenAddress               common.Address
+	StableDebtTokenAddress      common.Address
+	VariableDebtTokenAddress    common.Address
+	InterestRateStrategyAddress common.Address
+	AccruedToTreasury           *big.Int
+	Unbacked                    *big.Int
+	IsolationModeTotalDebt      *big.Int
+}
+
+// Reserve is the state of a reserve. Indexes and rates are scaled by RAY,
+// rates being yearly. Fields the Pool failed to return are left as zero
+// values, and Err reports why.
+type Reserve struct {
+	Asset common.Address
+	// Configuration is the bitmap of the reserve's parameters
+	Configuration       *big.Int
+	LiquidityIndex      *big.Int
+	LiquidityRate       *big.Int
+	VariableBorrowIndex *big.Int
+	VariableBorrowRate  *big.Int
+	StableBorrowRate    *big.Int
+	LastUpdate          time.Time
+	AToken              common.Address
+	StableDebtToken     common.Address
+	VariableDebtToken   common.Address
+	Err                 error
+}
+
+// Reserves fetches the reserve data of every asset from pool in a single
+// batch, returning them in the order of the assets. The error is only set if
+// the batch itself failed, the failures of a reserve are reported by its
+// Reserve.Err.
+func Reserves(ctx context.Context, client *multicall.Client, pool common.Address, assets []common.Address, opts ...multicall.CallOption) ([]Reserve, error) {
+	batch := client.NewBatch()
+	calls := make([]*multicall.CallOf[reserveData], len(assets))
+	for i, asset := range assets {
+		calls[i] = multicall.View[reserveData](ABI, "getReserveData", asset)
+		batch.AddCall(pool, calls[i]).AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	reserves := make([]Reserve, len(assets))
+	for i, asset := range assets {
+		reserves[i] = Reserve{Asset: asset}
+		if reserves[i].Err = results[i].Error(pool, fmt.Sprintf("getReserveData(%s)", asset)); reserves[i].Err != nil {
+			continue
+		}
+		data := calls[i].Value()
+		reserves[i].Configuration = data.Configuration.Data
+		reserves[i].LiquidityIndex = data.LiquidityIndex
+		reserves[i].LiquidityRate = data.CurrentLiquidityRate
+		reserves[i].VariableBorrowIndex = data.VariableBorrowIndex
+		reserves[i].VariableBorrowRate = data.CurrentVariableBorrowRate
+		reserves[i].StableBorrowRate = data.CurrentStableBorrowRate
+		reserves[i].LastUpdate = time.Unix(data.LastUpdateTimestamp.Int64(), 0)
+		reserves[i].AToken = data.ATokenAddress
+		reserves[i].StableDebtToken = data.StableDebtTokenAddress
+		reserves[i].VariableDebtToken = data.VariableDebtTokenAddress
+	}
+	return reserves, nil
+}
@@ -0,0 +1,113 @@
+package aave_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/aave"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	healthy    = common.Address{19: 1}
+	underwater = common.Address{19: 2}
+	weth       = common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	aWETH      = common.HexToAddress("0x4d5F47FA6A74757f35C14fD3a6Ef8E3C9BC514E8")
+)
+
+// reserve mirrors the tuple returned by getReserveData
+type reserve struct {
+	Configuration               struct{ Data *big.Int }
+	LiquidityIndex              *big.Int
+	CurrentLiquidityRate        *big.Int
+	VariableBorrowIndex         *big.Int
+	CurrentVariableBorrowRate   *big.Int
+	CurrentStableBorrowRate     *big.Int
+	LastUpdateTimestamp         *big.Int
+	Id                          uint16
+	ATokenAddress               common.Address
+	StableDebtTokenAddress      common.Address
+	VariableDebtTokenAddress    common.Address
+	InterestRateStrategyAddress common.Address
+	AccruedToTreasury           *big.Int
+	Unbacked                    *big.Int
+	IsolationModeTotalDebt      *big.Int
+}
+
+// newClient returns a client reading a pool in which healthy has a health
+// factor of 2 and underwater one of 0.5, and whose only reserve is WETH.
+// Other accounts and assets revert.
+func newClient(t *testing.T) *multicall.Client {
+	t.Helper()
+	half := new(big.Int).Quo(aave.WAD, big.NewInt(2))
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		aave.EthereumPool: fake.Methods(aave.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getUserAccountData": func(args []interface{}) ([]interface{}, error) {
+				health := map[common.Address]*big.Int{healthy: new(big.Int).Mul(aave.WAD, big.NewInt(2)), underwater: half}[args[0].(common.Address)]
+				if health == nil {
+					return nil, &fake.Revert{}
+				}
+				return []interface{}{big.NewInt(1000), big.NewInt(400), big.NewInt(300), big.NewInt(8250), big.NewInt(8000), health}, nil
+			},
+			"getReserveData": func(args []interface{}) ([]interface{}, error) {
+				if args[0].(common.Address) != weth {
+					return nil, &fake.Revert{}
+				}
+				data := reserve{
+					LiquidityIndex:            new(big.Int).Set(aave.RAY),
+					CurrentLiquidityRate:      big.NewInt(2),
+					VariableBorrowIndex:       new(big.Int).Set(aave.RAY),
+					CurrentVariableBorrowRate: big.NewInt(3),
+					CurrentStableBorrowRate:   big.NewInt(4),
+					LastUpdateTimestamp:       big.NewInt(1_700_000_000),
+					ATokenAddress:             aWETH,
+					AccruedToTreasury:         new(big.Int),
+					Unbacked:                  new(big.Int),
+					IsolationModeTotalDebt:    new(big.Int),
+				}
+				data.Configuration.Data = big.NewInt(1)
+				return []interface{}{data}, nil
+			},
+		}),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestAccounts(t *testing.T) {
+	accounts, err := aave.Accounts(context.Background(), newClient(t), aave.EthereumPool, []common.Address{healthy, underwater, {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := accounts[0]; got.Err != nil || got.TotalCollateral.Int64() != 1000 || got.TotalDebt.Int64() != 400 || got.AvailableBorrows.Int64() != 300 ||
+		got.LiquidationThreshold != 8250 || got.LTV != 8000 || got.Liquidatable() {
+		t.Errorf("got account %+v, want a healthy one", got)
+	}
+	if got := accounts[1]; got.Err != nil || !got.Liquidatable() {
+		t.Errorf("got account %+v, want a liquidatable one", got)
+	}
+	if got := accounts[2]; got.HealthFactor != nil || got.Liquidatable() || !errors.Is(got.Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got account %+v, want the revert of getUserAccountData", got)
+	}
+}
+
+func TestReserves(t *testing.T) {
+	reserves, err := aave.Reserves(context.Background(), newClient(t), aave.EthereumPool, []common.Address{weth, {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reserves[0]; got.Err != nil || got.Configuration.Int64() != 1 || got.LiquidityIndex.Cmp(aave.RAY) != 0 || got.LiquidityRate.Int64() != 2 ||
+		got.VariableBorrowRate.Int64() != 3 || got.StableBorrowRate.Int64() != 4 || !got.LastUpdate.Equal(time.Unix(1_700_000_000, 0)) || got.AToken != aWETH {
+		t.Errorf("got reserve %+v", got)
+	}
+	if got := reserves[1]; got.LiquidityIndex != nil || !errors.Is(got.Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got reserve %+v, want the revert of getReserveData", got)
+	}
+}
@@ -0,0 +1,173 @@
+// Package abidecode decodes ABI-encoded return data without reflection. It is
+// the runtime support for decoders generated by multicall-gen, but its
+// functions can also be used directly.
+//
+// Every function decodes the value whose head word starts at offset in data.
+// Dynamic values, such as bytes, strings and arrays, are located through the
+// offset stored in their head word, relative to the start of data.
+package abidecode
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// WordSize is the size in bytes of an ABI word
+const WordSize = 32
+
+// ErrShortData is returned when data ends before the value being decoded
+var ErrShortData = errors.New("abidecode: data too short")
+
+// Word returns the 32 byte word starting at offset
+func Word(data []byte, offset int) ([]byte, error) {
+	if offset < 0 || offset > len(data)-WordSize {
+		return nil, fmt.Errorf("%w: word at offset %d of %d bytes", ErrShortData, offset, len(data))
+	}
+	return data[offset : offset+WordSize], nil
+}
+
+// Uint decodes an unsigned integer of at most bits bits, which is at most 64
+func Uint(data []byte, offset, bits int) (uint64, error) {
+	word, err := Word(data, offset)
+	if err != nil {
+		return 0, err
+	}
+	for _, b := range word[:WordSize-8] {
+		if b != 0 {
+			return 0, fmt.Errorf("abidecode: uint%d at offset %d overflows", bits, offset)
+		}
+	}
+	n := beUint64(word[WordSize-8:])
+	if bits < 64 && n>>bits != 0 {
+		return 0, fmt.Errorf("abidecode: uint%d at offset %d overflows", bits, offset)
+	}
+	return n, nil
+}
+
+// Int decodes a signed integer of at most bits bits, which is at most 64
+func Int(data []byte, offset, bits int) (int64, error) {
+	word, err := Word(data, offset)
+	if err != nil {
+		return 0, err
+	}
+	n := int64(beUint64(word[WordSize-8:]))
+	var ext byte
+	if n < 0 {
+		ext = 0xff
+	}
+	for _, b := range word[:WordSize-8] {
+		if b != ext {
+			return 0, fmt.Errorf("abidecode: int%d at offset %d overflows", bits, offset)
+		}
+	}
+	if bits < 64 && n>>(bits-1) != 0 && n>>(bits-1) != -1 {
+		return 0, fmt.Errorf("abidecode: int%d at offset %d overflows", bits, offset)
+	}
+	return n, nil
+}
+
+// BigUint decodes an unsigned integer of any size
+func BigUint(data []byte, offset int) (*big.Int, error) {
+	word, err := Word(data, offset)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(word), nil
+}
+
+// BigInt decodes a two's complement signed integer of any size
+func BigInt(data []byte, offset int) (*big.Int, error) {
+	word, err := Word(data, offset)
+	if err != nil {
+		return nil, err
+	}
+	n := new(big.Int).SetBytes(word)
+	if word[0]&0x80 != 0 {
+		n.Sub(n, twoTo256)
+	}
+	return n, nil
+}
+
+var twoTo256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// Bool decodes a bool
+func Bool(data []byte, offset int) (bool, error) {
+	n, err := Uint(data, offset, 1)
+	if err != nil {
+		return false, fmt.Errorf("abidecode: invalid bool at offset %d", offset)
+	}
+	return n == 1, nil
+}
+
+// Address decodes an address
+func Address(data []byte, offset int) (common.Address, error) {
+	word, err := Word(data, offset)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.BytesToAddress(word[WordSize-common.AddressLength:]), nil
+}
+
+// FixedBytes returns the first size bytes of the word at offset, for decoding
+// bytes1 to bytes32 values
+func FixedBytes(data []byte, offset, size int) ([]byte, error) {
+	word, err := Word(data, offset)
+	if err != nil {
+		return nil, err
+	}
+	return word[:size], nil
+}
+
+// Bytes decodes a dynamic bytes value. The returned slice aliases data.
+func Bytes(data []byte, offset int) ([]byte, error) {
+	start, n, err := dynamic(data, offset)
+	if err != nil {
+		return nil, err
+	}
+	if n > len(data)-start {
+		return nil, fmt.Errorf("%w: %d bytes at offset %d of %d bytes", ErrShortData, n, start, len(data))
+	}
+	return data[start : start+n], nil
+}
+
+// String decodes a string
+func String(data []byte, offset int) (string, error) {
+	b, err := Bytes(data, offset)
+	return string(b), err
+}
+
+// Array locates a dynamic array and returns its length along with the data
+// of its elements, in which element i has its head word at offset
+// i*WordSize.
+func Array(data []byte, offset int) ([]byte, int, error) {
+	start, n, err := dynamic(data, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if n > (len(data)-start)/WordSize {
+		return nil, 0, fmt.Errorf("%w: %d array elements at offset %d of %d bytes", ErrShortData, n, start, len(data))
+	}
+	return data[start:], n, nil
+}
+
+// dynamic follows the offset stored in the head word at offset and returns
+// the position after the length word it points to, along with that length
+func dynamic(data []byte, offset int) (int, int, error) {
+	pointer, err := Uint(data, offset, 63)
+	if err != nil {
+		return 0, 0, err
+	}
+	length, err := Uint(data, int(pointer), 63)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(pointer) + WordSize, int(length), nil
+}
+
+func beUint64(b []byte) uint64 {
+	return uint64(b[0])<<56 | uint64(b[1])<<48 | uint64(b[2])<<40 | uint64(b[3])<<32 |
+		uint64(b[4])<<24 | uint64(b[5])<<16 | uint64(b[6])<<8 | uint64(b[7])
+}
@@ -0,0 +1,101 @@
+package abidecode_test
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/john-na4/multicall3/go/abidecode"
+)
+
+func arguments(t *testing.T, types ...string) abi.Arguments {
+	t.Helper()
+	args := make(abi.Arguments, len(types))
+	for i, name := range types {
+		typ, err := abi.NewType(name, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args[i] = abi.Argument{Type: typ}
+	}
+	return args
+}
+
+func TestDecode(t *testing.T) {
+	args := arguments(t, "uint8", "int24", "uint256", "int256", "bool", "address", "bytes4", "bytes", "string", "uint256[]")
+	address := common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	huge := new(big.Int).Lsh(big.NewInt(1), 255)
+	data, err := args.Pack(uint8(200), big.NewInt(-8388608), huge, big.NewInt(-5), true, address,
+		[4]byte{1, 2, 3, 4}, []byte{9, 8, 7}, "Dai Stablecoin", []*big.Int{big.NewInt(1), big.NewInt(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := abidecode.Uint(data, 0, 8); err != nil || n != 200 {
+		t.Errorf("got uint8 %d, %v, want 200", n, err)
+	}
+	if n, err := abidecode.Int(data, 32, 24); err != nil || n != -8388608 {
+		t.Errorf("got int24 %d, %v, want -8388608", n, err)
+	}
+	if n, err := abidecode.BigUint(data, 64); err != nil || n.Cmp(huge) != 0 {
+		t.Errorf("got uint256 %s, %v, want %s", n, err, huge)
+	}
+	if n, err := abidecode.BigInt(data, 96); err != nil || n.Int64() != -5 {
+		t.Errorf("got int256 %s, %v, want -5", n, err)
+	}
+	if b, err := abidecode.Bool(data, 128); err != nil || !b {
+		t.Errorf("got bool %t, %v, want true", b, err)
+	}
+	if a, err := abidecode.Address(data, 160); err != nil || a != address {
+		t.Errorf("got address %s, %v, want %s", a, err, address)
+	}
+	if b, err := abidecode.FixedBytes(data, 192, 4); err != nil || !bytes.Equal(b, []byte{1, 2, 3, 4}) {
+		t.Errorf("got bytes4 %x, %v, want 01020304", b, err)
+	}
+	if b, err := abidecode.Bytes(data, 224); err != nil || !bytes.Equal(b, []byte{9, 8, 7}) {
+		t.Errorf("got bytes %x, %v, want 090807", b, err)
+	}
+	if s, err := abidecode.String(data, 256); err != nil || s != "Dai Stablecoin" {
+		t.Errorf("got string %q, %v, want Dai Stablecoin", s, err)
+	}
+	elems, n, err := abidecode.Array(data, 288)
+	if err != nil || n != 2 {
+		t.Fatalf("got %d array elements, %v, want 2", n, err)
+	}
+	for i := 0; i < n; i++ {
+		if elem, err := abidecode.BigUint(elems, i*abidecode.WordSize); err != nil || elem.Int64() != int64(i+1) {
+			t.Errorf("got element %d %s, %v, want %d", i, elem, err, i+1)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	data, err := arguments(t, "uint256", "int256", "bytes").Pack(big.NewInt(256), big.NewInt(-129), []byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := abidecode.Uint(data, 0, 8); err == nil {
+		t.Error("256 decoded as a uint8")
+	}
+	if _, err := abidecode.Int(data, 32, 8); err == nil {
+		t.Error("-129 decoded as an int8")
+	}
+	if _, err := abidecode.Bool(data, 0); err == nil {
+		t.Error("256 decoded as a bool")
+	}
+	if _, err := abidecode.Word(data, len(data)-1); !errors.Is(err, abidecode.ErrShortData) {
+		t.Errorf("got error %v reading past the end, want ErrShortData", err)
+	}
+	// The bytes claim to be longer than the data left
+	truncated := data[:len(data)-abidecode.WordSize]
+	if _, err := abidecode.Bytes(truncated, 64); !errors.Is(err, abidecode.ErrShortData) {
+		t.Errorf("got error %v for truncated bytes, want ErrShortData", err)
+	}
+	if _, _, err := abidecode.Array(truncated, 64); !errors.Is(err, abidecode.ErrShortData) {
+		t.Errorf("got error %v for a truncated array, want ErrShortData", err)
+	}
+}
@@ -0,0 +1,183 @@
+// Package abifetch fetches the ABIs of verified contracts from block
+// explorers, so that calls to arbitrary contracts can be packed and their
+// return data and errors decoded without bundling their ABIs:
+//
+//	fetcher := abifetch.New([]abifetch.Source{
+//		&abifetch.Sourcify{},
+//		&abifetch.Etherscan{APIKey: apiKey},
+//	}, abifetch.WithCacheDir(cacheDir))
+//	vaultABI, err := fetcher.ABI(ctx, chainID, vault)
+//	values, err := mc.NewBatch().Add(vault, vaultABI, "totalAssets").Execute(ctx)
+package abifetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrNotVerified is returned by sources that have no verified ABI for a
+// contract
+var ErrNotVerified = errors.New("abifetch: contract not verified")
+
+// Source fetches the JSON ABI of a verified contract
+type Source interface {
+	FetchABI(ctx context.Context, chainID uint64, address common.Address) ([]byte, error)
+}
+
+// cacheKey identifies a contract
+type cacheKey struct {
+	chainID uint64
+	address common.Address
+}
+
+// Fetcher fetches ABIs from its sources in order, caching them in memory and
+// optionally on disk. Verified ABIs do not change, so they are cached for
+// good. It is safe for concurrent use.
+type Fetcher struct {
+	sources  []Source
+	cacheDir string
+
+	mu    sync.Mutex
+	cache map[cacheKey]abi.ABI
+}
+
+// Option configures a Fetcher
+type Option func(*Fetcher)
+
+// WithCacheDir makes the fetcher store the ABIs it fetches in dir, one file
+// per contract, and look them up there before querying its sources, so they
+// are only fetched once across runs
+func WithCacheDir(dir string) Option {
+	return func(f *Fetcher) {
+		f.cacheDir = dir
+	}
+}
+
+// New returns a Fetcher that tries sources in order until one has the ABI
+func New(sources []Source, opts ...Option) *Fetcher {
+	f := &Fetcher{
+		sources: sources,
+		cache:   make(map[cacheKey]abi.ABI),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// ABI returns the ABI of the verified contract at address on the chain with
+// the given ID. It fails with ErrNotVerified if none of the sources has it.
+func (f *Fetcher) ABI(ctx context.Context, chainID uint64, address common.Address) (abi.ABI, error) {
+	key := cacheKey{chainID: chainID, address: address}
+	f.mu.Lock()
+	contractABI, ok := f.cache[key]
+	f.mu.Unlock()
+	if ok {
+		return contractABI, nil
+	}
+
+	data, err := f.readCache(key)
+	if err != nil {
+		return abi.ABI{}, err
+	}
+	cached := data != nil
+	if !cached {
+		if data, err = f.fetch(ctx, chainID, address); err != nil {
+			return abi.ABI{}, err
+		}
+	}
+	contractABI, err = abi.JSON(strings.NewReader(string(data)))
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("abifetch: parse ABI of %s on chain %d: %w", address, chainID, err)
+	}
+	if !cached {
+		if err := f.writeCache(key, data); err != nil {
+			return abi.ABI{}, err
+		}
+	}
+
+	f.mu.Lock()
+	f.cache[key] = contractABI
+	f.mu.Unlock()
+	return contractABI, nil
+}
+
+// fetch queries the sources in order for the JSON ABI of a contract
+func (f *Fetcher) fetch(ctx context.Context, chainID uint64, address common.Address) ([]byte, error) {
+	var errs []error
+	for _, source := range f.sources {
+		data, err := source.FetchABI(ctx, chainID, address)
+		if err == nil {
+			return data, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !errors.Is(err, ErrNotVerified) {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("abifetch: fetch ABI of %s on chain %d: %w", address, chainID, errors.Join(errs...))
+	}
+	return nil, fmt.Errorf("%w: %s on chain %d", ErrNotVerified, address, chainID)
+}
+
+// cachePath returns the file the ABI of a contract is cached in
+func (f *Fetcher) cachePath(key cacheKey) string {
+	return filepath.Join(f.cacheDir, strconv.FormatUint(key.chainID, 10), key.address.Hex()+".json")
+}
+
+// readCache returns the JSON ABI cached on disk for a contract, or nil if
+// there is none
+func (f *Fetcher) readCache(key cacheKey) ([]byte, error) {
+	if f.cacheDir == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(f.cachePath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("abifetch: read cache: %w", err)
+	}
+	return data, nil
+}
+
+// writeCache caches the JSON ABI of a contract on disk. The file is written
+// to a temporary file first, so concurrent readers never see it partially
+// written.
+func (f *Fetcher) writeCache(key cacheKey, data []byte) error {
+	if f.cacheDir == "" {
+		return nil
+	}
+	path := f.cachePath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("abifetch: write cache: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".abi-*")
+	if err != nil {
+		return fmt.Errorf("abifetch: write cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("abifetch: write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("abifetch: write cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("abifetch: write cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package abifetch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultEtherscanURL is the URL of the Etherscan API, which serves every
+// chain Etherscan supports
+const DefaultEtherscanURL = "https://api.etherscan.io/v2/api"
+
+// Etherscan fetches ABIs from an Etherscan-compatible API, such as Etherscan
+// itself or Blockscout
+type Etherscan struct {
+	// URL is the API endpoint, DefaultEtherscanURL if empty. The chain ID is
+	// sent as the chainid parameter, which single-chain explorers ignore.
+	URL string
+	// APIKey is sent as the apikey parameter if set
+	APIKey string
+	// Client sends the requests, http.DefaultClient if nil
+	Client *http.Client
+}
+
+// etherscanResponse is the body of Etherscan API responses
+type etherscanResponse struct {
+	Status string `json:"status"`
+	// Message is "OK" on success, and Result holds the ABI as a string on
+	// success and the reason of the failure otherwise
+	Message string `json:"message"`
+	Result  string `json:"result"`
+}
+
+// FetchABI fetches the ABI of a contract with the getabi action of the API
+func (e *Etherscan) FetchABI(ctx context.Context, chainID uint64, address common.Address) ([]byte, error) {
+	endpoint := e.URL
+	if endpoint == "" {
+		endpoint = DefaultEtherscanURL
+	}
+	query := url.Values{
+		"chainid": {strconv.FormatUint(chainID, 10)},
+		"module":  {"contract"},
+		"action":  {"getabi"},
+		"address": {address.Hex()},
+	}
+	if e.APIKey != "" {
+		query.Set("apikey", e.APIKey)
+	}
+	var body etherscanResponse
+	if err := getJSON(ctx, e.Client, endpoint+"?"+query.Encode(), &body); err != nil {
+		return nil, fmt.Errorf("abifetch: etherscan: %w", err)
+	}
+	if body.Status != "1" {
+		if strings.Contains(strings.ToLower(body.Result), "not verified") {
+			return nil, ErrNotVerified
+		}
+		return nil, fmt.Errorf("abifetch: etherscan: %s: %s", body.Message, body.Result)
+	}
+	return []byte(body.Result), nil
+}
+
+// DefaultSourcifyURL is the URL of the public Sourcify server
+const DefaultSourcifyURL = "https://sourcify.dev/server"
+
+// Sourcify fetches ABIs from a Sourcify server
+type Sourcify struct {
+	// URL is the server's URL, DefaultSourcifyURL if empty
+	URL string
+	// Client sends the requests, http.DefaultClient if nil
+	Client *http.Client
+}
+
+// FetchABI fetches the ABI of a contract from the contract lookup of the
+// server's v2 API
+func (s *Sourcify) FetchABI(ctx context.Context, chainID uint64, address common.Address) ([]byte, error) {
+	server := strings.TrimSuffix(s.URL, "/")
+	if server == "" {
+		server = DefaultSourcifyURL
+	}
+	var body struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	err := getJSON(ctx, s.Client, fmt.Sprintf("%s/v2/contract/%d/%s?fields=abi", server, chainID, address.Hex()), &body)
+	if statusErr, ok := err.(*statusError); ok && statusErr.code == http.StatusNotFound {
+		return nil, ErrNotVerified
+	}
+	if err != nil {
+		return nil, fmt.Errorf("abifetch: sourcify: %w", err)
+	}
+	if len(body.ABI) == 0 || string(body.ABI) == "null" {
+		return nil, ErrNotVerified
+	}
+	return body.ABI, nil
+}
+
+// statusError reports an unexpected HTTP status
+type statusError struct {
+	code   int
+	status string
+}
+
+func (e *statusError) Error() string {
+	return e.status
+}
+
+// maxResponseSize bounds the size of the responses of ABI sources, which are
+// untrusted, well above that of the largest verified ABIs
+const maxResponseSize = 16 << 20
+
+// getJSON decodes the JSON body of a GET request to url into out
+func getJSON(ctx context.Context, client *http.Client, url string, out interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return redactURL(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return redactURL(err)
+	}
+	defer resp.Body.Close()
+	body := io.LimitReader(resp.Body, maxResponseSize)
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, body)
+		return &statusError{code: resp.StatusCode, status: resp.Status}
+	}
+	return json.NewDecoder(body).Decode(out)
+}
+
+// redactURL hides the API key in the URL of err, which would otherwise end up
+// in the logs of callers
+func redactURL(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	parsed, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		return &url.Error{Op: urlErr.Op, URL: "<redacted>", Err: urlErr.Err}
+	}
+	query := parsed.Query()
+	if query.Has("apikey") {
+		query.Set("apikey", "REDACTED")
+		parsed.RawQuery = query.Encode()
+	}
+	return &url.Error{Op: urlErr.Op, URL: parsed.String(), Err: urlErr.Err}
+}
@@ -0,0 +1,102 @@
+// Package balancer reads the tokens, balances and swap fees of Balancer V2
+// pools in bulk through Multicall3, from the Vault that holds their tokens:
+//
+//	pools, err := balancer.Pools(ctx, client, balancer.Vault, []common.Hash{wethDAIPoolID})
+//	for _, pool := range pools {
+//		fmt.Println(pool.Tokens, pool.Balances, pool.SwapFee)
+//	}
+package balancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// Vault is the address of the Balancer V2 Vault, the same on every chain it
+// is deployed to
+var Vault = common.HexToAddress("0xBA12222222228d8Ba445958a75a0704d566BF2C8")
+
+// VaultABI is the part of the Balancer V2 Vault ABI used by the helpers
+var VaultABI = helper.MustParseABI("balancer", vaultABI)
+
+// PoolABI is the part of the Balancer V2 pool ABI used by the helpers
+var PoolABI = helper.MustParseABI("balancer", poolABI)
+
+const vaultABI = `[
+	{"type":"function","name":"getPoolTokens","stateMutability":"view","inputs":[{"name":"poolId","type":"bytes32"}],"outputs":[{"name":"tokens","type":"address[]"},{"name":"balances","type":"uint256[]"},{"name":"lastChangeBlock","type":"uint256"}]}
+]`
+
+const poolABI = `[
+	{"type":"function","name":"getSwapFeePercentage","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// PoolAddress returns the address of a pool, which makes up the first 20
+// bytes of its ID
+func PoolAddress(id common.Hash) common.Address {
+	return common.BytesToAddress(id[:common.AddressLength])
+}
+
+// poolTokens are the outputs of getPoolTokens
+type poolTokens struct {
+	Tokens          []common.Address
+	Balances        []*big.Int
+	LastChangeBlock *big.Int
+}
+
+// Pool is the state of a pool. Fields the pool or the Vault failed to return
+// are left as zero values, and Err reports why.
+type Pool struct {
+	ID      common.Hash
+	Address common.Address
+	// Tokens are the tokens of the pool, which for composable pools include
+	// the pool's own token
+	Tokens   []common.Address
+	Balances []*big.Int
+	// LastChangeBlock is the last block the balances of the pool changed in
+	LastChangeBlock uint64
+	// SwapFee is the swap fee of the pool as a fraction scaled by 1e18
+	SwapFee *big.Int
+	// Err joins the errors of reading the tokens of the pool from the vault
+	// and its swap fee from the pool
+	Err error
+}
+
+// Pools fetches the tokens and balances of every pool from vault, and the
+// swap fee of every pool from the pool itself, in a single batch, returning
+// them in the order of the pool IDs. The error is only set if the batch
+// itself failed, the failures of a pool are reported by its Pool.Err.
+func Pools(ctx context.Context, client *multicall.Client, vault common.Address, ids []common.Hash, opts ...multicall.CallOption) ([]Pool, error) {
+	batch := client.NewBatch()
+	tokens := make([]*multicall.CallOf[poolTokens], len(ids))
+	for i, id := range ids {
+		tokens[i] = multicall.View[poolTokens](VaultABI, "getPoolTokens", id)
+		batch.AddCall(vault, tokens[i]).AllowFailure().
+			Add(PoolAddress(id), PoolABI, "getSwapFeePercentage").AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	pools := make([]Pool, len(ids))
+	for i, id := range ids {
+		pools[i] = Pool{ID: id, Address: PoolAddress(id)}
+		tokensErr := results[2*i].Error(vault, fmt.Sprintf("getPoolTokens(%s)", id))
+		if tokensErr == nil {
+			t := tokens[i].Value()
+			pools[i].Tokens, pools[i].Balances = t.Tokens, t.Balances
+			pools[i].LastChangeBlock = t.LastChangeBlock.Uint64()
+		}
+		feeErr := results[2*i+1].Error(pools[i].Address, "getSwapFeePercentage")
+		if feeErr == nil {
+			pools[i].SwapFee = results[2*i+1].Values[0].(*big.Int)
+		}
+		pools[i].Err = errors.Join(tokensErr, feeErr)
+	}
+	return pools, nil
+}
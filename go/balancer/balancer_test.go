@@ -0,0 +1,58 @@
+package balancer_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/balancer"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	// The ID of a pool starts with its address
+	poolID    = common.HexToHash("0x5c6ee304399dbdb9c8ef030ab642b10820db8f56000200000000000000000014")
+	unknownID = common.HexToHash("0x00000000000000000000000000000000000000bb000200000000000000000015")
+	bal       = common.HexToAddress("0xba100000625a3754423978a60c9317c58a424e3D")
+	weth      = common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+)
+
+func TestPools(t *testing.T) {
+	balances := []*big.Int{big.NewInt(8e18), big.NewInt(2e18)}
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		balancer.Vault: fake.Methods(balancer.VaultABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getPoolTokens": func(args []interface{}) ([]interface{}, error) {
+				if args[0].([32]byte) != poolID {
+					return nil, &fake.Revert{}
+				}
+				return []interface{}{[]common.Address{bal, weth}, balances, big.NewInt(17_000_000)}, nil
+			},
+		}),
+		balancer.PoolAddress(poolID): fake.Methods(balancer.PoolABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getSwapFeePercentage": fake.Returns(big.NewInt(1e16)),
+		}),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pools, err := balancer.Pools(context.Background(), client, balancer.Vault, []common.Hash{poolID, unknownID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := pools[0]
+	if got.Err != nil {
+		t.Fatal(got.Err)
+	}
+	if got.Address != common.HexToAddress("0x5c6Ee304399DBdB9C8Ef030aB642B10820DB8F56") || !reflect.DeepEqual(got.Tokens, []common.Address{bal, weth}) ||
+		!reflect.DeepEqual(got.Balances, balances) || got.LastChangeBlock != 17_000_000 || got.SwapFee.Int64() != 1e16 {
+		t.Errorf("got pool %+v", got)
+	}
+	// The vault reverts for unknown pools, and the address has no code
+	if got := pools[1]; got.Tokens != nil || got.SwapFee != nil || !errors.Is(got.Err, multicall.ErrExecutionReverted) || !errors.Is(got.Err, multicall.ErrDecode) {
+		t.Errorf("got pool %+v, want the errors of both calls", got)
+	}
+}
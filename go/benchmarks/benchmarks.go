@@ -0,0 +1,43 @@
+// Package benchmarks measures the performance of the multicall package.
+//
+// The benchmarks are exported as plain functions rather than living in test
+// files, so they can be run against any build with the multicall-bench
+// command:
+//
+//	go run ./benchmarks/cmd/multicall-bench -run Encoding
+//
+// They also run with go test, named without the group they belong to:
+//
+//	go test -run '^$' -bench Encoding/Append ./benchmarks
+package benchmarks
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+// Benchmark is a named benchmark function
+type Benchmark struct {
+	Name string
+	F    func(b *testing.B)
+}
+
+// All returns every benchmark in the package
+func All() []Benchmark {
+	benchmarks := append(Encoding(), Decoding()...)
+	return append(benchmarks, Strategies()...)
+}
+
+// Run runs the benchmarks whose name matches filter, or all of them if filter
+// is nil, and writes their results to w in the format of go test -bench.
+func Run(w io.Writer, benchmarks []Benchmark, filter *regexp.Regexp) {
+	for _, bm := range benchmarks {
+		if filter != nil && !filter.MatchString(bm.Name) {
+			continue
+		}
+		result := testing.Benchmark(bm.F)
+		fmt.Fprintf(w, "%-40s %s %s\n", bm.Name, result, result.MemString())
+	}
+}
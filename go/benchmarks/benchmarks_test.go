@@ -0,0 +1,23 @@
+package benchmarks
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func init() {
+	flag.DurationVar(&Latency, "latency", 0, "simulated round trip time of requests in the strategy benchmarks")
+}
+
+func BenchmarkEncoding(b *testing.B)   { run(b, Encoding()) }
+func BenchmarkDecoding(b *testing.B)   { run(b, Decoding()) }
+func BenchmarkStrategies(b *testing.B) { run(b, Strategies()) }
+
+// run runs benchmarks as sub-benchmarks of b, named without their group
+func run(b *testing.B, benchmarks []Benchmark) {
+	for _, bm := range benchmarks {
+		_, name, _ := strings.Cut(bm.Name, "/")
+		b.Run(name, bm.F)
+	}
+}
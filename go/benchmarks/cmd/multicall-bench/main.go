@@ -0,0 +1,27 @@
+// Command multicall-bench runs the benchmarks of the multicall package and
+// reports their time and memory use per operation.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"regexp"
+
+	"github.com/john-na4/multicall3/go/benchmarks"
+)
+
+func main() {
+	run := flag.String("run", "", "only run benchmarks matching this regular expression")
+	flag.DurationVar(&benchmarks.Latency, "latency", 0, "simulated round trip time of requests in the strategy benchmarks")
+	flag.Parse()
+
+	var filter *regexp.Regexp
+	if *run != "" {
+		var err error
+		if filter, err = regexp.Compile(*run); err != nil {
+			log.Fatalf("invalid -run pattern: %v", err)
+		}
+	}
+	benchmarks.Run(os.Stdout, benchmarks.All(), filter)
+}
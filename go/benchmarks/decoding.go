@@ -0,0 +1,70 @@
+package benchmarks
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	"github.com/john-na4/multicall3/go/abidecode"
+)
+
+const reservesABI = `[{"type":"function","name":"getReserves","stateMutability":"view","inputs":[],"outputs":[{"name":"reserve0","type":"uint112"},{"name":"reserve1","type":"uint112"},{"name":"blockTimestampLast","type":"uint32"}]}]`
+
+// reserves is what multicall-gen generates for getReserves
+type reserves struct {
+	Reserve0           *big.Int
+	Reserve1           *big.Int
+	BlockTimestampLast uint32
+}
+
+// Decoding returns benchmarks comparing reflection based unpacking of the
+// return data of a Uniswap V2 style getReserves call with direct decoding
+// through abidecode, as done by decoders generated with multicall-gen.
+func Decoding() []Benchmark {
+	parsed, err := abi.JSON(strings.NewReader(reservesABI))
+	if err != nil {
+		panic(err)
+	}
+	data, err := parsed.Methods["getReserves"].Outputs.Pack(big.NewInt(1e18), big.NewInt(3e9), uint32(1700000000))
+	if err != nil {
+		panic(err)
+	}
+	return []Benchmark{
+		{"Decoding/UnpackIntoInterface", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var out reserves
+				if err := parsed.UnpackIntoInterface(&out, "getReserves", data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}},
+		{"Decoding/Generated", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := decodeReserves(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}},
+	}
+}
+
+func decodeReserves(data []byte) (reserves, error) {
+	var out reserves
+	var err error
+	if out.Reserve0, err = abidecode.BigUint(data, 0); err != nil {
+		return reserves{}, err
+	}
+	if out.Reserve1, err = abidecode.BigUint(data, 32); err != nil {
+		return reserves{}, err
+	}
+	timestamp, err := abidecode.Uint(data, 64, 32)
+	if err != nil {
+		return reserves{}, err
+	}
+	out.BlockTimestampLast = uint32(timestamp)
+	return out, nil
+}
@@ -0,0 +1,106 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// encodingSizes are the batch sizes the encoding benchmarks are run with
+var encodingSizes = []int{10, 100, 1000}
+
+// Encoding returns benchmarks comparing the reflection based ABI.Pack with the
+// direct aggregate3 encoder, with and without buffer reuse, both on their own
+// and as part of a full Aggregate3 round trip against a canned response.
+func Encoding() []Benchmark {
+	var benchmarks []Benchmark
+	for _, n := range encodingSizes {
+		calls := erc20Calls(n)
+		benchmarks = append(benchmarks,
+			Benchmark{fmt.Sprintf("Encoding/Pack/%d", n), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := multicall.ABI.Pack("aggregate3", calls); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}},
+			Benchmark{fmt.Sprintf("Encoding/Append/%d", n), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					multicall.AppendAggregate3(nil, calls)
+				}
+			}},
+			Benchmark{fmt.Sprintf("Encoding/AppendReused/%d", n), func(b *testing.B) {
+				b.ReportAllocs()
+				var buf []byte
+				for i := 0; i < b.N; i++ {
+					buf = multicall.AppendAggregate3(buf[:0], calls)
+				}
+			}},
+			aggregate3Benchmark(fmt.Sprintf("Encoding/Aggregate3/%d", n), calls),
+			aggregate3Benchmark(fmt.Sprintf("Encoding/Aggregate3Pooled/%d", n), calls, multicall.WithPooledBuffers()),
+		)
+	}
+	return benchmarks
+}
+
+func aggregate3Benchmark(name string, calls []multicall.Call3, opts ...multicall.Option) Benchmark {
+	return Benchmark{name, func(b *testing.B) {
+		client, err := multicall.NewClient(newCannedCaller(len(calls)), opts...)
+		if err != nil {
+			b.Fatal(err)
+		}
+		ctx := context.Background()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := client.Aggregate3(ctx, calls); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}}
+}
+
+// erc20Calls returns n balanceOf calls on distinct holders of a token
+func erc20Calls(n int) []multicall.Call3 {
+	token := common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	calls := make([]multicall.Call3, n)
+	for i := range calls {
+		data := append([]byte{0x70, 0xa0, 0x82, 0x31}, common.LeftPadBytes(big.NewInt(int64(i+1)).Bytes(), 32)...)
+		calls[i] = multicall.Call3{Target: token, AllowFailure: true, CallData: data}
+	}
+	return calls
+}
+
+// cannedCaller answers every call with the same aggregate3 output of n
+// successful results, so benchmarks measure the client rather than a node
+type cannedCaller struct {
+	output []byte
+}
+
+func newCannedCaller(n int) *cannedCaller {
+	results := make([]multicall.Result, n)
+	for i := range results {
+		results[i] = multicall.Result{Success: true, ReturnData: common.LeftPadBytes(big.NewInt(1e18).Bytes(), 32)}
+	}
+	output, err := multicall.ABI.Methods["aggregate3"].Outputs.Pack(results)
+	if err != nil {
+		panic(err)
+	}
+	return &cannedCaller{output: output}
+}
+
+func (c *cannedCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0}, nil
+}
+
+func (c *cannedCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return c.output, nil
+}
@@ -0,0 +1,93 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/john-na4/multicall3/go/bindings"
+)
+
+// node is an in-process JSON-RPC endpoint serving eth_call from a simulated
+// chain with Multicall3 deployed, reached over HTTP like a real provider
+type node struct {
+	server    *httptest.Server
+	multicall common.Address
+}
+
+// newNode starts a node that delays every HTTP request by latency, to stand
+// in for the round trip to a remote provider
+func newNode(latency time.Duration) (*node, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		return nil, err
+	}
+	balance := new(big.Int).Lsh(big.NewInt(1), 100)
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{auth.From: {Balance: balance}}, 30_000_000)
+	address, _, _, err := bindings.DeployMulticall3(auth, sim)
+	if err != nil {
+		return nil, fmt.Errorf("deploy Multicall3: %w", err)
+	}
+	sim.Commit()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &ethService{sim: sim}); err != nil {
+		return nil, err
+	}
+	handler := http.Handler(server)
+	if latency > 0 {
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(latency)
+			server.ServeHTTP(w, r)
+		})
+	}
+	return &node{server: httptest.NewServer(handler), multicall: address}, nil
+}
+
+func (n *node) URL() string {
+	return n.server.URL
+}
+
+func (n *node) Close() {
+	n.server.Close()
+}
+
+// ethService implements the subset of the eth namespace used by the benchmarks
+type ethService struct {
+	sim *backends.SimulatedBackend
+}
+
+type callArgs struct {
+	From  *common.Address `json:"from"`
+	To    *common.Address `json:"to"`
+	Value *hexutil.Big    `json:"value"`
+	Input hexutil.Bytes   `json:"input"`
+	Data  hexutil.Bytes   `json:"data"`
+}
+
+func (s *ethService) Call(ctx context.Context, args callArgs, block string) (hexutil.Bytes, error) {
+	msg := ethereum.CallMsg{To: args.To, Data: args.Input, Value: (*big.Int)(args.Value)}
+	if args.From != nil {
+		msg.From = *args.From
+	}
+	if msg.Data == nil {
+		msg.Data = args.Data
+	}
+	return s.sim.CallContract(ctx, msg, nil)
+}
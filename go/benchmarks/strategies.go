@@ -0,0 +1,122 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// Latency is the simulated round trip time of every request to the node the
+// strategy benchmarks run against. With no latency, the benchmarks mostly
+// measure encoding and execution overhead; with a realistic latency they show
+// the effect of the number of round trips.
+var Latency time.Duration
+
+// strategySizes are the batch sizes the strategy benchmarks are run with
+var strategySizes = []int{1, 10, 100, 500}
+
+// Strategies returns benchmarks reading the ETH balances of a batch of
+// accounts from an in-process node over HTTP using three strategies: one
+// eth_call per account, a single JSON-RPC batch of eth_calls, and a single
+// Multicall3 aggregate3 call. Each operation reads the whole batch.
+func Strategies() []Benchmark {
+	var benchmarks []Benchmark
+	for _, n := range strategySizes {
+		n := n
+		benchmarks = append(benchmarks,
+			strategyBenchmark(fmt.Sprintf("Strategies/CallLoop/%d", n), n, callLoop),
+			strategyBenchmark(fmt.Sprintf("Strategies/RPCBatch/%d", n), n, rpcBatch),
+			strategyBenchmark(fmt.Sprintf("Strategies/Multicall/%d", n), n, aggregate3),
+		)
+	}
+	return benchmarks
+}
+
+// strategy reads the results of calls, each of which targets Multicall3
+type strategy func(ctx context.Context, client *rpc.Client, multicall common.Address, calls [][]byte) error
+
+func strategyBenchmark(name string, n int, read strategy) Benchmark {
+	return Benchmark{name, func(b *testing.B) {
+		node, err := newNode(Latency)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer node.Close()
+		client, err := rpc.Dial(node.URL())
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer client.Close()
+
+		calls := make([][]byte, n)
+		for i := range calls {
+			account := common.BigToAddress(common.Big1)
+			account[0] = byte(i)
+			account[1] = byte(i >> 8)
+			if calls[i], err = multicall.ABI.Pack("getEthBalance", account); err != nil {
+				b.Fatal(err)
+			}
+		}
+		ctx := context.Background()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := read(ctx, client, node.multicall, calls); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}}
+}
+
+func callLoop(ctx context.Context, client *rpc.Client, target common.Address, calls [][]byte) error {
+	eth := ethclient.NewClient(client)
+	for _, data := range calls {
+		if _, err := eth.CallContract(ctx, ethereum.CallMsg{To: &target, Data: data}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rpcBatch(ctx context.Context, client *rpc.Client, target common.Address, calls [][]byte) error {
+	results := make([]hexutil.Bytes, len(calls))
+	batch := make([]rpc.BatchElem, len(calls))
+	for i, data := range calls {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []interface{}{map[string]interface{}{"to": target, "input": hexutil.Bytes(data)}, "latest"},
+			Result: &results[i],
+		}
+	}
+	if err := client.BatchCallContext(ctx, batch); err != nil {
+		return err
+	}
+	for _, elem := range batch {
+		if elem.Error != nil {
+			return elem.Error
+		}
+	}
+	return nil
+}
+
+func aggregate3(ctx context.Context, client *rpc.Client, target common.Address, calls [][]byte) error {
+	mc, err := multicall.NewClient(ethclient.NewClient(client), multicall.WithAddress(target))
+	if err != nil {
+		return err
+	}
+	call3s := make([]multicall.Call3, len(calls))
+	for i, data := range calls {
+		call3s[i] = multicall.Call3{Target: target, CallData: data}
+	}
+	_, err = mc.Aggregate3(ctx, call3s)
+	return err
+}
@@ -0,0 +1,13 @@
+// Package bindings contains go-ethereum contract bindings for Multicall3,
+// generated by abigen from the full Multicall3 ABI and creation bytecode.
+package bindings
+
+import "github.com/ethereum/go-ethereum/common"
+
+//go:generate abigen --abi Multicall3.abi --bin Multicall3.bin --pkg bindings --type Multicall3 --out multicall3.go
+
+// AddressHex is the canonical Multicall3 address, deployed on most EVM chains
+const AddressHex = "0xcA11bde05977b3631167028862bE2a173976CA11"
+
+// Address is AddressHex as a common.Address
+var Address = common.HexToAddress(AddressHex)
@@ -0,0 +1,84 @@
+package bindings_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/john-na4/multicall3/go/bindings"
+)
+
+func TestABI(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(bindings.Multicall3ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(parsed.Methods); n != 16 {
+		t.Errorf("got %d functions in the ABI, want 16", n)
+	}
+	if bindings.Address.Hex() != bindings.AddressHex {
+		t.Errorf("got address %s, want %s", bindings.Address.Hex(), bindings.AddressHex)
+	}
+}
+
+func TestDeployMulticall3(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance := big.NewInt(1e18)
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{auth.From: {Balance: balance}}, 30_000_000)
+	defer sim.Close()
+	address, _, multicall, err := bindings.DeployMulticall3(auth, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	chainID, err := multicall.GetChainId(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chainID.Int64() != 1337 {
+		t.Errorf("got chain ID %s, want 1337", chainID)
+	}
+	number, err := multicall.GetBlockNumber(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if number.Int64() != 1 {
+		t.Errorf("got block %s, want 1", number)
+	}
+
+	// aggregate3 is payable, so it is called through the raw binding
+	parsed, err := bindings.Multicall3MetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := parsed.Pack("getEthBalance", auth.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []interface{}
+	calls := []bindings.Multicall3Call3{{Target: address, CallData: data}}
+	if err := (&bindings.Multicall3Raw{Contract: multicall}).Call(nil, &out, "aggregate3", calls); err != nil {
+		t.Fatal(err)
+	}
+	results := *abi.ConvertType(out[0], new([]bindings.Multicall3Result)).(*[]bindings.Multicall3Result)
+	if len(results) != 1 || !results[0].Success {
+		t.Fatalf("got results %+v, want one successful call", results)
+	}
+	if got := new(big.Int).SetBytes(results[0].ReturnData); got.Sign() <= 0 || got.Cmp(balance) >= 0 {
+		t.Errorf("got balance %s of the deployer, want less than %s after deploying", got, balance)
+	}
+}
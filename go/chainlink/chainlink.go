@@ -0,0 +1,134 @@
+// Package chainlink reads Chainlink price feeds in bulk through Multicall3,
+// checking that every price is fresh against the timestamp of the block the
+// feeds were read at:
+//
+//	prices, err := chainlink.Prices(ctx, client, []chainlink.Feed{
+//		{Address: ethUSD, MaxAge: time.Hour},
+//		{Address: btcUSD, MaxAge: time.Hour},
+//	})
+//	for _, price := range prices {
+//		if price.Err == nil && !price.Stale {
+//			fmt.Println(price.Description, price.Float())
+//		}
+//	}
+package chainlink
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ABI is the part of the AggregatorV3Interface ABI used by the helpers
+var ABI = helper.MustParseABI("chainlink", aggregatorABI)
+
+const aggregatorABI = `[
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"description","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"latestRoundData","stateMutability":"view","inputs":[],"outputs":[{"name":"roundId","type":"uint80"},{"name":"answer","type":"int256"},{"name":"startedAt","type":"uint256"},{"name":"updatedAt","type":"uint256"},{"name":"answeredInRound","type":"uint80"}]}
+]`
+
+// Feed is a price feed to read, by the address of its aggregator or proxy
+type Feed struct {
+	Address common.Address
+	// MaxAge is how old the latest answer may be before it is stale,
+	// typically the heartbeat of the feed. Zero disables the check.
+	MaxAge time.Duration
+}
+
+// roundData are the outputs of latestRoundData
+type roundData struct {
+	RoundId         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}
+
+// Price is the latest answer of a feed. Fields the feed failed to return are
+// left as zero values, and Err reports why.
+type Price struct {
+	Feed        common.Address
+	Description string
+	Decimals    uint8
+	RoundID     *big.Int
+	// Answer is the price, scaled by 10^Decimals
+	Answer          *big.Int
+	StartedAt       time.Time
+	UpdatedAt       time.Time
+	AnsweredInRound *big.Int
+	// Age is how old the answer was at the block the feed was read at
+	Age time.Duration
+	// Stale reports whether the answer is older than the feed's MaxAge, was
+	// carried over from an earlier round or is not positive
+	Stale bool
+	// Err joins the errors of the latest round, decimals and description,
+	// such as for feeds that have no round yet
+	Err error
+}
+
+// Float returns the price as a decimal number, or nil if the feed failed to
+// return it
+func (p Price) Float() *big.Float {
+	if p.Answer == nil {
+		return nil
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(p.Decimals)), nil)
+	return new(big.Float).Quo(new(big.Float).SetInt(p.Answer), new(big.Float).SetInt(scale))
+}
+
+// Prices fetches the latest round data, decimals and description of every
+// feed in a single batch, along with the timestamp of the block they are read
+// at, returning them in the order of the feeds. The error is only set if the
+// batch itself failed, the failures of a feed are reported by its Price.Err.
+func Prices(ctx context.Context, client *multicall.Client, feeds []Feed, opts ...multicall.CallOption) ([]Price, error) {
+	timestamp := multicall.BlockTimestamp()
+	batch := client.NewBatch().AddSelf(timestamp)
+	rounds := make([]*multicall.CallOf[roundData], len(feeds))
+	for i, feed := range feeds {
+		rounds[i] = multicall.View[roundData](ABI, "latestRoundData")
+		batch.AddCall(feed.Address, rounds[i]).AllowFailure().
+			Add(feed.Address, ABI, "decimals").AllowFailure().
+			Add(feed.Address, ABI, "description").AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	blockTime, err := timestamp.Get()
+	if err != nil {
+		return nil, err
+	}
+	prices := make([]Price, len(feeds))
+	for i, feed := range feeds {
+		round, decimals, description := results[1+3*i], results[2+3*i], results[3+3*i]
+		prices[i] = Price{Feed: feed.Address}
+		roundErr := round.Error(feed.Address, "latestRoundData")
+		if roundErr == nil {
+			data := rounds[i].Value()
+			prices[i].RoundID = data.RoundId
+			prices[i].Answer = data.Answer
+			prices[i].StartedAt = time.Unix(data.StartedAt.Int64(), 0)
+			prices[i].UpdatedAt = time.Unix(data.UpdatedAt.Int64(), 0)
+			prices[i].AnsweredInRound = data.AnsweredInRound
+			prices[i].Age = time.Duration(blockTime.Int64()-data.UpdatedAt.Int64()) * time.Second
+			prices[i].Stale = feed.MaxAge > 0 && prices[i].Age > feed.MaxAge ||
+				data.AnsweredInRound.Cmp(data.RoundId) < 0 || data.Answer.Sign() <= 0
+		}
+		decimalsErr := decimals.Error(feed.Address, "decimals")
+		if decimalsErr == nil {
+			prices[i].Decimals = decimals.Values[0].(uint8)
+		}
+		descriptionErr := description.Error(feed.Address, "description")
+		if descriptionErr == nil {
+			prices[i].Description = description.Values[0].(string)
+		}
+		prices[i].Err = errors.Join(roundErr, decimalsErr, descriptionErr)
+	}
+	return prices, nil
+}
@@ -0,0 +1,71 @@
+package chainlink_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/chainlink"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	ethUSD = common.HexToAddress("0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419")
+	stale  = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	empty  = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+)
+
+// feed returns a feed whose latest round was answered with answer at updatedAt
+func feed(answer, updatedAt int64) fake.Contract {
+	return fake.Methods(chainlink.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+		"latestRoundData": fake.Returns(big.NewInt(7), big.NewInt(answer), big.NewInt(updatedAt), big.NewInt(updatedAt), big.NewInt(7)),
+		"decimals":        fake.Returns(uint8(8)),
+		"description":     fake.Returns("ETH / USD"),
+	})
+}
+
+func TestPrices(t *testing.T) {
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		multicall.Address: fake.Methods(multicall.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getCurrentBlockTimestamp": fake.Returns(big.NewInt(10000)),
+		}),
+		ethUSD: feed(200000000000, 9990),
+		stale:  feed(200000000000, 5000),
+		// A feed without rounds yet reverts latestRoundData
+		empty: fake.Methods(chainlink.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"decimals":    fake.Returns(uint8(8)),
+			"description": fake.Returns("ETH / USD"),
+		}),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	feeds := []chainlink.Feed{{Address: ethUSD, MaxAge: time.Hour}, {Address: stale, MaxAge: time.Hour}, {Address: empty}}
+	prices, err := chainlink.Prices(context.Background(), client, feeds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := prices[0]
+	if got.Err != nil {
+		t.Fatal(got.Err)
+	}
+	if got.Description != "ETH / USD" || got.Decimals != 8 || got.RoundID.Int64() != 7 || got.Age != 10*time.Second || got.Stale {
+		t.Errorf("got price %+v", got)
+	}
+	if price, _ := got.Float().Float64(); price != 2000 {
+		t.Errorf("got price %g, want 2000", price)
+	}
+	if !prices[1].Stale || prices[1].Age != 5000*time.Second {
+		t.Errorf("answer %s old is not stale", prices[1].Age)
+	}
+	if !errors.Is(prices[2].Err, multicall.ErrExecutionReverted) || prices[2].Answer != nil || prices[2].Float() != nil {
+		t.Errorf("got price %+v for a feed without rounds, want a reverted call", prices[2])
+	}
+	if prices[2].Decimals != 8 {
+		t.Errorf("got decimals %d for a feed without rounds, want 8", prices[2].Decimals)
+	}
+}
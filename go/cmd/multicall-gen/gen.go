@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// generator emits reflection-free decoders for the outputs of ABI methods
+type generator struct {
+	pkg     string
+	prefix  string
+	body    bytes.Buffer
+	imports map[string]bool
+	vars    int
+}
+
+// generate returns the formatted source of a file declaring a decoder for
+// each of methods in contractABI
+func generate(pkg, prefix string, contractABI abi.ABI, methods []string) ([]byte, error) {
+	g := &generator{pkg: pkg, prefix: prefix, imports: map[string]bool{}}
+	for _, name := range methods {
+		method, ok := contractABI.Methods[name]
+		if !ok {
+			return nil, fmt.Errorf("no method %q in ABI", name)
+		}
+		if len(method.Outputs) == 0 {
+			return nil, fmt.Errorf("method %q has no outputs", name)
+		}
+		if err := g.method(name, method); err != nil {
+			return nil, fmt.Errorf("method %q: %w", name, err)
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by multicall-gen. DO NOT EDIT.\n\npackage %s\n\n", g.pkg)
+	// Standard library imports come first, separated from the others
+	var std, other []string
+	for path := range g.imports {
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	out.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+	if len(std) > 0 && len(other) > 0 {
+		out.WriteString("\n")
+	}
+	for _, path := range other {
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+	out.WriteString(")\n\n")
+	out.Write(g.body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+// method emits the decoder of one method, along with a struct holding its
+// outputs if it has several
+func (g *generator) method(name string, method abi.Method) error {
+	g.imports["fmt"] = true
+	g.imports["github.com/john-na4/multicall3/go/abidecode"] = true
+	funcName := "Decode" + g.prefix + abi.ToCamelCase(name)
+
+	if len(method.Outputs) == 1 {
+		output := method.Outputs[0]
+		goType, zero, err := g.goType(output.Type)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.body, "// %s decodes the return data of %s\n", funcName, method.Sig)
+		fmt.Fprintf(&g.body, "func %s(data []byte) (%s, error) {\n", funcName, goType)
+		fmt.Fprintf(&g.body, "\tvar out %s\n", goType)
+		errReturn := fmt.Sprintf("return %s, fmt.Errorf(\"decode %s output: %%w\", err)", zero, name)
+		if err := g.decode(output.Type, "data", "0", "out", errReturn, 1); err != nil {
+			return err
+		}
+		g.body.WriteString("\treturn out, nil\n}\n\n")
+		return nil
+	}
+
+	typeName := g.prefix + abi.ToCamelCase(name) + "Output"
+	fmt.Fprintf(&g.body, "// %s holds the outputs of %s\n", typeName, method.Sig)
+	fmt.Fprintf(&g.body, "type %s struct {\n", typeName)
+	fields := make([]string, len(method.Outputs))
+	for i, output := range method.Outputs {
+		goType, _, err := g.goType(output.Type)
+		if err != nil {
+			return err
+		}
+		fields[i] = fieldName(output, i)
+		fmt.Fprintf(&g.body, "\t%s %s\n", fields[i], goType)
+	}
+	g.body.WriteString("}\n\n")
+
+	fmt.Fprintf(&g.body, "// %s decodes the return data of %s\n", funcName, method.Sig)
+	fmt.Fprintf(&g.body, "func %s(data []byte) (%s, error) {\n", funcName, typeName)
+	fmt.Fprintf(&g.body, "\tvar out %s\n", typeName)
+	for i, output := range method.Outputs {
+		errReturn := fmt.Sprintf("return %s{}, fmt.Errorf(\"decode %s output %s: %%w\", err)", typeName, name, fields[i])
+		offset := fmt.Sprint(i * 32)
+		if err := g.decode(output.Type, "data", offset, "out."+fields[i], errReturn, 1); err != nil {
+			return err
+		}
+	}
+	g.body.WriteString("\treturn out, nil\n}\n\n")
+	return nil
+}
+
+func fieldName(arg abi.Argument, i int) string {
+	if arg.Name == "" {
+		return fmt.Sprintf("Ret%d", i)
+	}
+	return abi.ToCamelCase(arg.Name)
+}
+
+// goType returns the Go type a value of typ is decoded into, the same as
+// abigen uses, along with its zero value
+func (g *generator) goType(typ abi.Type) (string, string, error) {
+	switch typ.T {
+	case abi.UintTy, abi.IntTy:
+		if nativeInt(typ.Size) {
+			prefix := "int"
+			if typ.T == abi.UintTy {
+				prefix = "uint"
+			}
+			return fmt.Sprintf("%s%d", prefix, typ.Size), "0", nil
+		}
+		g.imports["math/big"] = true
+		return "*big.Int", "nil", nil
+	case abi.BoolTy:
+		return "bool", "false", nil
+	case abi.AddressTy:
+		g.imports["github.com/ethereum/go-ethereum/common"] = true
+		return "common.Address", "common.Address{}", nil
+	case abi.FixedBytesTy:
+		return fmt.Sprintf("[%d]byte", typ.Size), fmt.Sprintf("[%d]byte{}", typ.Size), nil
+	case abi.BytesTy:
+		return "[]byte", "nil", nil
+	case abi.StringTy:
+		return "string", `""`, nil
+	case abi.SliceTy:
+		if typ.Elem.T == abi.SliceTy {
+			return "", "", fmt.Errorf("unsupported type %s: nested arrays", typ)
+		}
+		elem, _, err := g.goType(*typ.Elem)
+		if err != nil {
+			return "", "", err
+		}
+		return "[]" + elem, "nil", nil
+	}
+	return "", "", fmt.Errorf("unsupported type %s", typ)
+}
+
+func nativeInt(size int) bool {
+	return size == 8 || size == 16 || size == 32 || size == 64
+}
+
+// decode emits statements decoding the value of typ whose head is at offset
+// in data into dst, running errReturn if decoding fails
+func (g *generator) decode(typ abi.Type, data, offset, dst, errReturn string, depth int) error {
+	indent := strings.Repeat("\t", depth)
+	emit := func(format string, args ...interface{}) {
+		g.body.WriteString(indent)
+		fmt.Fprintf(&g.body, format, args...)
+		g.body.WriteString("\n")
+	}
+	check := func() {
+		emit("if err != nil {")
+		emit("\t%s", errReturn)
+		emit("}")
+	}
+	g.vars++
+	v := fmt.Sprintf("v%d", g.vars)
+
+	switch typ.T {
+	case abi.UintTy, abi.IntTy:
+		fn, big := "Uint", "BigUint"
+		if typ.T == abi.IntTy {
+			fn, big = "Int", "BigInt"
+		}
+		if !nativeInt(typ.Size) {
+			emit("%s, err := abidecode.%s(%s, %s)", v, big, data, offset)
+			check()
+			emit("%s = %s", dst, v)
+			return nil
+		}
+		goType, _, _ := g.goType(typ)
+		emit("%s, err := abidecode.%s(%s, %s, %d)", v, fn, data, offset, typ.Size)
+		check()
+		emit("%s = %s(%s)", dst, goType, v)
+	case abi.BoolTy, abi.AddressTy, abi.BytesTy, abi.StringTy:
+		fn := map[byte]string{abi.BoolTy: "Bool", abi.AddressTy: "Address", abi.BytesTy: "Bytes", abi.StringTy: "String"}[typ.T]
+		emit("%s, err := abidecode.%s(%s, %s)", v, fn, data, offset)
+		check()
+		if typ.T == abi.BytesTy {
+			// Copy so the decoded value does not alias the return data
+			emit("%s = append([]byte(nil), %s...)", dst, v)
+		} else {
+			emit("%s = %s", dst, v)
+		}
+	case abi.FixedBytesTy:
+		emit("%s, err := abidecode.FixedBytes(%s, %s, %d)", v, data, offset, typ.Size)
+		check()
+		emit("copy(%s[:], %s)", dst, v)
+	case abi.SliceTy:
+		goType, _, err := g.goType(typ)
+		if err != nil {
+			return err
+		}
+		elems, n, i := v+"elems", v+"n", v+"i"
+		emit("%s, %s, err := abidecode.Array(%s, %s)", elems, n, data, offset)
+		check()
+		emit("%s = make(%s, %s)", dst, goType, n)
+		emit("for %s := range %s {", i, dst)
+		if err := g.decode(*typ.Elem, elems, fmt.Sprintf("%s*abidecode.WordSize", i), fmt.Sprintf("%s[%s]", dst, i), errReturn, depth+1); err != nil {
+			return err
+		}
+		emit("}")
+	default:
+		return fmt.Errorf("unsupported type %s", typ)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Command multicall-gen generates decoders for the return data of contract
+// methods that do not use reflection, for decoding the results of large
+// multicalls quickly. Each method gets a Decode function taking the raw
+// return data, such as Result.ReturnData:
+//
+//	//go:generate multicall-gen -abi erc20.abi -pkg tokens -type ERC20 -methods balanceOf,decimals -out erc20_decode.go
+//
+// generates DecodeERC20BalanceOf and DecodeERC20Decimals. Methods with several
+// outputs are decoded into a generated struct. Integer, bool, address, bytes
+// and string outputs are supported, along with arrays of them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func main() {
+	abiPath := flag.String("abi", "", "path to the contract ABI JSON")
+	pkg := flag.String("pkg", "", "package name of the generated file")
+	prefix := flag.String("type", "", "name prepended to the generated identifiers")
+	methods := flag.String("methods", "", "comma separated methods to generate decoders for, defaults to all view and pure methods")
+	out := flag.String("out", "", "output file, defaults to standard output")
+	flag.Parse()
+
+	if err := run(*abiPath, *pkg, *prefix, *methods, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "multicall-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(abiPath, pkg, prefix, methods, out string) error {
+	if abiPath == "" || pkg == "" {
+		return fmt.Errorf("-abi and -pkg are required")
+	}
+	f, err := os.Open(abiPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	contractABI, err := abi.JSON(f)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", abiPath, err)
+	}
+
+	var names []string
+	if methods != "" {
+		names = strings.Split(methods, ",")
+	} else {
+		for name, method := range contractABI.Methods {
+			if method.IsConstant() && len(method.Outputs) > 0 {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+
+	src, err := generate(pkg, prefix, contractABI, names)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
@@ -0,0 +1,169 @@
+// Package compound reads the positions of Compound V2 accounts, and of the
+// accounts of its forks, in bulk through Multicall3, for liquidation
+// monitors:
+//
+//	accounts, err := compound.Accounts(ctx, client, comptroller, []common.Address{cETH, cUSDC}, borrowers)
+//	for _, account := range accounts {
+//		if account.Err == nil && account.Liquidatable() {
+//			fmt.Println(account.Address, account.Shortfall)
+//		}
+//	}
+package compound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ComptrollerABI is the part of the Compound V2 Comptroller ABI used by the
+// helpers
+var ComptrollerABI = helper.MustParseABI("compound", comptrollerABI)
+
+// CTokenABI is the part of the Compound V2 cToken ABI used by the helpers
+var CTokenABI = helper.MustParseABI("compound", cTokenABI)
+
+const comptrollerABI = `[
+	{"type":"function","name":"getAccountLiquidity","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"error","type":"uint256"},{"name":"liquidity","type":"uint256"},{"name":"shortfall","type":"uint256"}]}
+]`
+
+const cTokenABI = `[
+	{"type":"function","name":"getAccountSnapshot","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"error","type":"uint256"},{"name":"cTokenBalance","type":"uint256"},{"name":"borrowBalance","type":"uint256"},{"name":"exchangeRateMantissa","type":"uint256"}]}
+]`
+
+// mantissa is 1e18, the scale of exchange rates
+var mantissa = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// accountLiquidity are the outputs of getAccountLiquidity
+type accountLiquidity struct {
+	Error     *big.Int
+	Liquidity *big.Int
+	Shortfall *big.Int
+}
+
+// accountSnapshot are the outputs of getAccountSnapshot
+type accountSnapshot struct {
+	Error                *big.Int
+	CTokenBalance        *big.Int
+	BorrowBalance        *big.Int
+	ExchangeRateMantissa *big.Int
+}
+
+// Snapshot is the position of an account in a market. Fields the market
+// failed to return are left as zero values, and Err reports why.
+type Snapshot struct {
+	Market common.Address
+	// CTokenBalance is the balance of cTokens of the account
+	CTokenBalance *big.Int
+	// BorrowBalance is the debt of the account, in the underlying token
+	BorrowBalance *big.Int
+	// ExchangeRate is the amount of the underlying token a cToken is worth,
+	// scaled by 1e18
+	ExchangeRate *big.Int
+	Err          error
+}
+
+// Supplied returns the amount of the underlying token the cTokens of the
+// account are worth, or nil if the market failed to return them
+func (s Snapshot) Supplied() *big.Int {
+	if s.CTokenBalance == nil || s.ExchangeRate == nil {
+		return nil
+	}
+	supplied := new(big.Int).Mul(s.CTokenBalance, s.ExchangeRate)
+	return supplied.Quo(supplied, mantissa)
+}
+
+// Account is the position of an account across markets. Liquidity and
+// Shortfall are in USD scaled by 1e18, as priced by the Comptroller's
+// oracle. Fields the Comptroller failed to return are left as zero values,
+// and Err reports why.
+type Account struct {
+	Address common.Address
+	// Liquidity is how much more, in USD, the account may borrow
+	Liquidity *big.Int
+	// Shortfall is how much, in USD, the account's debt exceeds what its
+	// collateral allows
+	Shortfall *big.Int
+	// Markets are the snapshots of the account in every market, in their
+	// order
+	Markets []Snapshot
+	// Err reports why getAccountLiquidity failed. The failures of markets
+	// are reported by their Snapshot.Err.
+	Err error
+}
+
+// Liquidatable reports whether the account has a shortfall, as it must for
+// the account to be liquidated
+func (a Account) Liquidatable() bool {
+	return a.Shortfall != nil && a.Shortfall.Sign() > 0
+}
+
+// Accounts fetches the liquidity of every account from comptroller and the
+// snapshot of every account in every market in a single batch, returning
+// them in the order of the accounts. The error is only set if the batch
+// itself failed.
+func Accounts(ctx context.Context, client *multicall.Client, comptroller common.Address, markets, accounts []common.Address, opts ...multicall.CallOption) ([]Account, error) {
+	batch := client.NewBatch()
+	liquidity := make([]*multicall.CallOf[accountLiquidity], len(accounts))
+	snapshots := make([][]*multicall.CallOf[accountSnapshot], len(accounts))
+	for i, account := range accounts {
+		liquidity[i] = multicall.View[accountLiquidity](ComptrollerABI, "getAccountLiquidity", account)
+		batch.AddCall(comptroller, liquidity[i]).AllowFailure()
+		snapshots[i] = make([]*multicall.CallOf[accountSnapshot], len(markets))
+		for j, market := range markets {
+			snapshots[i][j] = multicall.View[accountSnapshot](CTokenABI, "getAccountSnapshot", account)
+			batch.AddCall(market, snapshots[i][j]).AllowFailure()
+		}
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	calls := 1 + len(markets)
+	state := make([]Account, len(accounts))
+	for i, account := range accounts {
+		accountResults := results[i*calls : (i+1)*calls]
+		state[i] = Account{Address: account, Markets: make([]Snapshot, len(markets))}
+		method := fmt.Sprintf("getAccountLiquidity(%s)", account)
+		if state[i].Err = accountResults[0].Error(comptroller, method); state[i].Err == nil {
+			data := liquidity[i].Value()
+			if state[i].Err = codeErr(comptroller, method, data.Error); state[i].Err == nil {
+				state[i].Liquidity, state[i].Shortfall = data.Liquidity, data.Shortfall
+			}
+		}
+		for j, market := range markets {
+			snapshot := &state[i].Markets[j]
+			snapshot.Market = market
+			method := fmt.Sprintf("getAccountSnapshot(%s)", account)
+			if snapshot.Err = accountResults[1+j].Error(market, method); snapshot.Err != nil {
+				continue
+			}
+			data := snapshots[i][j].Value()
+			if snapshot.Err = codeErr(market, method, data.Error); snapshot.Err != nil {
+				continue
+			}
+			snapshot.CTokenBalance = data.CTokenBalance
+			snapshot.BorrowBalance = data.BorrowBalance
+			snapshot.ExchangeRate = data.ExchangeRateMantissa
+		}
+	}
+	return state, nil
+}
+
+// ErrFailure is returned when a Compound contract reports an error code
+// instead of reverting
+var ErrFailure = errors.New("compound: call failed")
+
+// codeErr returns the error of method on contract if it returned a nonzero
+// error code
+func codeErr(contract common.Address, method string, code *big.Int) error {
+	if code.Sign() == 0 {
+		return nil
+	}
+	return fmt.Errorf("compound: %s of %s: error code %s: %w", method, contract, code, ErrFailure)
+}
@@ -0,0 +1,83 @@
+package compound_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/compound"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	comptroller = common.HexToAddress("0x3d9819210A31b4961b30EF54bE2aeD79B9c9Cd3B")
+	cETH        = common.HexToAddress("0x4Ddc2D193948926D02f9B1fE9e1daa0718270ED5")
+	cDAI        = common.HexToAddress("0x5d3a536E4D6DbD6114cc1Ead35777bAB948E3643")
+	borrower    = common.Address{19: 1}
+	faulty      = common.Address{19: 2}
+)
+
+// newClient returns a client reading a comptroller in which borrower has a
+// shortfall and faulty gets an error code, a cETH market in which every
+// account holds 50 cTokens worth 0.02 ETH each, and a cDAI market that
+// reverts for faulty
+func newClient(t *testing.T) *multicall.Client {
+	t.Helper()
+	rate := big.NewInt(2e16)
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		comptroller: fake.Methods(compound.ComptrollerABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getAccountLiquidity": func(args []interface{}) ([]interface{}, error) {
+				if args[0].(common.Address) == faulty {
+					return []interface{}{big.NewInt(9), new(big.Int), new(big.Int)}, nil
+				}
+				return []interface{}{new(big.Int), new(big.Int), big.NewInt(5e17)}, nil
+			},
+		}),
+		cETH: fake.Methods(compound.CTokenABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getAccountSnapshot": fake.Returns(new(big.Int), big.NewInt(50), big.NewInt(1), rate),
+		}),
+		cDAI: fake.Methods(compound.CTokenABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getAccountSnapshot": func(args []interface{}) ([]interface{}, error) {
+				if args[0].(common.Address) == faulty {
+					return nil, &fake.Revert{}
+				}
+				return []interface{}{new(big.Int), new(big.Int), big.NewInt(100), rate}, nil
+			},
+		}),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestAccounts(t *testing.T) {
+	accounts, err := compound.Accounts(context.Background(), newClient(t), comptroller, []common.Address{cETH, cDAI}, []common.Address{borrower, faulty})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := accounts[0]
+	if got.Err != nil || got.Liquidity.Sign() != 0 || got.Shortfall.Int64() != 5e17 || !got.Liquidatable() {
+		t.Errorf("got account %+v, want a shortfall of 0.5 USD", got)
+	}
+	if eth := got.Markets[0]; eth.Market != cETH || eth.Err != nil || eth.CTokenBalance.Int64() != 50 || eth.BorrowBalance.Int64() != 1 || eth.Supplied().Int64() != 1 {
+		t.Errorf("got snapshot %+v, want 50 cETH worth 1 wei", eth)
+	}
+	if dai := got.Markets[1]; dai.Market != cDAI || dai.Err != nil || dai.BorrowBalance.Int64() != 100 || dai.Supplied().Sign() != 0 {
+		t.Errorf("got snapshot %+v, want a debt of 100", dai)
+	}
+
+	got = accounts[1]
+	if got.Shortfall != nil || got.Liquidatable() || !errors.Is(got.Err, compound.ErrFailure) {
+		t.Errorf("got account %+v, want the error code of getAccountLiquidity", got)
+	}
+	if got.Markets[0].Err != nil {
+		t.Errorf("got error %v in cETH, want the error of the comptroller only", got.Markets[0].Err)
+	}
+	if dai := got.Markets[1]; dai.Supplied() != nil || !errors.Is(dai.Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got snapshot %+v, want the revert of getAccountSnapshot", dai)
+	}
+}
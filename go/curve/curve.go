@@ -0,0 +1,151 @@
+// Package curve reads the coins and balances of Curve pools, and quotes swaps
+// through them, in bulk through Multicall3, for multi-venue price scanners:
+//
+//	pools, err := curve.Pools(ctx, client, []curve.Pool{{Address: threePool, Coins: 3}})
+//	quotes, err := curve.Quotes(ctx, client, []curve.Quote{
+//		{Pool: threePool, I: 0, J: 1, Amount: oneDAI},
+//	})
+//
+// Older pools index coins with int128 and newer ones with uint256, so both
+// variants of each method are called, allowed to fail, and the one the pool
+// implements is used.
+package curve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ABI is the part of the Curve pool ABI used by the helpers, with int128 and
+// uint256 overloads of the methods that take coin indexes, named with the
+// suffix 0 for the former
+var ABI = helper.MustParseABI("curve", poolABI)
+
+const poolABI = `[
+	{"type":"function","name":"coins","stateMutability":"view","inputs":[{"name":"i","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"coins","stateMutability":"view","inputs":[{"name":"i","type":"int128"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"balances","stateMutability":"view","inputs":[{"name":"i","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"balances","stateMutability":"view","inputs":[{"name":"i","type":"int128"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"get_dy","stateMutability":"view","inputs":[{"name":"i","type":"uint256"},{"name":"j","type":"uint256"},{"name":"dx","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"get_dy","stateMutability":"view","inputs":[{"name":"i","type":"int128"},{"name":"j","type":"int128"},{"name":"dx","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// Pool is a pool to read, with its number of coins
+type Pool struct {
+	Address common.Address
+	Coins   int
+}
+
+// PoolState holds the coins of a pool and their balances, in the order of
+// their indexes. Coins the pool failed to return are left as zero values,
+// and Err reports why.
+type PoolState struct {
+	Address  common.Address
+	Coins    []common.Address
+	Balances []*big.Int
+	// Err joins the errors of the coins and balances that failed to be read,
+	// which are left zero
+	Err error
+}
+
+// Pools fetches the coins and balances of every pool in a single batch,
+// returning them in the order of the pools. The error is only set if the
+// batch itself failed, the failures of a pool are reported by its
+// PoolState.Err.
+func Pools(ctx context.Context, client *multicall.Client, pools []Pool, opts ...multicall.CallOption) ([]PoolState, error) {
+	batch := client.NewBatch()
+	for _, pool := range pools {
+		for i := 0; i < pool.Coins; i++ {
+			index := big.NewInt(int64(i))
+			batch.Add(pool.Address, ABI, "coins", index).AllowFailure().
+				Add(pool.Address, ABI, "coins0", index).AllowFailure().
+				Add(pool.Address, ABI, "balances", index).AllowFailure().
+				Add(pool.Address, ABI, "balances0", index).AllowFailure()
+		}
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	state := make([]PoolState, len(pools))
+	for i, pool := range pools {
+		state[i] = PoolState{
+			Address:  pool.Address,
+			Coins:    make([]common.Address, pool.Coins),
+			Balances: make([]*big.Int, pool.Coins),
+		}
+		var errs []error
+		for j := 0; j < pool.Coins; j++ {
+			coin, err := either(pool.Address, fmt.Sprintf("coins(%d)", j), results[0], results[1])
+			if err == nil {
+				state[i].Coins[j] = coin.Values[0].(common.Address)
+			}
+			errs = append(errs, err)
+			balance, err := either(pool.Address, fmt.Sprintf("balances(%d)", j), results[2], results[3])
+			if err == nil {
+				state[i].Balances[j] = balance.Values[0].(*big.Int)
+			}
+			errs = append(errs, err)
+			results = results[4:]
+		}
+		state[i].Err = errors.Join(errs...)
+	}
+	return state, nil
+}
+
+// Quote is a swap of Amount of the coin with index I of Pool for the coin
+// with index J
+type Quote struct {
+	Pool   common.Address
+	I, J   int
+	Amount *big.Int
+}
+
+// Quotes fetches the amount every swap receives, from get_dy, in a single
+// batch, returning them in the order of the quotes. The amounts of quotes
+// whose call failed are left nil and their errors are returned joined.
+func Quotes(ctx context.Context, client *multicall.Client, quotes []Quote, opts ...multicall.CallOption) ([]*big.Int, error) {
+	batch := client.NewBatch()
+	for _, quote := range quotes {
+		i, j := big.NewInt(int64(quote.I)), big.NewInt(int64(quote.J))
+		batch.Add(quote.Pool, ABI, "get_dy", i, j, quote.Amount).AllowFailure().
+			Add(quote.Pool, ABI, "get_dy0", i, j, quote.Amount).AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	amounts := make([]*big.Int, len(quotes))
+	var errs []error
+	for k, quote := range quotes {
+		result, err := either(quote.Pool, fmt.Sprintf("get_dy(%d, %d, %s)", quote.I, quote.J, quote.Amount), results[2*k], results[2*k+1])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		amounts[k] = result.Values[0].(*big.Int)
+	}
+	return amounts, helper.JoinErrors(errs...)
+}
+
+// either returns the result of the uint256 or int128 variant of method on
+// pool, whichever succeeded, or the error of the uint256 variant if neither
+// did
+func either(pool common.Address, method string, uint256, int128 multicall.CallResult) (multicall.CallResult, error) {
+	if uint256.Success && uint256.Err == nil {
+		return uint256, nil
+	}
+	if int128.Success && int128.Err == nil {
+		return int128, nil
+	}
+	if !uint256.Success {
+		return uint256, fmt.Errorf("curve: %s of %s: %s: %w", method, pool, uint256.Reason(), multicall.ErrExecutionReverted)
+	}
+	return uint256, uint256.Err
+}
@@ -0,0 +1,92 @@
+package curve_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/curve"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	threePool = common.HexToAddress("0xbEbc44782C7dB0a1A60Cb6fe97d0b483032FF1C7")
+	oldPool   = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	notPool   = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+)
+
+// pool returns a pool indexing its coins with uint256, or int128 if old,
+// whose coin i is {19: 10+i} with a balance of 100*(i+1), and whose swaps
+// receive factor times their amount
+func pool(old bool, factor int64) fake.Contract {
+	suffix := ""
+	if old {
+		suffix = "0"
+	}
+	return fake.Methods(curve.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+		"coins" + suffix: func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{common.Address{19: byte(10 + args[0].(*big.Int).Int64())}}, nil
+		},
+		"balances" + suffix: func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{big.NewInt(100 * (args[0].(*big.Int).Int64() + 1))}, nil
+		},
+		"get_dy" + suffix: func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{new(big.Int).Mul(args[2].(*big.Int), big.NewInt(factor))}, nil
+		},
+	})
+}
+
+func newClient(t *testing.T) *multicall.Client {
+	t.Helper()
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		threePool: pool(false, 2),
+		oldPool:   pool(true, 3),
+		notPool:   fake.Methods(curve.ABI, nil),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestPools(t *testing.T) {
+	pools, err := curve.Pools(context.Background(), newClient(t), []curve.Pool{
+		{Address: threePool, Coins: 3},
+		{Address: oldPool, Coins: 2},
+		{Address: notPool, Coins: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, coins := range []int{3, 2} {
+		got := pools[i]
+		if got.Err != nil || len(got.Coins) != coins {
+			t.Fatalf("got pool %+v, want %d coins", got, coins)
+		}
+		for j := 0; j < coins; j++ {
+			if got.Coins[j] != (common.Address{19: byte(10 + j)}) || got.Balances[j].Int64() != int64(100*(j+1)) {
+				t.Errorf("got coin %s with balance %s in pool %s", got.Coins[j], got.Balances[j], got.Address)
+			}
+		}
+	}
+	if got := pools[2]; got.Coins[0] != (common.Address{}) || got.Balances[0] != nil || !errors.Is(got.Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got pool %+v, want the reverts of coins and balances", got)
+	}
+}
+
+func TestQuotes(t *testing.T) {
+	amounts, err := curve.Quotes(context.Background(), newClient(t), []curve.Quote{
+		{Pool: threePool, I: 0, J: 1, Amount: big.NewInt(10)},
+		{Pool: oldPool, I: 1, J: 0, Amount: big.NewInt(10)},
+		{Pool: notPool, I: 0, J: 1, Amount: big.NewInt(10)},
+	})
+	if !errors.Is(err, multicall.ErrExecutionReverted) {
+		t.Errorf("got error %v, want the revert of get_dy", err)
+	}
+	if amounts[0].Int64() != 20 || amounts[1].Int64() != 30 || amounts[2] != nil {
+		t.Errorf("got amounts %v, want 20 and 30", amounts)
+	}
+}
@@ -0,0 +1,139 @@
+// Package erc1155 reads the balances and metadata URIs of ERC-1155 tokens in
+// bulk through Multicall3. Each collection is queried with a single
+// balanceOfBatch call for all of its owners and IDs, and the balances of all
+// collections are flattened into one map:
+//
+//	balances, err := erc1155.BalancesOf(ctx, client, []erc1155.Query{
+//		{Collection: items, Owners: players, IDs: []*big.Int{sword, shield}},
+//	})
+//	fmt.Println(balances.Of(items, sword, player))
+package erc1155
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ABI is the part of the ERC-1155 ABI used by the helpers
+var ABI = helper.MustParseABI("erc1155", erc1155ABI)
+
+const erc1155ABI = `[
+	{"type":"function","name":"balanceOfBatch","stateMutability":"view","inputs":[{"name":"accounts","type":"address[]"},{"name":"ids","type":"uint256[]"}],"outputs":[{"name":"","type":"uint256[]"}]},
+	{"type":"function","name":"uri","stateMutability":"view","inputs":[{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"string"}]}
+]`
+
+// Query asks for the balance of every owner in every token ID of a collection
+type Query struct {
+	Collection common.Address
+	Owners     []common.Address
+	IDs        []*big.Int
+}
+
+// Key identifies the balance of an owner in a token, whose ID is held as a
+// 32 byte big-endian word so that keys can be compared
+type Key struct {
+	Collection common.Address
+	ID         common.Hash
+	Owner      common.Address
+}
+
+// KeyOf returns the key of the balance of owner in the token id of collection
+func KeyOf(collection common.Address, id *big.Int, owner common.Address) Key {
+	return Key{Collection: collection, ID: common.BigToHash(id), Owner: owner}
+}
+
+// Balances holds balances by collection, token ID and owner
+type Balances map[Key]*big.Int
+
+// Of returns the balance of owner in the token id of collection, or nil if it
+// was not fetched
+func (b Balances) Of(collection common.Address, id *big.Int, owner common.Address) *big.Int {
+	return b[KeyOf(collection, id, owner)]
+}
+
+// BalancesOf fetches the balances asked for by queries in a single batch,
+// with one balanceOfBatch call per query. A collection whose call fails does
+// not fail the others: the balances of every other collection are returned
+// along with the errors of the failed ones, joined, while the error of a
+// batch that failed as a whole is returned with no balances.
+func BalancesOf(ctx context.Context, client *multicall.Client, queries []Query, opts ...multicall.CallOption) (Balances, error) {
+	batch := client.NewBatch()
+	for _, query := range queries {
+		accounts := make([]common.Address, 0, len(query.Owners)*len(query.IDs))
+		ids := make([]*big.Int, 0, len(query.Owners)*len(query.IDs))
+		for _, owner := range query.Owners {
+			for _, id := range query.IDs {
+				accounts = append(accounts, owner)
+				ids = append(ids, id)
+			}
+		}
+		batch.Add(query.Collection, ABI, "balanceOfBatch", accounts, ids).AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	balances := make(Balances)
+	var errs []error
+	for i, query := range queries {
+		result := results[i]
+		if err := result.Error(query.Collection, "balanceOfBatch"); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		amounts := result.Values[0].([]*big.Int)
+		if len(amounts) != len(query.Owners)*len(query.IDs) {
+			errs = append(errs, fmt.Errorf("erc1155: balanceOfBatch of %s returned %d balances for %d", query.Collection, len(amounts), len(query.Owners)*len(query.IDs)))
+			continue
+		}
+		for j, owner := range query.Owners {
+			for k, id := range query.IDs {
+				balances[KeyOf(query.Collection, id, owner)] = amounts[j*len(query.IDs)+k]
+			}
+		}
+	}
+	return balances, helper.JoinErrors(errs...)
+}
+
+// Token identifies a token by its collection and ID
+type Token struct {
+	Collection common.Address
+	ID         *big.Int
+}
+
+// URIs fetches the metadata URI of every token in a single batch, returning
+// them in the order of the tokens. Like BalancesOf, the URIs of tokens whose
+// call failed are left empty and their errors are returned joined. URIs may
+// contain the {id} placeholder, see ExpandURI.
+func URIs(ctx context.Context, client *multicall.Client, tokens []Token, opts ...multicall.CallOption) ([]string, error) {
+	batch := client.NewBatch()
+	for _, token := range tokens {
+		batch.Add(token.Collection, ABI, "uri", token.ID).AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	uris := make([]string, len(tokens))
+	var errs []error
+	for i, token := range tokens {
+		if err := results[i].Error(token.Collection, fmt.Sprintf("uri(%s)", token.ID)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		uris[i] = results[i].Values[0].(string)
+	}
+	return uris, helper.JoinErrors(errs...)
+}
+
+// ExpandURI replaces the {id} placeholder of a metadata URI by the token ID,
+// as 64 lowercase hexadecimal digits, as ERC-1155 clients must
+func ExpandURI(uri string, id *big.Int) string {
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id))
+}
@@ -0,0 +1,92 @@
+package erc1155_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/erc1155"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	items   = common.HexToAddress("0x76BE3b62873462d2142405439777e971754E8E77")
+	broken  = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	players = []common.Address{{19: 1}, {19: 2}}
+	sword   = big.NewInt(10)
+	shield  = big.NewInt(20)
+)
+
+// newClient returns a client reading items, in which every account holds
+// the last byte of its address times the token ID, and a collection whose
+// balanceOfBatch returns a single balance and whose uri reverts
+func newClient(t *testing.T) *multicall.Client {
+	t.Helper()
+	itemsCollection := fake.Methods(erc1155.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+		"balanceOfBatch": func(args []interface{}) ([]interface{}, error) {
+			accounts, ids := args[0].([]common.Address), args[1].([]*big.Int)
+			balances := make([]*big.Int, len(accounts))
+			for i, account := range accounts {
+				balances[i] = new(big.Int).Mul(big.NewInt(int64(account[19])), ids[i])
+			}
+			return []interface{}{balances}, nil
+		},
+		"uri": fake.Returns("https://items.example/{id}.json"),
+	})
+	brokenCollection := fake.Methods(erc1155.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+		"balanceOfBatch": fake.Returns([]*big.Int{big.NewInt(1)}),
+	})
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		items:  itemsCollection,
+		broken: brokenCollection,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestBalancesOf(t *testing.T) {
+	balances, err := erc1155.BalancesOf(context.Background(), newClient(t), []erc1155.Query{
+		{Collection: items, Owners: players, IDs: []*big.Int{sword, shield}},
+		{Collection: broken, Owners: players, IDs: []*big.Int{sword}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "returned 1 balances for 2") {
+		t.Errorf("got error %v, want the short balanceOfBatch of the broken collection", err)
+	}
+	if len(balances) != 4 {
+		t.Errorf("got %d balances, want the 4 of items only", len(balances))
+	}
+	for _, player := range players {
+		for _, id := range []*big.Int{sword, shield} {
+			want := new(big.Int).Mul(big.NewInt(int64(player[19])), id)
+			if got := balances.Of(items, id, player); got == nil || got.Cmp(want) != 0 {
+				t.Errorf("got balance %v of %s in token %s, want %s", got, player, id, want)
+			}
+		}
+	}
+	if got := balances.Of(broken, sword, players[0]); got != nil {
+		t.Errorf("got balance %s in the broken collection", got)
+	}
+}
+
+func TestURIs(t *testing.T) {
+	uris, err := erc1155.URIs(context.Background(), newClient(t), []erc1155.Token{
+		{Collection: items, ID: sword},
+		{Collection: broken, ID: sword},
+	})
+	if !errors.Is(err, multicall.ErrExecutionReverted) {
+		t.Errorf("got error %v, want the revert of uri", err)
+	}
+	if uris[0] != "https://items.example/{id}.json" || uris[1] != "" {
+		t.Errorf("got URIs %q", uris)
+	}
+	want := "https://items.example/000000000000000000000000000000000000000000000000000000000000000a.json"
+	if got := erc1155.ExpandURI(uris[0], sword); got != want {
+		t.Errorf("expanded URI to %s, want %s", got, want)
+	}
+}
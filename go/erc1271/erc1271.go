@@ -0,0 +1,67 @@
+// Package erc1271 checks the signatures of smart contract wallets with
+// EIP-1271 isValidSignature, for any number of wallets and signatures in a
+// single batch, such as to verify the contract signatures of an off-chain
+// order book:
+//
+//	valid, err := erc1271.Verify(ctx, client, []erc1271.Signature{
+//		{Wallet: safe, Hash: orderHash, Signature: sig},
+//	})
+package erc1271
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ABI is the EIP-1271 ABI
+var ABI = helper.MustParseABI("erc1271", erc1271ABI)
+
+const erc1271ABI = `[
+	{"type":"function","name":"isValidSignature","stateMutability":"view","inputs":[{"name":"hash","type":"bytes32"},{"name":"signature","type":"bytes"}],"outputs":[{"name":"magicValue","type":"bytes4"}]}
+]`
+
+// MagicValue is the value isValidSignature returns for valid signatures, its
+// own selector
+var MagicValue = [4]byte{0x16, 0x26, 0xba, 0x7e}
+
+// Signature is a signature of Hash to check against Wallet
+type Signature struct {
+	Wallet    common.Address
+	Hash      common.Hash
+	Signature []byte
+}
+
+// Verify checks every signature in a single batch and reports whether each
+// is valid, in the order of the signatures. A signature is only valid if its
+// wallet returns MagicValue: calls that revert or return anything else, as on
+// accounts without code, count as invalid signatures, so the error is only
+// set if the batch itself failed.
+func Verify(ctx context.Context, client *multicall.Client, signatures []Signature, opts ...multicall.CallOption) ([]bool, error) {
+	batch := client.NewBatch()
+	for _, signature := range signatures {
+		batch.Add(signature.Wallet, ABI, "isValidSignature", signature.Hash, signature.Signature).AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	valid := make([]bool, len(signatures))
+	for i, result := range results {
+		valid[i] = isMagic(result)
+	}
+	return valid, nil
+}
+
+// magicWord is MagicValue as a returned bytes4, padded to a word
+var magicWord = common.RightPadBytes(MagicValue[:], 32)
+
+// isMagic reports whether an isValidSignature call returned MagicValue. The
+// whole word is compared so that contracts returning other data that happens
+// to start with it are not taken as valid.
+func isMagic(result multicall.CallResult) bool {
+	return result.Success && bytes.Equal(result.ReturnData, magicWord)
+}
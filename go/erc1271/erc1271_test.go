@@ -0,0 +1,62 @@
+package erc1271_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/erc1271"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	wallet   = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	reverter = common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	trailing = common.HexToAddress("0x00000000000000000000000000000000000000a3")
+	account  = common.HexToAddress("0x00000000000000000000000000000000000000a4")
+	hash     = common.HexToHash("0x01")
+)
+
+func TestVerify(t *testing.T) {
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		// The wallet only accepts the signature "ok"
+		wallet: fake.Methods(erc1271.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"isValidSignature": func(args []interface{}) ([]interface{}, error) {
+				if !bytes.Equal(args[1].([]byte), []byte("ok")) {
+					return []interface{}{[4]byte{0xff, 0xff, 0xff, 0xff}}, nil
+				}
+				return []interface{}{erc1271.MagicValue}, nil
+			},
+		}),
+		reverter: fake.Methods(erc1271.ABI, nil),
+		// Returns the magic value followed by more data
+		trailing: func(ctx context.Context, data []byte) ([]byte, error) {
+			return append(common.RightPadBytes(erc1271.MagicValue[:], 32), make([]byte, 32)...), nil
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err := erc1271.Verify(context.Background(), client, []erc1271.Signature{
+		{Wallet: wallet, Hash: hash, Signature: []byte("ok")},
+		{Wallet: wallet, Hash: hash, Signature: []byte("forged")},
+		{Wallet: reverter, Hash: hash, Signature: []byte("ok")},
+		{Wallet: trailing, Hash: hash, Signature: []byte("ok")},
+		{Wallet: account, Hash: hash, Signature: []byte("ok")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []bool{true, false, false, false, false}; !reflect.DeepEqual(valid, want) {
+		t.Errorf("got validity %v, want %v", valid, want)
+	}
+}
+
+func TestMagicValue(t *testing.T) {
+	if id := erc1271.ABI.Methods["isValidSignature"].ID; !bytes.Equal(id, erc1271.MagicValue[:]) {
+		t.Errorf("got magic value %x, want the selector of isValidSignature, %x", erc1271.MagicValue, id)
+	}
+}
@@ -0,0 +1,96 @@
+// Package erc165 detects the interfaces contracts implement with ERC-165
+// supportsInterface, for any number of contracts and interfaces in a single
+// batch, such as to classify unknown contracts at scale:
+//
+//	capabilities, err := erc165.Scan(ctx, client, contracts, []erc165.InterfaceID{erc165.ERC721, erc165.ERC1155})
+//	for _, contract := range capabilities {
+//		fmt.Println(contract.Address, contract.Supports)
+//	}
+package erc165
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ABI is the ERC-165 ABI
+var ABI = helper.MustParseABI("erc165", erc165ABI)
+
+const erc165ABI = `[
+	{"type":"function","name":"supportsInterface","stateMutability":"view","inputs":[{"name":"interfaceId","type":"bytes4"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+// InterfaceID is an ERC-165 interface identifier, the XOR of the selectors of
+// the functions of the interface
+type InterfaceID [4]byte
+
+// Identifiers of common interfaces
+var (
+	ERC165             = InterfaceID{0x01, 0xff, 0xc9, 0xa7}
+	ERC721             = InterfaceID{0x80, 0xac, 0x58, 0xcd}
+	ERC721Metadata     = InterfaceID{0x5b, 0x5e, 0x13, 0x9f}
+	ERC721Enumerable   = InterfaceID{0x78, 0x0e, 0x9d, 0x63}
+	ERC1155            = InterfaceID{0xd9, 0xb6, 0x7a, 0x26}
+	ERC1155MetadataURI = InterfaceID{0x0e, 0x89, 0x34, 0x1c}
+	ERC2981            = InterfaceID{0x2a, 0x55, 0x20, 0x5a}
+	ERC4906            = InterfaceID{0x49, 0x06, 0x49, 0x06}
+)
+
+// invalidID is the interface ID that no ERC-165 contract may claim to support
+var invalidID = InterfaceID{0xff, 0xff, 0xff, 0xff}
+
+// Capabilities are the interfaces a contract supports
+type Capabilities struct {
+	Address common.Address
+	// ERC165 reports whether the contract implements ERC-165. If it does not,
+	// it is taken to support none of the interfaces.
+	ERC165 bool
+	// Supports reports whether the contract supports each of the interfaces
+	// scanned for, in their order
+	Supports []bool
+}
+
+// Scan checks every contract for every interface in a single batch and
+// returns their capabilities in the order of the contracts. Following
+// ERC-165, a contract only implements it if supportsInterface returns true
+// for the ERC-165 interface itself and false for 0xffffffff. Calls that
+// revert or return something other than a boolean, as on accounts without
+// code, count as not supporting the interface, so the error is only set if
+// the batch itself failed.
+func Scan(ctx context.Context, client *multicall.Client, contracts []common.Address, interfaceIDs []InterfaceID, opts ...multicall.CallOption) ([]Capabilities, error) {
+	queried := append([]InterfaceID{ERC165, invalidID}, interfaceIDs...)
+	batch := client.NewBatch()
+	for _, contract := range contracts {
+		for _, id := range queried {
+			batch.Add(contract, ABI, "supportsInterface", id).AllowFailure()
+		}
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	capabilities := make([]Capabilities, len(contracts))
+	for i, contract := range contracts {
+		contractResults := results[i*len(queried) : (i+1)*len(queried)]
+		capabilities[i] = Capabilities{
+			Address:  contract,
+			ERC165:   supported(contractResults[0]) && !supported(contractResults[1]),
+			Supports: make([]bool, len(interfaceIDs)),
+		}
+		if !capabilities[i].ERC165 {
+			continue
+		}
+		for j, result := range contractResults[2:] {
+			capabilities[i].Supports[j] = supported(result)
+		}
+	}
+	return capabilities, nil
+}
+
+// supported reports whether a supportsInterface call returned true
+func supported(result multicall.CallResult) bool {
+	return result.Success && result.Err == nil && result.Values[0].(bool)
+}
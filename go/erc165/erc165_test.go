@@ -0,0 +1,65 @@
+package erc165_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/erc165"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	nft      = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	yes      = common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	reverter = common.HexToAddress("0x00000000000000000000000000000000000000a3")
+	account  = common.HexToAddress("0x00000000000000000000000000000000000000a4")
+)
+
+// supports returns a contract supporting the interfaces ids
+func supports(ids ...erc165.InterfaceID) fake.Contract {
+	return fake.Methods(erc165.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+		"supportsInterface": func(args []interface{}) ([]interface{}, error) {
+			for _, id := range ids {
+				if args[0].([4]byte) == id {
+					return []interface{}{true}, nil
+				}
+			}
+			return []interface{}{false}, nil
+		},
+	})
+}
+
+func TestScan(t *testing.T) {
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		nft: supports(erc165.ERC165, erc165.ERC721, erc165.ERC721Metadata),
+		// Claiming to support 0xffffffff breaks ERC-165
+		yes:      supports(erc165.ERC165, erc165.ERC721, erc165.ERC1155, erc165.InterfaceID{0xff, 0xff, 0xff, 0xff}),
+		reverter: fake.Methods(erc165.ABI, nil),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	interfaces := []erc165.InterfaceID{erc165.ERC721, erc165.ERC1155, erc165.ERC721Metadata}
+	capabilities, err := erc165.Scan(context.Background(), client, []common.Address{nft, yes, reverter, account}, interfaces)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []erc165.Capabilities{
+		{Address: nft, ERC165: true, Supports: []bool{true, false, true}},
+		{Address: yes, Supports: []bool{false, false, false}},
+		{Address: reverter, Supports: []bool{false, false, false}},
+		{Address: account, Supports: []bool{false, false, false}},
+	}
+	if !reflect.DeepEqual(capabilities, want) {
+		t.Errorf("got capabilities %+v, want %+v", capabilities, want)
+	}
+}
+
+func TestInterfaceIDs(t *testing.T) {
+	if id := erc165.ABI.Methods["supportsInterface"].ID; erc165.InterfaceID(id) != erc165.ERC165 {
+		t.Errorf("got ERC-165 interface ID %x, want the selector of supportsInterface, %x", erc165.ERC165, id)
+	}
+}
@@ -0,0 +1,81 @@
+package erc20
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// UnlimitedThreshold is the allowance from which an approval counts as
+// unlimited: the largest uint96, which tokens such as UNI and COMP use as
+// their infinite allowance. Allowances of the largest uint256 that a token
+// decrements on every transfer stay above it too.
+var UnlimitedThreshold = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 96), big.NewInt(1))
+
+// Approval is the allowance an owner has given a spender over its tokens.
+// Amount is nil if the token failed to return it, and Err reports why.
+type Approval struct {
+	Owner   common.Address
+	Token   common.Address
+	Spender common.Address
+	Amount  *big.Int
+	Err     error
+}
+
+// Unlimited reports whether the allowance is at least UnlimitedThreshold
+func (a Approval) Unlimited() bool {
+	return a.Amount != nil && a.Amount.Cmp(UnlimitedThreshold) >= 0
+}
+
+// ApprovalReport lists approvals, such as those found by Approvals
+type ApprovalReport []Approval
+
+// Unlimited returns the approvals of the report that are unlimited
+func (r ApprovalReport) Unlimited() ApprovalReport {
+	var unlimited ApprovalReport
+	for _, approval := range r {
+		if approval.Unlimited() {
+			unlimited = append(unlimited, approval)
+		}
+	}
+	return unlimited
+}
+
+// Approvals fetches the allowance of every spender over the tokens of every
+// owner in a single batch, and reports the allowances that are not zero,
+// along with those that could not be fetched, by owner, token and spender.
+// Like BalancesOf, the error is only set if the batch itself failed.
+func Approvals(ctx context.Context, client *multicall.Client, owners, tokens, spenders []common.Address, opts ...multicall.CallOption) (ApprovalReport, error) {
+	batch := client.NewBatch()
+	for _, owner := range owners {
+		for _, token := range tokens {
+			for _, spender := range spenders {
+				batch.Add(token, ABI, "allowance", owner, spender).AllowFailure()
+			}
+		}
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var report ApprovalReport
+	for _, owner := range owners {
+		for _, token := range tokens {
+			for _, spender := range spenders {
+				result := results[0]
+				results = results[1:]
+				approval := Approval{Owner: owner, Token: token, Spender: spender}
+				if approval.Err = result.Error(token, "allowance"); approval.Err == nil {
+					approval.Amount = result.Values[0].(*big.Int)
+					if approval.Amount.Sign() == 0 {
+						continue
+					}
+				}
+				report = append(report, approval)
+			}
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,81 @@
+package erc20_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/john-na4/multicall3/go/erc20"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	router  = common.Address{19: 0xaa}
+	drainer = common.Address{19: 0xbb}
+	unused  = common.Address{19: 0xcc}
+)
+
+func TestApprovals(t *testing.T) {
+	token := fake.Methods(erc20.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+		"allowance": func(args []interface{}) ([]interface{}, error) {
+			switch args[1].(common.Address) {
+			case router:
+				return []interface{}{big.NewInt(100)}, nil
+			case drainer:
+				return []interface{}{math.MaxBig256}, nil
+			}
+			return []interface{}{new(big.Int)}, nil
+		},
+	})
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		dai:    token,
+		broken: fake.Methods(erc20.ABI, nil),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := common.Address{19: 1}
+	report, err := erc20.Approvals(context.Background(), client, []common.Address{owner}, []common.Address{dai, broken}, []common.Address{router, drainer, unused})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Zero allowances are left out, failed ones are kept
+	if len(report) != 5 {
+		t.Fatalf("got %d approvals, want 5", len(report))
+	}
+	if got := report[0]; got.Owner != owner || got.Token != dai || got.Spender != router || got.Amount.Int64() != 100 || got.Unlimited() {
+		t.Errorf("got approval %+v, want 100 DAI for the router", got)
+	}
+	if got := report[1]; got.Spender != drainer || !got.Unlimited() {
+		t.Errorf("got approval %+v, want an unlimited one for the drainer", got)
+	}
+	for _, got := range report[2:] {
+		if got.Token != broken || got.Amount != nil || !errors.Is(got.Err, multicall.ErrExecutionReverted) {
+			t.Errorf("got approval %+v, want the revert of allowance", got)
+		}
+	}
+	if unlimited := report.Unlimited(); len(unlimited) != 1 || unlimited[0].Spender != drainer {
+		t.Errorf("got unlimited approvals %+v, want the drainer's only", unlimited)
+	}
+}
+
+func TestApprovalUnlimited(t *testing.T) {
+	below := new(big.Int).Sub(erc20.UnlimitedThreshold, big.NewInt(1))
+	for _, test := range []struct {
+		amount *big.Int
+		want   bool
+	}{
+		{nil, false},
+		{below, false},
+		{erc20.UnlimitedThreshold, true},
+		{math.MaxBig256, true},
+	} {
+		if got := (erc20.Approval{Amount: test.amount}).Unlimited(); got != test.want {
+			t.Errorf("allowance %v unlimited: got %t, want %t", test.amount, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Package erc20 reads the metadata and balances of ERC-20 tokens in bulk
+// through Multicall3:
+//
+//	tokens, err := erc20.FetchMetadata(ctx, client, usdc, dai, weth)
+//	for _, token := range tokens {
+//		fmt.Println(token.Symbol, token.Decimals)
+//	}
+//
+// Every call is allowed to fail, so a token that does not implement a method,
+// or is not a token at all, is reported on its own without failing the
+// others.
+package erc20
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ABI is the part of the ERC-20 ABI used by the helpers
+var ABI = helper.MustParseABI("erc20", erc20ABI)
+
+const erc20ABI = `[
+	{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"symbol","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"totalSupply","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"allowance","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"nonces","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"DOMAIN_SEPARATOR","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bytes32"}]}
+]`
+
+// Metadata describes a token. Fields the token failed to return are left as
+// zero values, and Err reports why.
+type Metadata struct {
+	Token       common.Address
+	Name        string
+	Symbol      string
+	Decimals    uint8
+	TotalSupply *big.Int
+	// Err joins the errors of the getters that reverted or returned
+	// undecodable data, nil if the token returned every field
+	Err error
+}
+
+// FetchMetadata fetches the name, symbol, decimals and total supply of tokens
+// in a single batch, returning them in the order of the tokens. The error is
+// only set if the batch itself failed, the failures of a token are reported
+// by its Metadata.Err. Names and symbols returned as bytes32, as by MKR and
+// other early tokens, are decoded too.
+func FetchMetadata(ctx context.Context, client *multicall.Client, tokens ...common.Address) ([]Metadata, error) {
+	batch := client.NewBatch()
+	for _, token := range tokens {
+		addMetadata(batch, token)
+	}
+	results, err := batch.ExecuteResults(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata := make([]Metadata, len(tokens))
+	for i, token := range tokens {
+		metadata[i] = metadataOf(token, results[metadataCalls*i:])
+	}
+	return metadata, nil
+}
+
+// metadataCalls is the number of calls addMetadata adds
+const metadataCalls = 4
+
+// addMetadata adds the calls that fetch the metadata of token to batch
+func addMetadata(batch *multicall.Batch, token common.Address) {
+	batch.Add(token, ABI, "name").AllowFailure().
+		Add(token, ABI, "symbol").AllowFailure().
+		Add(token, ABI, "decimals").AllowFailure().
+		Add(token, ABI, "totalSupply").AllowFailure()
+}
+
+// metadataOf decodes the metadata of token from the results of the calls
+// added by addMetadata, which come first in results
+func metadataOf(token common.Address, results []multicall.CallResult) Metadata {
+	var nameErr, symbolErr, decimalsErr, totalSupplyErr error
+	metadata := Metadata{Token: token}
+	metadata.Name, nameErr = decodeString(token, "name", results[0])
+	metadata.Symbol, symbolErr = decodeString(token, "symbol", results[1])
+	if decimalsErr = results[2].Error(token, "decimals"); decimalsErr == nil {
+		metadata.Decimals = results[2].Values[0].(uint8)
+	}
+	if totalSupplyErr = results[3].Error(token, "totalSupply"); totalSupplyErr == nil {
+		metadata.TotalSupply = results[3].Values[0].(*big.Int)
+	}
+	metadata.Err = errors.Join(nameErr, symbolErr, decimalsErr, totalSupplyErr)
+	return metadata
+}
+
+// decodeString decodes the string returned by the call of method on token,
+// falling back to a bytes32 holding the string padded with zeros
+func decodeString(token common.Address, method string, result multicall.CallResult) (string, error) {
+	if result.Success && result.Err != nil && len(result.ReturnData) == 32 {
+		return bytes32String(result.ReturnData), nil
+	}
+	if err := result.Error(token, method); err != nil {
+		return "", err
+	}
+	return result.Values[0].(string), nil
+}
+
+// bytes32String returns the string held by a bytes32, without its padding
+func bytes32String(data []byte) string {
+	return string(bytes.TrimRight(data, "\x00"))
+}
+
+// Balance is the balance of a wallet in a token. Amount is nil if the token
+// failed to return it, and Err reports why.
+type Balance struct {
+	Wallet common.Address
+	Token  common.Address
+	Amount *big.Int
+	Err    error
+}
+
+// BalancesOf fetches the balance of every wallet in every token in a single
+// batch. The balances are returned by wallet, then by token, so the balance
+// of wallets[i] in tokens[j] is at index i*len(tokens)+j. Like FetchMetadata,
+// the error is only set if the batch itself failed.
+func BalancesOf(ctx context.Context, client *multicall.Client, wallets, tokens []common.Address, opts ...multicall.CallOption) ([]Balance, error) {
+	batch := client.NewBatch()
+	calls := make([]*multicall.CallOf[*big.Int], 0, len(wallets)*len(tokens))
+	for _, wallet := range wallets {
+		for _, token := range tokens {
+			call := multicall.View[*big.Int](ABI, "balanceOf", wallet)
+			batch.AddCall(token, call).AllowFailure()
+			calls = append(calls, call)
+		}
+	}
+	if _, err := batch.ExecuteResults(ctx, opts...); err != nil {
+		return nil, err
+	}
+	balances := make([]Balance, 0, len(calls))
+	for i, wallet := range wallets {
+		for j, token := range tokens {
+			amount, err := calls[i*len(tokens)+j].Get()
+			balances = append(balances, Balance{Wallet: wallet, Token: token, Amount: amount, Err: err})
+		}
+	}
+	return balances, nil
+}
@@ -0,0 +1,118 @@
+package erc20_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/erc20"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	dai    = common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	mkr    = common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")
+	broken = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+)
+
+// balanceOf answers balanceOf with the last byte of the owner's address
+func balanceOf(args []interface{}) ([]interface{}, error) {
+	owner := args[0].(common.Address)
+	return []interface{}{big.NewInt(int64(owner[len(owner)-1]))}, nil
+}
+
+// newClient returns a client reading DAI, MKR, whose name and symbol are
+// bytes32, and a token whose decimals and balanceOf revert
+func newClient(t *testing.T) *multicall.Client {
+	t.Helper()
+	daiToken := fake.Methods(erc20.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+		"name":        fake.Returns("Dai Stablecoin"),
+		"symbol":      fake.Returns("DAI"),
+		"decimals":    fake.Returns(uint8(18)),
+		"totalSupply": fake.Returns(big.NewInt(1e18)),
+		"balanceOf":   balanceOf,
+	})
+	mkrToken := fake.Methods(erc20.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+		"decimals":    fake.Returns(uint8(18)),
+		"totalSupply": fake.Returns(big.NewInt(1e6)),
+	})
+	brokenToken := fake.Methods(erc20.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+		"name":        fake.Returns("Broken"),
+		"symbol":      fake.Returns("BRK"),
+		"totalSupply": fake.Returns(big.NewInt(1)),
+	})
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		dai: daiToken,
+		mkr: func(ctx context.Context, data []byte) ([]byte, error) {
+			switch {
+			case bytes.Equal(data, erc20.ABI.Methods["name"].ID):
+				return common.RightPadBytes([]byte("Maker"), 32), nil
+			case bytes.Equal(data, erc20.ABI.Methods["symbol"].ID):
+				return common.RightPadBytes([]byte("MKR"), 32), nil
+			}
+			return mkrToken(ctx, data)
+		},
+		broken: brokenToken,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestFetchMetadata(t *testing.T) {
+	metadata, err := erc20.FetchMetadata(context.Background(), newClient(t), dai, mkr, broken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []erc20.Metadata{
+		{Token: dai, Name: "Dai Stablecoin", Symbol: "DAI", Decimals: 18, TotalSupply: big.NewInt(1e18)},
+		{Token: mkr, Name: "Maker", Symbol: "MKR", Decimals: 18, TotalSupply: big.NewInt(1e6)},
+		{Token: broken, Name: "Broken", Symbol: "BRK", TotalSupply: big.NewInt(1)},
+	}
+	for i, got := range metadata {
+		if got.Token != want[i].Token || got.Name != want[i].Name || got.Symbol != want[i].Symbol ||
+			got.Decimals != want[i].Decimals || got.TotalSupply.Cmp(want[i].TotalSupply) != 0 {
+			t.Errorf("got metadata %+v, want %+v", got, want[i])
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if metadata[i].Err != nil {
+			t.Errorf("%s: %v", metadata[i].Token, metadata[i].Err)
+		}
+	}
+	if !errors.Is(metadata[2].Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got error %v for reverting decimals, want ErrExecutionReverted", metadata[2].Err)
+	}
+}
+
+func TestBalancesOf(t *testing.T) {
+	wallets := []common.Address{{19: 1}, {19: 2}}
+	balances, err := erc20.BalancesOf(context.Background(), newClient(t), wallets, []common.Address{dai, broken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(balances) != 4 {
+		t.Fatalf("got %d balances, want 4", len(balances))
+	}
+	for i, balance := range balances {
+		wallet, token := wallets[i/2], []common.Address{dai, broken}[i%2]
+		if balance.Wallet != wallet || balance.Token != token {
+			t.Errorf("balance %d is of %s in %s, want %s in %s", i, balance.Wallet, balance.Token, wallet, token)
+		}
+	}
+	for _, i := range []int{0, 2} {
+		if balances[i].Err != nil || balances[i].Amount.Int64() != int64(i/2+1) {
+			t.Errorf("got balance %v, %v, want %d", balances[i].Amount, balances[i].Err, i/2+1)
+		}
+	}
+	for _, i := range []int{1, 3} {
+		if balances[i].Amount != nil || !errors.Is(balances[i].Err, multicall.ErrExecutionReverted) {
+			t.Errorf("got balance %v, %v of a reverting token, want ErrExecutionReverted", balances[i].Amount, balances[i].Err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package erc20
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// permitTypeHash is the EIP-712 type hash of the EIP-2612 Permit struct
+var permitTypeHash = crypto.Keccak256Hash([]byte("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"))
+
+// Permit is what an owner needs to sign an EIP-2612 permit for a token. Err
+// is set if the token failed to return its nonce or domain separator, as
+// tokens without permit support do.
+type Permit struct {
+	Token common.Address
+	Owner common.Address
+	// Nonce is the nonce the next permit of the owner must use
+	Nonce           *big.Int
+	DomainSeparator common.Hash
+	Err             error
+}
+
+// Digest returns the EIP-712 digest the owner signs to permit spender to
+// spend value of the token until deadline, a Unix timestamp. The permit must
+// have no Err.
+func (p Permit) Digest(spender common.Address, value, deadline *big.Int) common.Hash {
+	structHash := crypto.Keccak256(
+		permitTypeHash.Bytes(),
+		common.LeftPadBytes(p.Owner.Bytes(), 32),
+		common.LeftPadBytes(spender.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(value)),
+		math.U256Bytes(new(big.Int).Set(p.Nonce)),
+		math.U256Bytes(new(big.Int).Set(deadline)),
+	)
+	return crypto.Keccak256Hash([]byte("\x19\x01"), p.DomainSeparator.Bytes(), structHash)
+}
+
+// Permits fetches the permit nonce of owner and the domain separator of every
+// token in a single batch, so that permits for several tokens can be signed
+// off-chain at once. They are returned in the order of the tokens, and like
+// BalancesOf, the error is only set if the batch itself failed.
+func Permits(ctx context.Context, client *multicall.Client, owner common.Address, tokens []common.Address, opts ...multicall.CallOption) ([]Permit, error) {
+	batch := client.NewBatch()
+	for _, token := range tokens {
+		batch.Add(token, ABI, "nonces", owner).AllowFailure().
+			Add(token, ABI, "DOMAIN_SEPARATOR").AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	permits := make([]Permit, len(tokens))
+	for i, token := range tokens {
+		nonce, separator := results[2*i], results[2*i+1]
+		permits[i] = Permit{Token: token, Owner: owner}
+		nonceErr := nonce.Error(token, "nonces")
+		if nonceErr == nil {
+			permits[i].Nonce = nonce.Values[0].(*big.Int)
+		}
+		separatorErr := separator.Error(token, "DOMAIN_SEPARATOR")
+		if separatorErr == nil {
+			permits[i].DomainSeparator = separator.Values[0].([32]byte)
+		}
+		permits[i].Err = errors.Join(nonceErr, separatorErr)
+	}
+	return permits, nil
+}
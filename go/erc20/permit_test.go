@@ -0,0 +1,85 @@
+package erc20_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/john-na4/multicall3/go/erc20"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// permitData is the EIP-712 permit of 1 DAI to the router, as signed by
+// wallets
+var permitData = apitypes.TypedData{
+	Types: apitypes.Types{
+		"EIP712Domain": {
+			{Name: "name", Type: "string"},
+			{Name: "version", Type: "string"},
+			{Name: "chainId", Type: "uint256"},
+			{Name: "verifyingContract", Type: "address"},
+		},
+		"Permit": {
+			{Name: "owner", Type: "address"},
+			{Name: "spender", Type: "address"},
+			{Name: "value", Type: "uint256"},
+			{Name: "nonce", Type: "uint256"},
+			{Name: "deadline", Type: "uint256"},
+		},
+	},
+	PrimaryType: "Permit",
+	Domain: apitypes.TypedDataDomain{
+		Name:              "Dai Stablecoin",
+		Version:           "1",
+		ChainId:           math.NewHexOrDecimal256(1),
+		VerifyingContract: dai.Hex(),
+	},
+	Message: apitypes.TypedDataMessage{
+		"owner":    common.Address{19: 1}.Hex(),
+		"spender":  router.Hex(),
+		"value":    "1000000000000000000",
+		"nonce":    "3",
+		"deadline": "1700000000",
+	},
+}
+
+func TestPermits(t *testing.T) {
+	separator, err := permitData.HashStruct("EIP712Domain", permitData.Domain.Map())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		dai: fake.Methods(erc20.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"nonces":           fake.Returns(big.NewInt(3)),
+			"DOMAIN_SEPARATOR": fake.Returns([32]byte(separator)),
+		}),
+		broken: fake.Methods(erc20.ABI, nil),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := common.Address{19: 1}
+	permits, err := erc20.Permits(context.Background(), client, owner, []common.Address{dai, broken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := permits[0]; got.Token != dai || got.Owner != owner || got.Nonce.Int64() != 3 || got.DomainSeparator != common.BytesToHash(separator) || got.Err != nil {
+		t.Fatalf("got permit %+v", got)
+	}
+	if got := permits[1]; got.Nonce != nil || !errors.Is(got.Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got permit %+v of a token without permits, want the reverts", got)
+	}
+
+	want, _, err := apitypes.TypedDataAndHash(permitData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := permits[0].Digest(router, big.NewInt(1e18), big.NewInt(1_700_000_000)); got != common.BytesToHash(want) {
+		t.Errorf("got digest %s, want the EIP-712 hash %x", got, want)
+	}
+}
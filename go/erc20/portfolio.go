@@ -0,0 +1,92 @@
+package erc20
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// Portfolio holds the balances of a set of wallets in a set of tokens, along
+// with the metadata of the tokens and the native balances of the wallets
+type Portfolio struct {
+	Wallets []common.Address
+	// Tokens holds the metadata of every token, in the order they were given
+	Tokens []Metadata
+	// Balances[i][j] is the balance of Wallets[i] in Tokens[j]
+	Balances [][]Balance
+	// Native[i] is the native balance of Wallets[i], in wei, or nil if
+	// NativeErr[i] is set
+	Native []*big.Int
+	// NativeErr[i] is the error the native balance of Wallets[i] failed
+	// with, as when reading it from a chain without Multicall3 in deployless
+	// mode
+	NativeErr []error
+}
+
+// FetchPortfolio fetches the balance of every wallet in every token, the
+// metadata of every token and the native balance of every wallet in a single
+// batch, which the client splits into as few multicalls as its limits allow,
+// see multicall.WithMaxCalls. A token whose contract fails, or that is not a
+// token at all, only sets the Err of its metadata and balances, and a native
+// balance that cannot be read only sets the NativeErr of its wallet, so the
+// error is only set if the batch itself failed.
+func FetchPortfolio(ctx context.Context, client *multicall.Client, wallets, tokens []common.Address, opts ...multicall.CallOption) (*Portfolio, error) {
+	batch := client.NewBatch()
+	for _, token := range tokens {
+		addMetadata(batch, token)
+	}
+	for _, wallet := range wallets {
+		batch.AddEthBalance(wallet)
+		for _, token := range tokens {
+			batch.Add(token, ABI, "balanceOf", wallet).AllowFailure()
+		}
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	portfolio := &Portfolio{
+		Wallets:   wallets,
+		Tokens:    make([]Metadata, len(tokens)),
+		Balances:  make([][]Balance, len(wallets)),
+		Native:    make([]*big.Int, len(wallets)),
+		NativeErr: make([]error, len(wallets)),
+	}
+	for j, token := range tokens {
+		portfolio.Tokens[j] = metadataOf(token, results[metadataCalls*j:])
+	}
+	results = results[metadataCalls*len(tokens):]
+	for i, wallet := range wallets {
+		walletResults := results[i*(1+len(tokens)) : (i+1)*(1+len(tokens))]
+		if portfolio.NativeErr[i] = walletResults[0].Error(client.Address(), "getEthBalance"); portfolio.NativeErr[i] == nil {
+			portfolio.Native[i] = walletResults[0].Values[0].(*big.Int)
+		}
+		portfolio.Balances[i] = make([]Balance, len(tokens))
+		for j, token := range tokens {
+			balance := Balance{Wallet: wallet, Token: token}
+			if balance.Err = walletResults[1+j].Error(token, "balanceOf"); balance.Err == nil {
+				balance.Amount = walletResults[1+j].Values[0].(*big.Int)
+			}
+			portfolio.Balances[i][j] = balance
+		}
+	}
+	return portfolio, nil
+}
+
+// Balance returns the balance of wallet in token, or nil if either is not
+// part of the portfolio or the token failed to return it
+func (p *Portfolio) Balance(wallet, token common.Address) *big.Int {
+	for i := range p.Wallets {
+		if p.Wallets[i] != wallet {
+			continue
+		}
+		for _, balance := range p.Balances[i] {
+			if balance.Token == token {
+				return balance.Amount
+			}
+		}
+	}
+	return nil
+}
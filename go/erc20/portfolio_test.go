@@ -0,0 +1,37 @@
+package erc20_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/erc20"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+func TestFetchPortfolio(t *testing.T) {
+	wallets := []common.Address{{19: 1}, {19: 2}}
+	portfolio, err := erc20.FetchPortfolio(context.Background(), newClient(t), wallets, []common.Address{dai, broken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if portfolio.Tokens[0].Symbol != "DAI" || !errors.Is(portfolio.Tokens[1].Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got tokens %+v, want DAI and the broken token", portfolio.Tokens)
+	}
+	for i, wallet := range wallets {
+		if got := portfolio.Balance(wallet, dai); got == nil || got.Int64() != int64(i+1) {
+			t.Errorf("got DAI balance %v of %s, want %d", got, wallet, i+1)
+		}
+		if got := portfolio.Balance(wallet, broken); got != nil || portfolio.Balances[i][1].Err == nil {
+			t.Errorf("got balance %v of %s in the broken token, want its error", got, wallet)
+		}
+		// The fake chain has no Multicall3 to read native balances with
+		if portfolio.Native[i] != nil || !errors.Is(portfolio.NativeErr[i], multicall.ErrDecode) {
+			t.Errorf("got native balance %v, %v, want ErrDecode", portfolio.Native[i], portfolio.NativeErr[i])
+		}
+	}
+	if got := portfolio.Balance(common.Address{19: 3}, dai); got != nil {
+		t.Errorf("got balance %s of a wallet outside the portfolio", got)
+	}
+}
@@ -0,0 +1,111 @@
+// Package erc4337 reads the state of ERC-4337 smart accounts, their nonces
+// and deposits at the EntryPoint along with their own getters, in bulk
+// through Multicall3, so that bundlers and account abstraction wallets can
+// refresh many accounts in one round trip:
+//
+//	accounts, err := erc4337.Accounts(ctx, client, erc4337.EntryPointV07, smartAccounts)
+//	for _, account := range accounts {
+//		fmt.Println(account.Address, account.Nonce, account.Deposit, account.Balance)
+//	}
+package erc4337
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// Addresses of the EntryPoint contracts, the same on every chain they are
+// deployed to
+var (
+	EntryPointV06 = common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	EntryPointV07 = common.HexToAddress("0x0000000071727De22E5E9d8BAf0edAc6f37da032")
+)
+
+// EntryPointABI is the part of the EntryPoint ABI used by the helpers, common
+// to versions 0.6 and 0.7
+var EntryPointABI = helper.MustParseABI("erc4337", entryPointABI)
+
+// AccountABI are the getters of smart accounts read by the helpers. They are
+// not part of ERC-4337, but most account implementations have them.
+var AccountABI = helper.MustParseABI("erc4337", accountABI)
+
+const entryPointABI = `[
+	{"type":"function","name":"getNonce","stateMutability":"view","inputs":[{"name":"sender","type":"address"},{"name":"key","type":"uint192"}],"outputs":[{"name":"nonce","type":"uint256"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+const accountABI = `[
+	{"type":"function","name":"entryPoint","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"owner","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]}
+]`
+
+// Account is the state of a smart account. Fields that failed are left as
+// zero values, and Err reports why.
+type Account struct {
+	Address common.Address
+	// Nonce is the EntryPoint nonce of the account for key 0, the nonce of
+	// its next user operation
+	Nonce *big.Int
+	// Deposit is the deposit of the account at the EntryPoint, which pays
+	// for the gas of its user operations
+	Deposit *big.Int
+	// Balance is the ETH balance of the account itself
+	Balance *big.Int
+	// EntryPoint and Owner are returned by the getters of the account of the
+	// same name. Accounts that do not implement them, or are not deployed
+	// yet, leave them as the zero address without an error.
+	EntryPoint common.Address
+	Owner      common.Address
+	// Err joins the errors of the EntryPoint calls and of the balance read
+	// that failed
+	Err error
+}
+
+// Accounts fetches the nonce and deposit of every account at entryPoint, and
+// its ETH balance, entry point and owner, in a single batch, returning them
+// in the order of the accounts. The error is only set if the batch itself
+// failed, the failures of an account are reported by its Account.Err.
+func Accounts(ctx context.Context, client *multicall.Client, entryPoint common.Address, accounts []common.Address, opts ...multicall.CallOption) ([]Account, error) {
+	batch := client.NewBatch()
+	for _, account := range accounts {
+		batch.Add(entryPoint, EntryPointABI, "getNonce", account, new(big.Int)).AllowFailure().
+			Add(entryPoint, EntryPointABI, "balanceOf", account).AllowFailure().
+			AddEthBalance(account).
+			Add(account, AccountABI, "entryPoint").AllowFailure().
+			Add(account, AccountABI, "owner").AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	state := make([]Account, len(accounts))
+	for i, account := range accounts {
+		accountResults := results[5*i : 5*i+5]
+		state[i] = Account{Address: account}
+		nonceErr := accountResults[0].Error(entryPoint, "getNonce")
+		if nonceErr == nil {
+			state[i].Nonce = accountResults[0].Values[0].(*big.Int)
+		}
+		depositErr := accountResults[1].Error(entryPoint, "balanceOf")
+		if depositErr == nil {
+			state[i].Deposit = accountResults[1].Values[0].(*big.Int)
+		}
+		balanceErr := accountResults[2].Error(client.Address(), "getEthBalance")
+		if balanceErr == nil {
+			state[i].Balance = accountResults[2].Values[0].(*big.Int)
+		}
+		if accountResults[3].Error(account, "entryPoint") == nil {
+			state[i].EntryPoint = accountResults[3].Values[0].(common.Address)
+		}
+		if accountResults[4].Error(account, "owner") == nil {
+			state[i].Owner = accountResults[4].Values[0].(common.Address)
+		}
+		state[i].Err = errors.Join(nonceErr, depositErr, balanceErr)
+	}
+	return state, nil
+}
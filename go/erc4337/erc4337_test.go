@@ -0,0 +1,69 @@
+package erc4337_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/erc4337"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	deployed   = common.Address{19: 1}
+	counterful = common.Address{19: 2}
+	rejected   = common.Address{19: 3}
+	signer     = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+)
+
+func TestAccounts(t *testing.T) {
+	// Accounts have the nonce of their last byte, 10 times that at the
+	// EntryPoint and 100 times that in ETH. Only deployed has code, and the
+	// EntryPoint reverts for rejected.
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		multicall.Address: fake.Methods(multicall.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getEthBalance": func(args []interface{}) ([]interface{}, error) {
+				return []interface{}{big.NewInt(100 * int64(args[0].(common.Address)[19]))}, nil
+			},
+		}),
+		erc4337.EntryPointV07: fake.Methods(erc4337.EntryPointABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getNonce": func(args []interface{}) ([]interface{}, error) {
+				if args[1].(*big.Int).Sign() != 0 {
+					return nil, &fake.Revert{}
+				}
+				return []interface{}{big.NewInt(int64(args[0].(common.Address)[19]))}, nil
+			},
+			"balanceOf": func(args []interface{}) ([]interface{}, error) {
+				if args[0].(common.Address) == rejected {
+					return nil, &fake.Revert{}
+				}
+				return []interface{}{big.NewInt(10 * int64(args[0].(common.Address)[19]))}, nil
+			},
+		}),
+		deployed: fake.Methods(erc4337.AccountABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"entryPoint": fake.Returns(erc4337.EntryPointV07),
+			"owner":      fake.Returns(signer),
+		}),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts, err := erc4337.Accounts(context.Background(), client, erc4337.EntryPointV07, []common.Address{deployed, counterful, rejected})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := accounts[0]; got.Err != nil || got.Nonce.Int64() != 1 || got.Deposit.Int64() != 10 || got.Balance.Int64() != 100 ||
+		got.EntryPoint != erc4337.EntryPointV07 || got.Owner != signer {
+		t.Errorf("got account %+v, want a deployed account owned by %s", got, signer)
+	}
+	// Accounts that are not deployed yet have no getters, without an error
+	if got := accounts[1]; got.Err != nil || got.Nonce.Int64() != 2 || got.Deposit.Int64() != 20 || got.EntryPoint != (common.Address{}) || got.Owner != (common.Address{}) {
+		t.Errorf("got account %+v, want a counterfactual account", got)
+	}
+	if got := accounts[2]; got.Deposit != nil || got.Nonce.Int64() != 3 || got.Balance.Int64() != 300 || !errors.Is(got.Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got account %+v, want the revert of balanceOf", got)
+	}
+}
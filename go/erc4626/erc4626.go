@@ -0,0 +1,175 @@
+// Package erc4626 reads the state of ERC-4626 tokenized vaults, and the
+// positions of their depositors, in bulk through Multicall3, as yield
+// dashboards need:
+//
+//	positions, err := erc4626.Positions(ctx, client, vaults, depositors)
+//	for _, position := range positions {
+//		fmt.Println(position.Vault, position.Shares, position.Assets)
+//	}
+//
+// Every call is allowed to fail, so a vault that does not implement a method,
+// or is not a vault at all, is reported on its own without failing the
+// others.
+package erc4626
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ABI is the part of the ERC-4626 ABI used by the helpers
+var ABI = helper.MustParseABI("erc4626", erc4626ABI)
+
+const erc4626ABI = `[
+	{"type":"function","name":"asset","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"totalAssets","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"totalSupply","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"convertToAssets","stateMutability":"view","inputs":[{"name":"shares","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"maxWithdraw","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// Vault is the state of a vault. Fields the vault failed to return are left
+// as zero values, and Err reports why.
+type Vault struct {
+	Address common.Address
+	// Asset is the underlying token of the vault
+	Asset common.Address
+	// Decimals are the decimals of the vault's shares
+	Decimals    uint8
+	TotalAssets *big.Int
+	TotalSupply *big.Int
+	// Err joins the errors of the vault getters that failed, whose fields
+	// are left zero
+	Err error
+}
+
+// Vaults fetches the underlying asset, share decimals, total assets and
+// total supply of shares of vaults in a single batch, returning them in the
+// order of the vaults. The error is only set if the batch itself failed, the
+// failures of a vault are reported by its Vault.Err.
+func Vaults(ctx context.Context, client *multicall.Client, vaults []common.Address, opts ...multicall.CallOption) ([]Vault, error) {
+	batch := client.NewBatch()
+	for _, vault := range vaults {
+		batch.Add(vault, ABI, "asset").AllowFailure().
+			Add(vault, ABI, "decimals").AllowFailure().
+			Add(vault, ABI, "totalAssets").AllowFailure().
+			Add(vault, ABI, "totalSupply").AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	info := make([]Vault, len(vaults))
+	for i, vault := range vaults {
+		var assetErr, decimalsErr, totalAssetsErr, totalSupplyErr error
+		info[i] = Vault{Address: vault}
+		if assetErr = results[4*i].Error(vault, "asset"); assetErr == nil {
+			info[i].Asset = results[4*i].Values[0].(common.Address)
+		}
+		if decimalsErr = results[4*i+1].Error(vault, "decimals"); decimalsErr == nil {
+			info[i].Decimals = results[4*i+1].Values[0].(uint8)
+		}
+		if totalAssetsErr = results[4*i+2].Error(vault, "totalAssets"); totalAssetsErr == nil {
+			info[i].TotalAssets = results[4*i+2].Values[0].(*big.Int)
+		}
+		if totalSupplyErr = results[4*i+3].Error(vault, "totalSupply"); totalSupplyErr == nil {
+			info[i].TotalSupply = results[4*i+3].Values[0].(*big.Int)
+		}
+		info[i].Err = errors.Join(assetErr, decimalsErr, totalAssetsErr, totalSupplyErr)
+	}
+	return info, nil
+}
+
+// Position is the position of a depositor in a vault. Fields the vault failed
+// to return are left nil, and Err reports why.
+type Position struct {
+	Vault     common.Address
+	Depositor common.Address
+	// Shares is the depositor's balance of vault shares
+	Shares *big.Int
+	// Assets is the amount of underlying assets the shares are worth
+	Assets *big.Int
+	// MaxWithdraw is the amount of underlying assets the depositor can
+	// withdraw at once, which the vault may limit below Assets
+	MaxWithdraw *big.Int
+	// Err joins the errors of the balance, conversion and withdrawal limit
+	// that failed to be read
+	Err error
+}
+
+// Positions fetches the position of every depositor in every vault. The
+// positions are returned by vault, then by depositor, so the position of
+// depositors[j] in vaults[i] is at index i*len(depositors)+j. Like Vaults,
+// the error is only set if a batch itself failed.
+//
+// The shares are converted to assets with the vault's own convertToAssets,
+// which needs a second batch once the shares are known. It is executed
+// against the block the first one was, so that both see the same state.
+func Positions(ctx context.Context, client *multicall.Client, vaults, depositors []common.Address, opts ...multicall.CallOption) ([]Position, error) {
+	blockNumber := multicall.BlockNumber()
+	batch := client.NewBatch().AddSelf(blockNumber)
+	for _, vault := range vaults {
+		for _, depositor := range depositors {
+			batch.Add(vault, ABI, "balanceOf", depositor).AllowFailure().
+				Add(vault, ABI, "maxWithdraw", depositor).AllowFailure()
+		}
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	positions := make([]Position, 0, len(vaults)*len(depositors))
+	errs := make([][]error, 0, len(vaults)*len(depositors))
+	conversions := client.NewBatch()
+	var converted []int
+	for _, vault := range vaults {
+		for _, depositor := range depositors {
+			shares, maxWithdraw := results[1+2*len(positions)], results[2+2*len(positions)]
+			position := Position{Vault: vault, Depositor: depositor}
+			sharesErr := shares.Error(vault, "balanceOf")
+			if sharesErr == nil {
+				position.Shares = shares.Values[0].(*big.Int)
+				if position.Shares.Sign() == 0 {
+					position.Assets = new(big.Int)
+				} else {
+					conversions.Add(vault, ABI, "convertToAssets", position.Shares).AllowFailure()
+					converted = append(converted, len(positions))
+				}
+			}
+			maxWithdrawErr := maxWithdraw.Error(vault, "maxWithdraw")
+			if maxWithdrawErr == nil {
+				position.MaxWithdraw = maxWithdraw.Values[0].(*big.Int)
+			}
+			positions = append(positions, position)
+			errs = append(errs, []error{sharesErr, maxWithdrawErr})
+		}
+	}
+	if len(converted) > 0 {
+		block, err := blockNumber.Get()
+		if err != nil {
+			return nil, err
+		}
+		results, err := conversions.ExecuteResults(ctx, append(opts[:len(opts):len(opts)], multicall.AtBlock(block))...)
+		if err != nil {
+			return nil, err
+		}
+		for i, j := range converted {
+			assetsErr := results[i].Error(positions[j].Vault, "convertToAssets")
+			if assetsErr == nil {
+				positions[j].Assets = results[i].Values[0].(*big.Int)
+			}
+			errs[j] = append(errs[j], assetsErr)
+		}
+	}
+	for i := range positions {
+		positions[i].Err = errors.Join(errs[i]...)
+	}
+	return positions, nil
+}
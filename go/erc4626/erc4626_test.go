@@ -0,0 +1,95 @@
+package erc4626_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/erc4626"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	vault   = common.HexToAddress("0x83F20F44975D03b1b09e64809B757c47f942BEeA")
+	usdc    = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	partial = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+)
+
+// newClient returns a client reading a vault whose shares are worth two
+// assets each, in which every depositor holds 10 times the last byte of its
+// address in shares, and a vault that only implements asset
+func newClient(t *testing.T) (*multicall.Client, *fake.Caller) {
+	t.Helper()
+	caller := fake.NewCaller(map[common.Address]fake.Contract{
+		multicall.Address: fake.Methods(multicall.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getBlockNumber": fake.Returns(big.NewInt(100)),
+		}),
+		vault: fake.Methods(erc4626.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"asset":       fake.Returns(usdc),
+			"decimals":    fake.Returns(uint8(6)),
+			"totalAssets": fake.Returns(big.NewInt(2000)),
+			"totalSupply": fake.Returns(big.NewInt(1000)),
+			"balanceOf": func(args []interface{}) ([]interface{}, error) {
+				return []interface{}{big.NewInt(10 * int64(args[0].(common.Address)[19]))}, nil
+			},
+			"convertToAssets": func(args []interface{}) ([]interface{}, error) {
+				return []interface{}{new(big.Int).Mul(args[0].(*big.Int), big.NewInt(2))}, nil
+			},
+			"maxWithdraw": fake.Returns(big.NewInt(5)),
+		}),
+		partial: fake.Methods(erc4626.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"asset": fake.Returns(usdc),
+		}),
+	})
+	client, err := multicall.NewClient(caller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, caller
+}
+
+func TestVaults(t *testing.T) {
+	client, _ := newClient(t)
+	vaults, err := erc4626.Vaults(context.Background(), client, []common.Address{vault, partial})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := vaults[0]; got.Asset != usdc || got.Decimals != 6 || got.TotalAssets.Int64() != 2000 || got.TotalSupply.Int64() != 1000 || got.Err != nil {
+		t.Errorf("got vault %+v", got)
+	}
+	if got := vaults[1]; got.Asset != usdc || got.TotalAssets != nil || !errors.Is(got.Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got vault %+v, want its asset and the errors of the other getters", got)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	client, caller := newClient(t)
+	depositors := []common.Address{{19: 1}, {}}
+	positions, err := erc4626.Positions(context.Background(), client, []common.Address{vault, partial}, depositors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(positions) != 4 {
+		t.Fatalf("got %d positions, want 4", len(positions))
+	}
+	if got := positions[0]; got.Depositor != depositors[0] || got.Shares.Int64() != 10 || got.Assets.Int64() != 20 || got.MaxWithdraw.Int64() != 5 || got.Err != nil {
+		t.Errorf("got position %+v, want 10 shares worth 20 assets", got)
+	}
+	// Positions without shares are worth nothing, without converting them
+	if got := positions[1]; got.Shares.Sign() != 0 || got.Assets.Sign() != 0 || got.Err != nil {
+		t.Errorf("got position %+v, want an empty one", got)
+	}
+	for _, got := range positions[2:] {
+		if got.Vault != partial || got.Shares != nil || got.Assets != nil || !errors.Is(got.Err, multicall.ErrExecutionReverted) {
+			t.Errorf("got position %+v in a vault without balanceOf", got)
+		}
+	}
+	// The positions, then the only conversion needed
+	if got, want := caller.Executed(), []int{9, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("executed multicalls of %v calls, want %v", got, want)
+	}
+}
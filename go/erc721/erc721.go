@@ -0,0 +1,145 @@
+// Package erc721 reads the owners, metadata URIs, approvals and balances of
+// ERC-721 tokens in bulk through Multicall3, across any number of
+// collections:
+//
+//	tokens, err := erc721.Tokens(ctx, client, []erc721.NFT{
+//		{Collection: punks, ID: big.NewInt(7804)},
+//		{Collection: azuki, ID: big.NewInt(9605)},
+//	})
+//	for _, token := range tokens {
+//		fmt.Println(token.Exists, token.Owner, token.URI)
+//	}
+//
+// ownerOf reverts for tokens that were never minted or have been burned, so
+// every call is allowed to fail, and such tokens are reported as not existing
+// instead of failing the batch.
+package erc721
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ABI is the part of the ERC-721 ABI used by the helpers
+var ABI = helper.MustParseABI("erc721", erc721ABI)
+
+const erc721ABI = `[
+	{"type":"function","name":"ownerOf","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"tokenURI","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"getApproved","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// NFT identifies a token by its collection and ID
+type NFT struct {
+	Collection common.Address
+	ID         *big.Int
+}
+
+// Token is the state of an NFT. A token that does not exist has no other
+// field set.
+type Token struct {
+	NFT
+	// Exists is false if ownerOf reverted or returned the zero address
+	Exists bool
+	Owner  common.Address
+	URI    string
+	// Approved is the account approved to transfer the token, or the zero
+	// address
+	Approved common.Address
+	// Err joins the errors of tokenURI and getApproved for an existing
+	// token, such as tokenURI on a collection without metadata
+	Err error
+}
+
+// Tokens fetches the owner, metadata URI and approved account of every NFT in
+// a single batch, returning them in the order of the NFTs. The error is only
+// set if the batch itself failed, the failures of a token are reported by its
+// Token.Err.
+func Tokens(ctx context.Context, client *multicall.Client, nfts []NFT, opts ...multicall.CallOption) ([]Token, error) {
+	batch := client.NewBatch()
+	for _, nft := range nfts {
+		batch.Add(nft.Collection, ABI, "ownerOf", nft.ID).AllowFailure().
+			Add(nft.Collection, ABI, "tokenURI", nft.ID).AllowFailure().
+			Add(nft.Collection, ABI, "getApproved", nft.ID).AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]Token, len(nfts))
+	for i, nft := range nfts {
+		owner, uri, approved := results[3*i], results[3*i+1], results[3*i+2]
+		tokens[i] = Token{NFT: nft}
+		if !owner.Success {
+			continue
+		}
+		if owner.Err != nil {
+			tokens[i].Err = owner.Err
+			continue
+		}
+		tokens[i].Owner = owner.Values[0].(common.Address)
+		if tokens[i].Owner == (common.Address{}) {
+			continue
+		}
+		tokens[i].Exists = true
+		uriErr := uri.Error(subject(nft), "tokenURI")
+		if uriErr == nil {
+			tokens[i].URI = uri.Values[0].(string)
+		}
+		approvedErr := approved.Error(subject(nft), "getApproved")
+		if approvedErr == nil {
+			tokens[i].Approved = approved.Values[0].(common.Address)
+		}
+		tokens[i].Err = errors.Join(uriErr, approvedErr)
+	}
+	return tokens, nil
+}
+
+// subject describes nft in the errors of its calls
+func subject(nft NFT) string {
+	return fmt.Sprintf("%s #%s", nft.Collection, nft.ID)
+}
+
+// Balance is the number of tokens of a collection an owner holds. Count is
+// nil if the collection failed to return it, and Err reports why.
+type Balance struct {
+	Owner      common.Address
+	Collection common.Address
+	Count      *big.Int
+	Err        error
+}
+
+// BalancesOf fetches the number of tokens every owner holds in every
+// collection in a single batch. The balances are returned by owner, then by
+// collection, so the balance of owners[i] in collections[j] is at index
+// i*len(collections)+j. Like Tokens, the error is only set if the batch
+// itself failed.
+func BalancesOf(ctx context.Context, client *multicall.Client, owners, collections []common.Address, opts ...multicall.CallOption) ([]Balance, error) {
+	batch := client.NewBatch()
+	calls := make([]*multicall.CallOf[*big.Int], 0, len(owners)*len(collections))
+	for _, owner := range owners {
+		for _, collection := range collections {
+			call := multicall.View[*big.Int](ABI, "balanceOf", owner)
+			batch.AddCall(collection, call).AllowFailure()
+			calls = append(calls, call)
+		}
+	}
+	if _, err := batch.ExecuteResults(ctx, opts...); err != nil {
+		return nil, err
+	}
+	balances := make([]Balance, 0, len(calls))
+	for i, owner := range owners {
+		for j, collection := range collections {
+			count, err := calls[i*len(collections)+j].Get()
+			balances = append(balances, Balance{Owner: owner, Collection: collection, Count: count, Err: err})
+		}
+	}
+	return balances, nil
+}
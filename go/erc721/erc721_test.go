@@ -0,0 +1,111 @@
+package erc721_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/erc721"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var (
+	punks    = common.HexToAddress("0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB")
+	noURI    = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	holder   = common.Address{19: 0x11}
+	operator = common.Address{19: 0x22}
+)
+
+// collection returns a collection in which token 1 is held by holder and
+// approved for operator, token 2 was never minted, and token 3 is owned by
+// the zero address. Without uri, tokenURI reverts.
+func collection(uri bool) fake.Contract {
+	methods := map[string]func(args []interface{}) ([]interface{}, error){
+		"ownerOf": func(args []interface{}) ([]interface{}, error) {
+			switch args[0].(*big.Int).Int64() {
+			case 1:
+				return []interface{}{holder}, nil
+			case 3:
+				return []interface{}{common.Address{}}, nil
+			}
+			return nil, &fake.Revert{}
+		},
+		"getApproved": fake.Returns(operator),
+		"balanceOf": func(args []interface{}) ([]interface{}, error) {
+			owner := args[0].(common.Address)
+			return []interface{}{big.NewInt(int64(owner[len(owner)-1]))}, nil
+		},
+	}
+	if uri {
+		methods["tokenURI"] = func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{"ipfs://token/" + args[0].(*big.Int).String()}, nil
+		}
+	}
+	return fake.Methods(erc721.ABI, methods)
+}
+
+func newClient(t *testing.T) *multicall.Client {
+	t.Helper()
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		punks: collection(true),
+		noURI: collection(false),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestTokens(t *testing.T) {
+	tokens, err := erc721.Tokens(context.Background(), newClient(t), []erc721.NFT{
+		{Collection: punks, ID: big.NewInt(1)},
+		{Collection: punks, ID: big.NewInt(2)},
+		{Collection: punks, ID: big.NewInt(3)},
+		{Collection: noURI, ID: big.NewInt(1)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token := tokens[0]; !token.Exists || token.Owner != holder || token.URI != "ipfs://token/1" || token.Approved != operator || token.Err != nil {
+		t.Errorf("got token %+v, want it held by %s", token, holder)
+	}
+	for _, token := range tokens[1:3] {
+		if token.Exists || token.Err != nil {
+			t.Errorf("got token %+v, want it not to exist", token)
+		}
+	}
+	// The token exists even without metadata
+	if token := tokens[3]; !token.Exists || token.URI != "" || token.Approved != operator || !errors.Is(token.Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got token %+v, want it to exist with the error of tokenURI", token)
+	}
+}
+
+func TestBalancesOf(t *testing.T) {
+	owners := []common.Address{{19: 1}, {19: 2}}
+	balances, err := erc721.BalancesOf(context.Background(), newClient(t), owners, []common.Address{punks, {19: 9}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(balances) != 4 {
+		t.Fatalf("got %d balances, want 4", len(balances))
+	}
+	for i, balance := range balances {
+		if balance.Owner != owners[i/2] {
+			t.Errorf("balance %d is of %s, want %s", i, balance.Owner, owners[i/2])
+		}
+	}
+	for _, i := range []int{0, 2} {
+		if balances[i].Err != nil || balances[i].Count.Int64() != int64(i/2+1) {
+			t.Errorf("got balance %v, %v, want %d", balances[i].Count, balances[i].Err, i/2+1)
+		}
+	}
+	// Accounts without code return no data, which does not decode
+	for _, i := range []int{1, 3} {
+		if balances[i].Count != nil || !errors.Is(balances[i].Err, multicall.ErrDecode) {
+			t.Errorf("got balance %v, %v of an account without code, want ErrDecode", balances[i].Count, balances[i].Err)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Package fourbyte resolves function and error selectors to their likely
+// signatures with the 4byte.directory signature database, to render failed
+// calls and raw calldata whose ABI is unknown:
+//
+//	resolver := fourbyte.New()
+//	revert, err := resolver.DecodeRevert(ctx, result.ReturnData)
+//	fmt.Println(revert) // InsufficientBalance(100, 250)
+//
+// Selectors are only 4 bytes long, so a selector may match several
+// signatures, and the one found is a guess that only holds if the data
+// decodes with it.
+package fourbyte
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// DefaultURL is the URL of the public 4byte.directory
+const DefaultURL = "https://www.4byte.directory"
+
+// Resolver looks up selectors in a 4byte.directory database, caching the
+// signatures it finds, or the lack thereof, for its lifetime. It is safe for
+// concurrent use.
+type Resolver struct {
+	url    string
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[[4]byte][]string
+}
+
+// Option configures a Resolver
+type Option func(*Resolver)
+
+// WithURL sets the URL of the database, DefaultURL by default, for mirrors
+// and self-hosted instances
+func WithURL(url string) Option {
+	return func(r *Resolver) {
+		r.url = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithHTTPClient sets the client requests to the database are sent with,
+// http.DefaultClient by default
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *Resolver) {
+		r.client = client
+	}
+}
+
+// New returns a Resolver querying DefaultURL, unless configured otherwise
+func New(opts ...Option) *Resolver {
+	r := &Resolver{
+		url:    DefaultURL,
+		client: http.DefaultClient,
+		cache:  make(map[[4]byte][]string),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// signaturesResponse is the body of the database's signature search
+type signaturesResponse struct {
+	Results []struct {
+		ID            int    `json:"id"`
+		TextSignature string `json:"text_signature"`
+	} `json:"results"`
+}
+
+// Lookup returns the text signatures, such as "transfer(address,uint256)",
+// whose selector is selector, oldest first, since signatures added later to
+// collide with an existing selector are rarely the genuine one. It returns
+// no signatures if the selector is unknown.
+func (r *Resolver) Lookup(ctx context.Context, selector [4]byte) ([]string, error) {
+	r.mu.Lock()
+	signatures, ok := r.cache[selector]
+	r.mu.Unlock()
+	if ok {
+		return signatures, nil
+	}
+
+	query := url.Values{"hex_signature": {hexutil.Encode(selector[:])}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url+"/api/v1/signatures/?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fourbyte: %w", err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fourbyte: lookup %x: %w", selector, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fourbyte: lookup %x: %s", selector, resp.Status)
+	}
+	var body signaturesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("fourbyte: decode response for %x: %w", selector, err)
+	}
+	sort.Slice(body.Results, func(i, j int) bool { return body.Results[i].ID < body.Results[j].ID })
+	signatures = make([]string, len(body.Results))
+	for i, result := range body.Results {
+		signatures[i] = result.TextSignature
+	}
+
+	r.mu.Lock()
+	r.cache[selector] = signatures
+	r.mu.Unlock()
+	return signatures, nil
+}
+
+// Decoded is calldata or revert data decoded with a signature found in the
+// database
+type Decoded struct {
+	// Signature is the text signature the data decodes with
+	Signature string
+	// Name is the function or error name of the signature
+	Name string
+	// Inputs describes the arguments of the signature, which are unnamed
+	Inputs abi.Arguments
+	// Args holds the decoded arguments
+	Args []interface{}
+}
+
+// String renders the decoded data as a call, such as
+// "transfer(0x6B175474E89094C44Da98b954EedeAC495271d0F, 100)"
+func (d *Decoded) String() string {
+	args := make([]string, len(d.Args))
+	for i, arg := range d.Args {
+		args[i] = fmt.Sprint(arg)
+	}
+	return fmt.Sprintf("%s(%s)", d.Name, strings.Join(args, ", "))
+}
+
+// ErrUnknownSelector is returned when no signature in the database matches
+// data
+var ErrUnknownSelector = errors.New("fourbyte: unknown selector")
+
+// Decode decodes calldata, or custom error revert data, with the oldest
+// signature of its selector that the data decodes and re-encodes exactly
+// with, so that signatures with the same selector but other arguments are
+// ruled out.
+func (r *Resolver) Decode(ctx context.Context, data []byte) (*Decoded, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("fourbyte: data too short for a selector: %s", hexutil.Encode(data))
+	}
+	var selector [4]byte
+	copy(selector[:], data)
+	signatures, err := r.Lookup(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, signature := range signatures {
+		name, inputs, err := parseSignature(signature)
+		if err != nil {
+			continue
+		}
+		args, err := inputs.Unpack(data[4:])
+		if err != nil {
+			continue
+		}
+		if packed, err := inputs.Pack(args...); err != nil || !bytes.Equal(packed, data[4:]) {
+			continue
+		}
+		return &Decoded{Signature: signature, Name: name, Inputs: inputs, Args: args}, nil
+	}
+	return nil, fmt.Errorf("%w 0x%x", ErrUnknownSelector, selector)
+}
+
+// DecodeRevert decodes the revert data of a failed call like
+// multicall.DecodeRevert, looking up the custom error it holds, if any, in
+// the database. The custom error is left undecoded if it is not found.
+func (r *Resolver) DecodeRevert(ctx context.Context, data []byte) (*multicall.Revert, error) {
+	revert := multicall.DecodeRevert(data)
+	if len(data) < 4 || revert.Message != "" || revert.PanicCode != nil {
+		return revert, nil
+	}
+	decoded, err := r.Decode(ctx, data)
+	if errors.Is(err, ErrUnknownSelector) {
+		return revert, nil
+	}
+	if err != nil {
+		return revert, err
+	}
+	customError := abi.NewError(decoded.Name, decoded.Inputs)
+	revert.CustomError, revert.Args = &customError, decoded.Args
+	return revert, nil
+}
+
+// Reason is like multicall.Result.Reason, but names the custom errors found
+// in the database
+func (r *Resolver) Reason(ctx context.Context, result multicall.Result) (string, error) {
+	if result.Success {
+		return "", nil
+	}
+	revert, err := r.DecodeRevert(ctx, result.ReturnData)
+	return revert.String(), err
+}
@@ -0,0 +1,90 @@
+package fourbyte
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// parseSignature parses a text signature such as "swap((address,uint256)[],bool)"
+// into its name and unnamed arguments
+func parseSignature(signature string) (string, abi.Arguments, error) {
+	open := strings.IndexByte(signature, '(')
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return "", nil, fmt.Errorf("invalid signature %q", signature)
+	}
+	types, err := splitTypes(signature[open+1 : len(signature)-1])
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid signature %q: %w", signature, err)
+	}
+	args := make(abi.Arguments, len(types))
+	for i, typ := range types {
+		marshaling, err := parseType(typ)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid signature %q: %w", signature, err)
+		}
+		args[i].Type, err = abi.NewType(marshaling.Type, "", marshaling.Components)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid signature %q: %w", signature, err)
+		}
+	}
+	return signature[:open], args, nil
+}
+
+// parseType describes typ, which may be a tuple such as "(uint256,bool)[]",
+// the way ABI JSON does
+func parseType(typ string) (abi.ArgumentMarshaling, error) {
+	if !strings.HasPrefix(typ, "(") {
+		return abi.ArgumentMarshaling{Type: typ}, nil
+	}
+	end := strings.LastIndexByte(typ, ')')
+	types, err := splitTypes(typ[1:end])
+	if err != nil {
+		return abi.ArgumentMarshaling{}, err
+	}
+	tuple := abi.ArgumentMarshaling{Type: "tuple" + typ[end+1:]}
+	for i, component := range types {
+		marshaling, err := parseType(component)
+		if err != nil {
+			return abi.ArgumentMarshaling{}, err
+		}
+		// Tuple components need distinct names to be decoded into a struct
+		marshaling.Name = fmt.Sprintf("field%d", i)
+		tuple.Components = append(tuple.Components, marshaling)
+	}
+	return tuple, nil
+}
+
+// splitTypes splits a comma separated list of types, leaving the commas
+// within tuples alone
+func splitTypes(list string) ([]string, error) {
+	if list == "" {
+		return nil, nil
+	}
+	var (
+		types []string
+		depth int
+		start int
+	)
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in %q", list)
+			}
+		case ',':
+			if depth == 0 {
+				types = append(types, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in %q", list)
+	}
+	return append(types, list[start:]), nil
+}
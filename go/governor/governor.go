@@ -0,0 +1,180 @@
+// Package governor reads the status of the proposals of OpenZeppelin
+// Governor contracts in bulk through Multicall3, for governance dashboards:
+//
+//	statuses, err := governor.Statuses(ctx, client, []governor.Proposal{
+//		{Governor: ensGovernor, ID: proposalID},
+//	})
+//	for _, status := range statuses {
+//		fmt.Println(status.ID, status.State, status.For, status.QuorumReached())
+//	}
+package governor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ABI is the part of the OpenZeppelin Governor ABI used by the helpers
+var ABI = helper.MustParseABI("governor", governorABI)
+
+const governorABI = `[
+	{"type":"function","name":"state","stateMutability":"view","inputs":[{"name":"proposalId","type":"uint256"}],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"proposalVotes","stateMutability":"view","inputs":[{"name":"proposalId","type":"uint256"}],"outputs":[{"name":"againstVotes","type":"uint256"},{"name":"forVotes","type":"uint256"},{"name":"abstainVotes","type":"uint256"}]},
+	{"type":"function","name":"proposalSnapshot","stateMutability":"view","inputs":[{"name":"proposalId","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"proposalDeadline","stateMutability":"view","inputs":[{"name":"proposalId","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"quorum","stateMutability":"view","inputs":[{"name":"timepoint","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// State is the state of a proposal, as returned by state
+type State uint8
+
+// States of a proposal
+const (
+	Pending State = iota
+	Active
+	Canceled
+	Defeated
+	Succeeded
+	Queued
+	Expired
+	Executed
+)
+
+var stateNames = [...]string{"Pending", "Active", "Canceled", "Defeated", "Succeeded", "Queued", "Expired", "Executed"}
+
+func (s State) String() string {
+	if int(s) < len(stateNames) {
+		return stateNames[s]
+	}
+	return fmt.Sprintf("State(%d)", uint8(s))
+}
+
+// Proposal identifies a proposal by its governor and ID
+type Proposal struct {
+	Governor common.Address
+	ID       *big.Int
+}
+
+// votes are the outputs of proposalVotes
+type votes struct {
+	AgainstVotes *big.Int
+	ForVotes     *big.Int
+	AbstainVotes *big.Int
+}
+
+// Status is the status of a proposal. Fields the governor failed to return
+// are left as zero values, and Err reports why.
+type Status struct {
+	Proposal
+	State   State
+	Against *big.Int
+	For     *big.Int
+	Abstain *big.Int
+	// Snapshot and Deadline are the block numbers, or timestamps for
+	// governors whose clock is time, voting starts after and ends at
+	Snapshot uint64
+	Deadline uint64
+	// Quorum is the quorum at the proposal's snapshot, nil for pending
+	// proposals, whose snapshot has not been reached yet
+	Quorum *big.Int
+	// Err joins the errors of the reads of the proposal that failed, whose
+	// fields are left zero
+	Err error
+}
+
+// QuorumReached reports whether the for and abstain votes of the proposal
+// reach its quorum, as GovernorCountingSimple counts them
+func (s Status) QuorumReached() bool {
+	if s.Quorum == nil || s.For == nil || s.Abstain == nil {
+		return false
+	}
+	return new(big.Int).Add(s.For, s.Abstain).Cmp(s.Quorum) >= 0
+}
+
+// Statuses fetches the state, votes, snapshot and deadline of every proposal
+// in a single batch, returning them in the order of the proposals. The error
+// is only set if a batch itself failed, the failures of a proposal are
+// reported by its Status.Err.
+//
+// The quorum of a proposal depends on its snapshot, so it is fetched with a
+// second batch once the snapshots are known, against the block the first
+// one was executed at.
+func Statuses(ctx context.Context, client *multicall.Client, proposals []Proposal, opts ...multicall.CallOption) ([]Status, error) {
+	blockNumber := multicall.BlockNumber()
+	batch := client.NewBatch().AddSelf(blockNumber)
+	tallies := make([]*multicall.CallOf[votes], len(proposals))
+	for i, proposal := range proposals {
+		tallies[i] = multicall.View[votes](ABI, "proposalVotes", proposal.ID)
+		batch.Add(proposal.Governor, ABI, "state", proposal.ID).AllowFailure().
+			AddCall(proposal.Governor, tallies[i]).AllowFailure().
+			Add(proposal.Governor, ABI, "proposalSnapshot", proposal.ID).AllowFailure().
+			Add(proposal.Governor, ABI, "proposalDeadline", proposal.ID).AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, len(proposals))
+	errs := make([][]error, len(proposals))
+	quorums := client.NewBatch()
+	var quorumOf []int
+	for i, proposal := range proposals {
+		proposalResults := results[1+4*i : 5+4*i]
+		statuses[i] = Status{Proposal: proposal}
+		stateErr := proposalResults[0].Error(subject(proposal), "state")
+		if stateErr == nil {
+			statuses[i].State = State(proposalResults[0].Values[0].(uint8))
+		}
+		votesErr := proposalResults[1].Error(subject(proposal), "proposalVotes")
+		if votesErr == nil {
+			v := tallies[i].Value()
+			statuses[i].Against, statuses[i].For, statuses[i].Abstain = v.AgainstVotes, v.ForVotes, v.AbstainVotes
+		}
+		snapshotErr := proposalResults[2].Error(subject(proposal), "proposalSnapshot")
+		if snapshotErr == nil {
+			snapshot := proposalResults[2].Values[0].(*big.Int)
+			statuses[i].Snapshot = snapshot.Uint64()
+			if stateErr != nil || statuses[i].State != Pending {
+				quorums.Add(proposal.Governor, ABI, "quorum", snapshot).AllowFailure()
+				quorumOf = append(quorumOf, i)
+			}
+		}
+		deadlineErr := proposalResults[3].Error(subject(proposal), "proposalDeadline")
+		if deadlineErr == nil {
+			statuses[i].Deadline = proposalResults[3].Values[0].(*big.Int).Uint64()
+		}
+		errs[i] = []error{stateErr, votesErr, snapshotErr, deadlineErr}
+	}
+	if len(quorumOf) > 0 {
+		block, err := blockNumber.Get()
+		if err != nil {
+			return nil, err
+		}
+		results, err := quorums.ExecuteResults(ctx, append(opts[:len(opts):len(opts)], multicall.AtBlock(block))...)
+		if err != nil {
+			return nil, err
+		}
+		for k, i := range quorumOf {
+			quorumErr := results[k].Error(subject(proposals[i]), "quorum")
+			if quorumErr == nil {
+				statuses[i].Quorum = results[k].Values[0].(*big.Int)
+			}
+			errs[i] = append(errs[i], quorumErr)
+		}
+	}
+	for i := range statuses {
+		statuses[i].Err = errors.Join(errs[i]...)
+	}
+	return statuses, nil
+}
+
+// subject describes proposal in the errors of its calls
+func subject(proposal Proposal) string {
+	return fmt.Sprintf("proposal %s of %s", proposal.ID, proposal.Governor)
+}
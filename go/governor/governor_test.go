@@ -0,0 +1,101 @@
+package governor_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/governor"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+var ensGovernor = common.HexToAddress("0x323A76393544d5ecca80cd6ef2A560C6a395b7E3")
+
+// proposal returns the proposal of ensGovernor with id
+func proposal(id int64) governor.Proposal {
+	return governor.Proposal{Governor: ensGovernor, ID: big.NewInt(id)}
+}
+
+// known returns the proposal with the ID of args among 1 and 2, and false
+// for any other
+func known(args []interface{}) (int64, bool) {
+	id := args[0].(*big.Int).Int64()
+	return id, id == 1 || id == 2
+}
+
+func TestStatuses(t *testing.T) {
+	// Proposal 1 is active with a quorum of 70 at its snapshot, proposal 2 is
+	// pending, and proposal 3 does not exist
+	caller := fake.NewCaller(map[common.Address]fake.Contract{
+		multicall.Address: fake.Methods(multicall.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getBlockNumber": fake.Returns(big.NewInt(100)),
+		}),
+		ensGovernor: fake.Methods(governor.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"state": func(args []interface{}) ([]interface{}, error) {
+				id, ok := known(args)
+				if !ok {
+					return nil, &fake.Revert{}
+				}
+				return []interface{}{map[int64]uint8{1: uint8(governor.Active), 2: uint8(governor.Pending)}[id]}, nil
+			},
+			"proposalVotes": func(args []interface{}) ([]interface{}, error) {
+				if id, _ := known(args); id != 1 {
+					return []interface{}{new(big.Int), new(big.Int), new(big.Int)}, nil
+				}
+				return []interface{}{big.NewInt(5), big.NewInt(60), big.NewInt(10)}, nil
+			},
+			"proposalSnapshot": func(args []interface{}) ([]interface{}, error) {
+				id, ok := known(args)
+				if !ok {
+					return nil, &fake.Revert{}
+				}
+				return []interface{}{big.NewInt(80 + 10*id)}, nil
+			},
+			"proposalDeadline": func(args []interface{}) ([]interface{}, error) {
+				id, _ := known(args)
+				return []interface{}{big.NewInt(110 + 10*id)}, nil
+			},
+			"quorum": func(args []interface{}) ([]interface{}, error) {
+				if args[0].(*big.Int).Int64() != 90 {
+					return nil, &fake.Revert{}
+				}
+				return []interface{}{big.NewInt(70)}, nil
+			},
+		}),
+	})
+	client, err := multicall.NewClient(caller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := governor.Statuses(context.Background(), client, []governor.Proposal{proposal(1), proposal(2), proposal(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := statuses[0]; got.Err != nil || got.State != governor.Active || got.Against.Int64() != 5 || got.For.Int64() != 60 || got.Abstain.Int64() != 10 ||
+		got.Snapshot != 90 || got.Deadline != 120 || got.Quorum.Int64() != 70 || !got.QuorumReached() {
+		t.Errorf("got status %+v, want an active proposal reaching its quorum", got)
+	}
+	if got := statuses[1]; got.Err != nil || got.State != governor.Pending || got.Snapshot != 100 || got.Quorum != nil || got.QuorumReached() {
+		t.Errorf("got status %+v, want a pending proposal without quorum", got)
+	}
+	if got := statuses[2]; got.Snapshot != 0 || got.Deadline != 140 || got.Quorum != nil || !errors.Is(got.Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got status %+v, want the reverts of state and proposalSnapshot", got)
+	}
+	// The quorum of the active proposal only
+	if got, want := caller.Executed(), []int{13, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("executed multicalls of %v calls, want %v", got, want)
+	}
+}
+
+func TestStateString(t *testing.T) {
+	if got := governor.Succeeded.String(); got != "Succeeded" {
+		t.Errorf("got %s, want Succeeded", got)
+	}
+	if got := governor.State(9).String(); got != "State(9)" {
+		t.Errorf("got %s, want State(9)", got)
+	}
+}
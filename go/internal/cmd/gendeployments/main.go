@@ -0,0 +1,116 @@
+// Command gendeployments converts the deployments.json list at the root of
+// the repository into the Go data file holding the deployments known to the
+// multicall package. It is run by go generate in the multicall directory.
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"math/big"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// canonical is the address Multicall3 is deployed at on most chains
+var canonical = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// entry is a deployment as listed in deployments.json. Address is only given
+// for deployments at a non-canonical address.
+type entry struct {
+	Name    string `json:"name"`
+	ChainID uint64 `json:"chainId"`
+	URL     string `json:"url"`
+	Address string `json:"address"`
+}
+
+// urlAddress matches an address in an explorer URL, but not a longer hash
+var urlAddress = regexp.MustCompile(`0x[0-9a-fA-F]{40}\b`)
+
+func main() {
+	in := flag.String("in", "deployments.json", "deployments list to convert")
+	out := flag.String("out", "deployments.go", "Go file to write")
+	flag.Parse()
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var entries []entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Fatalf("parse %s: %v", *in, err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gendeployments from deployments.json. DO NOT EDIT.\n\n")
+	buf.WriteString("package multicall\n\n")
+	buf.WriteString("import \"github.com/ethereum/go-ethereum/common\"\n\n")
+	buf.WriteString("var deployments = []Deployment{\n")
+	for _, e := range entries {
+		address, err := resolve(e)
+		if err != nil {
+			log.Printf("skipping %s (chain %d): %v", e.Name, e.ChainID, err)
+			continue
+		}
+		addressExpr := "Address"
+		if address != canonical {
+			addressExpr = fmt.Sprintf("common.HexToAddress(%q)", address.Hex())
+		}
+		fmt.Fprintf(&buf, "\t{Name: %q, ChainID: %d, Address: %s, URL: %q},\n", e.Name, e.ChainID, addressExpr, e.URL)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("format generated code: %v", err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// resolve returns the EVM address of a deployment: its address if given, else
+// the address in its explorer URL, else the canonical address
+func resolve(e entry) (common.Address, error) {
+	switch {
+	case common.IsHexAddress(e.Address):
+		return common.HexToAddress(e.Address), nil
+	case strings.HasPrefix(e.Address, "T"):
+		return tronAddress(e.Address)
+	case e.Address != "":
+		return common.Address{}, fmt.Errorf("address %s is not an EVM address", e.Address)
+	}
+	if match := urlAddress.FindString(e.URL); match != "" {
+		return common.HexToAddress(match), nil
+	}
+	return canonical, nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// tronAddress decodes a base58check Tron address, a 0x41 prefix followed by
+// the 20 byte EVM address
+func tronAddress(s string) (common.Address, error) {
+	n := new(big.Int)
+	for _, r := range s {
+		i := strings.IndexRune(base58Alphabet, r)
+		if i < 0 {
+			return common.Address{}, fmt.Errorf("invalid base58 address %s", s)
+		}
+		n.Mul(n, big.NewInt(58)).Add(n, big.NewInt(int64(i)))
+	}
+	raw := n.FillBytes(make([]byte, 25))
+	first := sha256.Sum256(raw[:21])
+	second := sha256.Sum256(first[:])
+	if raw[0] != 0x41 || !bytes.Equal(second[:4], raw[21:]) {
+		return common.Address{}, fmt.Errorf("invalid Tron address %s", s)
+	}
+	return common.BytesToAddress(raw[1:21]), nil
+}
@@ -0,0 +1,193 @@
+// Package fake simulates Multicall3 and the contracts it calls in Go, for the
+// tests of the multicall package and of the helper packages built on it.
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/bindings"
+	"github.com/john-na4/multicall3/go/internal/helper"
+)
+
+var multicallABI = helper.MustParseABI("fake", bindings.Multicall3MetaData.ABI)
+
+// Contract answers the calldata of a call with its return data, or fails it:
+// with a *Revert to revert, ErrOutOfGas to run the whole multicall out of gas,
+// or the error of ctx to time it out
+type Contract func(ctx context.Context, data []byte) ([]byte, error)
+
+// Revert reverts a call with Data
+type Revert struct {
+	Data []byte
+}
+
+func (r *Revert) Error() string {
+	return "reverted"
+}
+
+// ErrOutOfGas runs the multicall of a call out of gas
+var ErrOutOfGas = errors.New("out of gas")
+
+// Methods returns a contract implementing the methods of contractABI, each
+// answering its arguments with its outputs, or failing like a Contract.
+// Calls of other methods revert without data.
+func Methods(contractABI abi.ABI, methods map[string]func(args []interface{}) ([]interface{}, error)) Contract {
+	return func(ctx context.Context, data []byte) ([]byte, error) {
+		method, err := contractABI.MethodById(data)
+		if err != nil || methods[method.Name] == nil {
+			return nil, &Revert{}
+		}
+		args, err := method.Inputs.Unpack(data[4:])
+		if err != nil {
+			return nil, &Revert{}
+		}
+		outputs, err := methods[method.Name](args)
+		if err != nil {
+			return nil, err
+		}
+		return method.Outputs.Pack(outputs...)
+	}
+}
+
+// Returns returns a method answering any arguments with outputs
+func Returns(outputs ...interface{}) func(args []interface{}) ([]interface{}, error) {
+	return func(args []interface{}) ([]interface{}, error) {
+		return outputs, nil
+	}
+}
+
+// call and result mirror the Call3 and Result structs of Multicall3, and
+// plainCall the Call struct of aggregate
+type plainCall struct {
+	Target   common.Address
+	CallData []byte
+}
+
+type call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// Caller is a contract caller that executes aggregate, tryAggregate and
+// aggregate3 multicalls against contracts simulated in Go, as Multicall3
+// would at a fixed block of a chain. Calls to accounts without a contract
+// succeed with no return data.
+type Caller struct {
+	Block     int64
+	ChainID   int64
+	Contracts map[common.Address]Contract
+
+	mu sync.Mutex
+	// executed is the number of calls of each multicall executed, in order
+	executed []int
+}
+
+// NewCaller returns a caller executing multicalls against contracts
+func NewCaller(contracts map[common.Address]Contract) *Caller {
+	return &Caller{Block: 100, ChainID: 1, Contracts: contracts}
+}
+
+func (c *Caller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0}, nil
+}
+
+func (c *Caller) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	method, err := multicallABI.MethodById(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+	values, err := method.Inputs.Unpack(msg.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "getBlockNumber":
+		return method.Outputs.Pack(big.NewInt(c.Block))
+	case "getChainId":
+		return method.Outputs.Pack(big.NewInt(c.ChainID))
+	case "aggregate":
+		results, err := c.execute(ctx, plainCalls(values[0], false))
+		if err != nil {
+			return nil, err
+		}
+		returnData := make([][]byte, len(results))
+		for i, result := range results {
+			returnData[i] = result.ReturnData
+		}
+		return method.Outputs.Pack(big.NewInt(c.Block), returnData)
+	case "tryAggregate":
+		results, err := c.execute(ctx, plainCalls(values[1], !values[0].(bool)))
+		if err != nil {
+			return nil, err
+		}
+		return method.Outputs.Pack(results)
+	case "aggregate3":
+		results, err := c.execute(ctx, *abi.ConvertType(values[0], new([]call)).(*[]call))
+		if err != nil {
+			return nil, err
+		}
+		return method.Outputs.Pack(results)
+	}
+	return nil, fmt.Errorf("fake: %s not supported", method.Name)
+}
+
+// plainCalls converts the Call array of aggregate and tryAggregate, whose
+// calls may fail if allowFailure is set
+func plainCalls(value interface{}, allowFailure bool) []call {
+	plain := *abi.ConvertType(value, new([]plainCall)).(*[]plainCall)
+	calls := make([]call, len(plain))
+	for i, p := range plain {
+		calls[i] = call{Target: p.Target, AllowFailure: allowFailure, CallData: p.CallData}
+	}
+	return calls
+}
+
+// execute executes the calls of a multicall, reverting it if a call that is
+// not allowed to fail reverts
+func (c *Caller) execute(ctx context.Context, calls []call) ([]result, error) {
+	c.mu.Lock()
+	c.executed = append(c.executed, len(calls))
+	c.mu.Unlock()
+	results := make([]result, len(calls))
+	for i, call := range calls {
+		contract, ok := c.Contracts[call.Target]
+		if !ok {
+			results[i] = result{Success: true, ReturnData: []byte{}}
+			continue
+		}
+		data, err := contract(ctx, call.CallData)
+		var revert *Revert
+		switch {
+		case errors.As(err, &revert):
+			if !call.AllowFailure {
+				return nil, errors.New("execution reverted: Multicall3: call failed")
+			}
+			results[i] = result{ReturnData: revert.Data}
+		case err != nil:
+			return nil, err
+		default:
+			results[i] = result{Success: true, ReturnData: data}
+		}
+	}
+	return results, nil
+}
+
+// Executed returns the number of calls of each multicall executed, in order
+func (c *Caller) Executed() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int(nil), c.executed...)
+}
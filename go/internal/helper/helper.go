@@ -0,0 +1,35 @@
+// Package helper holds the code shared by the multicall package and the
+// helper packages built on it, such as erc20.
+package helper
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// MustParseABI parses the JSON ABI raw of package pkg, panicking if it is
+// invalid, for ABIs defined as constants
+func MustParseABI(pkg, raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(pkg + ": invalid ABI: " + err.Error())
+	}
+	return parsed
+}
+
+// JoinErrors joins the non-nil errs with errors.Join, returning a single
+// error as is
+func JoinErrors(errs ...error) error {
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) == 1 {
+		return failed[0]
+	}
+	return errors.Join(failed...)
+}
@@ -0,0 +1,10 @@
+package multicall
+
+import (
+	"github.com/john-na4/multicall3/go/bindings"
+	"github.com/john-na4/multicall3/go/internal/helper"
+)
+
+// ABI is the full parsed Multicall3 ABI used by the Client. It can be passed to
+// Batch.Add to mix Multicall3 helper methods such as getBlockNumber into a batch.
+var ABI = helper.MustParseABI("multicall", bindings.Multicall3MetaData.ABI)
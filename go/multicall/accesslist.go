@@ -0,0 +1,90 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// AccessList is the access list of an aggregate3 transaction, as created by
+// eth_createAccessList
+type AccessList struct {
+	// AccessList holds the accounts and storage slots the transaction reads
+	// and writes, to be attached to it
+	AccessList types.AccessList
+	// GasUsed is the gas the transaction uses with the access list
+	GasUsed uint64
+}
+
+// CreateAccessList creates the access list of an aggregate3 transaction of
+// calls sent by from with eth_createAccessList, the accounts and storage
+// slots it touches, which are charged less when declared up front: attaching
+// it to the transaction reduces the gas of large batches that read the same
+// contracts over and over. The gas options of opts apply to the transaction,
+// which is executed on the selected block; other call options and deployless
+// mode cannot be used, since the transaction is sent to Multicall3 as is. It
+// needs an RPC client, see WithRPCClient.
+func (c *Client) CreateAccessList(ctx context.Context, from common.Address, calls []Call3, opts ...CallOption) (*AccessList, error) {
+	o := newCallOptions(opts)
+	if o.err != nil {
+		return nil, o.err
+	}
+	if err := c.checkVersion("aggregate3"); err != nil {
+		return nil, err
+	}
+	if c.deployless {
+		return nil, errors.New("multicall: access lists cannot be created in deployless mode")
+	}
+	if len(o.overrides) > 0 || o.blockOverrides != nil || o.from != nil {
+		return nil, errors.New("multicall: access lists cannot be created with overrides")
+	}
+	client, err := c.rpcClient("CreateAccessList")
+	if err != nil {
+		return nil, err
+	}
+	args := traceCallArgs{
+		From:                 &from,
+		To:                   &c.address,
+		Data:                 AppendAggregate3(nil, calls),
+		GasPrice:             (*hexutil.Big)(o.gasPrice),
+		MaxFeePerGas:         (*hexutil.Big)(o.gasFeeCap),
+		MaxPriorityFeePerGas: (*hexutil.Big)(o.gasTipCap),
+	}
+	if o.gas != 0 {
+		args.Gas = (*hexutil.Uint64)(&o.gas)
+	}
+	var result struct {
+		AccessList *types.AccessList `json:"accessList"`
+		GasUsed    hexutil.Uint64    `json:"gasUsed"`
+		Error      string            `json:"error"`
+	}
+	if err := client.CallContext(ctx, &result, "eth_createAccessList", args, blockArg(o)); err != nil {
+		return nil, fmt.Errorf("multicall: create access list: %w", classify(err))
+	}
+	// The access list of a transaction that reverts is only partial
+	if result.Error != "" {
+		return nil, withKind(ErrExecutionReverted, fmt.Errorf("multicall: create access list: %s", result.Error))
+	}
+	if result.AccessList == nil {
+		return nil, errors.New("multicall: create access list: no access list")
+	}
+	return &AccessList{AccessList: *result.AccessList, GasUsed: uint64(result.GasUsed)}, nil
+}
+
+// AccessList creates the access list of an aggregate3 transaction of the
+// calls of the batch sent by from, see Client.CreateAccessList. The batch is
+// not split into chunks.
+func (b *Batch) AccessList(ctx context.Context, from common.Address, opts ...CallOption) (*AccessList, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	calls := make([]Call3, len(b.calls))
+	for i, call := range b.calls {
+		calls[i] = Call3{Target: call.target, AllowFailure: call.allowFailure, CallData: call.callData}
+	}
+	return b.client.CreateAccessList(ctx, from, calls, opts...)
+}
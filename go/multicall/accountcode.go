@@ -0,0 +1,177 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// AccountCode is the code size and code hash of an account
+type AccountCode struct {
+	Address common.Address
+	// Size is the size of the code of the account, in bytes
+	Size uint64
+	// Hash is the Keccak-256 hash of the code of the account, which is
+	// types.EmptyCodeHash for accounts without code
+	Hash common.Hash
+}
+
+// IsContract reports whether the account has code. Externally owned
+// accounts have none, and neither do contracts while their constructor
+// runs, or once they have self-destructed.
+func (a AccountCode) IsContract() bool {
+	return a.Size > 0
+}
+
+// codeReaderSize is the size of codeReaderCode
+const codeReaderSize = 55
+
+// codeReaderCode is the init code of a contract creation that returns the
+// EXTCODESIZE and EXTCODEHASH of each address appended to it as a 32 byte
+// word, as two words per address, without deploying anything. Multicall3
+// has no accessor for either. With D = 55 the offset of the addresses and
+// i the offset of the current one:
+//
+//	PUSH1 0                                                            [i]
+//	loop: JUMPDEST DUP1 PUSH2 D CODESIZE SUB GT ISZERO PUSH2 end JUMPI
+//	PUSH1 32 DUP2 PUSH2 D ADD DUP3 DUP1 ADD CODECOPY    copy to 2i
+//	DUP1 DUP1 ADD DUP1 MLOAD DUP1 EXTCODESIZE DUP3 MSTORE  [i 2i addr]
+//	EXTCODEHASH SWAP1 PUSH1 32 ADD MSTORE               store after size
+//	PUSH1 32 ADD PUSH2 loop JUMP
+//	end: JUMPDEST DUP1 DUP1 ADD PUSH1 0 RETURN
+var codeReaderCode = func() []byte {
+	code := common.FromHex(fmt.Sprintf(
+		"60005b80"+
+			"61%04x38031115"+
+			"61003057"+
+			"60208161%04x0182800139"+
+			"8080018051803b8252"+
+			"3f9060200152"+
+			"60200161000256"+
+			"5b8080016000f3",
+		codeReaderSize, codeReaderSize))
+	if len(code) != codeReaderSize {
+		panic("multicall: invalid account code reader")
+	}
+	return code
+}()
+
+// AccountCodes returns the code size and code hash of each of accounts,
+// which tells contracts apart from externally owned accounts, executed
+// against the block selected by opts. They are read by code executed as a
+// contract creation in eth_call, like WithDeployless, with 32 bytes of
+// calldata per account and about 2,700 gas, and are split into chunks and
+// executed against a single block like the calls of a batch.
+//
+// When the code reader fails, as on providers that reject contract creations
+// in eth_call or on chains without EXTCODEHASH, the code of each account is
+// fetched with eth_getCode instead, in JSON-RPC batches, if an RPC client is
+// available, see WithRPCClient. Unlike the code reader, eth_getCode does not
+// apply state or block overrides, so calls with overrides do not fall back.
+func (c *Client) AccountCodes(ctx context.Context, accounts []common.Address, opts ...CallOption) ([]AccountCode, error) {
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+	codes, err := chunked(ctx, c, accounts, opts, codeReaderSize, accountSize, c.readCodes)
+	if err == nil || ctx.Err() != nil || errors.Is(err, ErrRateLimited) {
+		return codes, err
+	}
+	o := newCallOptions(opts)
+	if o.err != nil || len(o.overrides) > 0 || o.blockOverrides != nil {
+		return codes, err
+	}
+	if _, rpcErr := c.rpcClient("AccountCodes"); rpcErr != nil {
+		return codes, err
+	}
+	return chunked(ctx, c, accounts, opts, 0, accountSize, c.getCodes)
+}
+
+// accountSize is the calldata size of an account of AccountCodes
+func accountSize(common.Address) int { return 32 }
+
+// readCodes reads the code size and hash of accounts with codeReaderCode
+func (c *Client) readCodes(ctx context.Context, opts *callOptions, accounts []common.Address) ([]AccountCode, error) {
+	data := make([]byte, 0, codeReaderSize+32*len(accounts))
+	data = append(data, codeReaderCode...)
+	for _, account := range accounts {
+		data = append(data, common.LeftPadBytes(account.Bytes(), 32)...)
+	}
+	// The code reader runs as its own creation, not as a multicall from the
+	// sender, which From would inject Multicall3 code into
+	o := *opts
+	o.from = nil
+	msg := ethereum.CallMsg{
+		Data:      data,
+		Gas:       o.gas,
+		GasPrice:  o.gasPrice,
+		GasFeeCap: o.gasFeeCap,
+		GasTipCap: o.gasTipCap,
+	}
+	output, err := c.callContract(ctx, msg, &o)
+	if err != nil {
+		return nil, fmt.Errorf("multicall: read account codes: %w", classify(err))
+	}
+	if len(output) != 64*len(accounts) {
+		return nil, withKind(ErrDecode, fmt.Errorf("multicall: account code reader returned %d bytes for %d accounts", len(output), len(accounts)))
+	}
+	codes := make([]AccountCode, len(accounts))
+	for i, account := range accounts {
+		word := output[64*i:]
+		codes[i] = AccountCode{
+			Address: account,
+			Size:    new(big.Int).SetBytes(word[:32]).Uint64(),
+			Hash:    common.BytesToHash(word[32:64]),
+		}
+		// EXTCODEHASH is zero for accounts that do not exist
+		if codes[i].Size == 0 {
+			codes[i].Hash = types.EmptyCodeHash
+		}
+	}
+	return codes, nil
+}
+
+// getCodes gets the code of accounts with a JSON-RPC batch of eth_getCode
+// requests and hashes it
+func (c *Client) getCodes(ctx context.Context, opts *callOptions, accounts []common.Address) ([]AccountCode, error) {
+	client, err := c.rpcClient("AccountCodes")
+	if err != nil {
+		return nil, err
+	}
+	block := blockArg(opts)
+	codes := make([]hexutil.Bytes, len(accounts))
+	batch := make([]rpc.BatchElem, len(accounts))
+	for i, account := range accounts {
+		batch[i] = rpc.BatchElem{Method: "eth_getCode", Args: []interface{}{account, block}, Result: &codes[i]}
+	}
+	if err := c.batchCall(ctx, client, batch); err != nil {
+		return nil, fmt.Errorf("multicall: get account codes: %w", err)
+	}
+	results := make([]AccountCode, len(accounts))
+	for i, account := range accounts {
+		if batch[i].Error != nil {
+			return nil, fmt.Errorf("multicall: get code of %s: %w", account, classify(batch[i].Error))
+		}
+		results[i] = AccountCode{Address: account, Size: uint64(len(codes[i])), Hash: crypto.Keccak256Hash(codes[i])}
+	}
+	return results, nil
+}
+
+// blockArg returns the JSON-RPC block parameter of the block selected by
+// opts
+func blockArg(opts *callOptions) interface{} {
+	switch {
+	case opts.blockHash != nil:
+		return map[string]interface{}{"blockHash": *opts.blockHash}
+	case opts.block != nil:
+		return rpc.BlockNumber(opts.block.Int64())
+	}
+	return Latest
+}
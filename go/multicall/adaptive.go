@@ -0,0 +1,39 @@
+package multicall
+
+import "sync"
+
+// adaptiveLimit tracks how many calls a provider currently accepts in a single
+// multicall. It is shrunk when a multicall fails for being too large or slow,
+// and grown back gradually as multicalls succeed.
+type adaptiveLimit struct {
+	min, max int
+
+	mu      sync.Mutex
+	current int
+}
+
+// limit returns the current number of calls per multicall
+func (l *adaptiveLimit) limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.current
+}
+
+// shrink halves the limit after a multicall of size calls failed. It reports
+// false if the limit cannot shrink any further.
+func (l *adaptiveLimit) shrink(size int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if size <= l.min {
+		return false
+	}
+	l.current = max(l.min, size/2)
+	return true
+}
+
+// grow increases the limit after a successful multicall
+func (l *adaptiveLimit) grow() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.current = min(l.max, l.current+max(1, l.current/4))
+}
@@ -0,0 +1,283 @@
+package multicall
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+)
+
+// AggregateResult represents the result of a multicall aggregate
+type AggregateResult struct {
+	BlockNumber *big.Int
+	ReturnData  [][]byte
+}
+
+// Results returns the return data of the calls as Results, all successful
+// since aggregate reverts if any call fails, for the same shape as the other
+// aggregate methods
+func (r *AggregateResult) Results() []Result {
+	results := make([]Result, len(r.ReturnData))
+	for i, data := range r.ReturnData {
+		results[i] = Result{Success: true, ReturnData: data}
+	}
+	return results
+}
+
+// Aggregate executes calls with the contract's aggregate method. The whole
+// batch reverts if any call fails.
+func (c *Client) Aggregate(ctx context.Context, calls []Call, opts ...CallOption) (*AggregateResult, error) {
+	var block blockTracker
+	returnData, err := chunked(ctx, c, calls, opts, methodSize, callSize, func(ctx context.Context, opts *callOptions, chunk []Call) ([][]byte, error) {
+		output, err := c.callEncoded(ctx, opts, nil, "aggregate", func(dst []byte) []byte {
+			return appendAggregate(dst, "aggregate", chunk)
+		})
+		if err != nil {
+			return nil, err
+		}
+		var result AggregateResult
+		if err := ABI.UnpackIntoInterface(&result, "aggregate", output); err != nil {
+			return nil, withKind(ErrDecode, fmt.Errorf("multicall: unpack aggregate result: %w", err))
+		}
+		if err := block.observe(&BlockResult{BlockNumber: result.BlockNumber}); err != nil {
+			return nil, err
+		}
+		return result.ReturnData, nil
+	})
+	if err != nil && incomplete(err) == nil {
+		return nil, err
+	}
+	result := &AggregateResult{ReturnData: returnData}
+	if block.first != nil {
+		result.BlockNumber = block.first.BlockNumber
+	}
+	return result, err
+}
+
+// Aggregate3 executes calls with the contract's aggregate3 method, returning
+// one Result per call. Only calls with AllowFailure set may fail without
+// reverting the whole batch.
+func (c *Client) Aggregate3(ctx context.Context, calls []Call3, opts ...CallOption) ([]Result, error) {
+	if c.cache != nil {
+		return c.aggregate3Cached(ctx, calls, opts)
+	}
+	return c.aggregate3Uncached(ctx, calls, opts)
+}
+
+// aggregate3Uncached executes calls like Aggregate3, without the client's
+// result cache
+func (c *Client) aggregate3Uncached(ctx context.Context, calls []Call3, opts []CallOption) ([]Result, error) {
+	if c.version < Version3 {
+		return c.aggregate3Compat(ctx, calls, opts)
+	}
+	if c.ccip != nil {
+		return c.aggregate3CCIP(ctx, calls, opts)
+	}
+	return c.aggregate3(ctx, calls, opts)
+}
+
+// aggregate3 executes calls with aggregate3 on Multicall3
+func (c *Client) aggregate3(ctx context.Context, calls []Call3, opts []CallOption) ([]Result, error) {
+	return chunked(ctx, c, calls, opts, methodSize, call3Size, func(ctx context.Context, opts *callOptions, chunk []Call3) ([]Result, error) {
+		if results, ok, err := c.executeOnNode(ctx, opts, chunk, nil); ok {
+			return results, err
+		}
+		output, err := c.callEncoded(ctx, opts, nil, "aggregate3", func(dst []byte) []byte {
+			return AppendAggregate3(dst, chunk)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return unpackResults("aggregate3", output)
+	})
+}
+
+// TryAggregate executes calls with the contract's tryAggregate method. If
+// requireSuccess is true the whole batch reverts when any call fails,
+// otherwise failed calls are reported through their Result.
+func (c *Client) TryAggregate(ctx context.Context, requireSuccess bool, calls []Call, opts ...CallOption) ([]Result, error) {
+	if c.version < Version2 {
+		return c.tryAggregateCompat(ctx, requireSuccess, calls, opts)
+	}
+	return chunked(ctx, c, calls, opts, methodSize+requireSuccessSize, callSize, func(ctx context.Context, opts *callOptions, chunk []Call) ([]Result, error) {
+		output, err := c.callEncoded(ctx, opts, nil, "tryAggregate", func(dst []byte) []byte {
+			return appendTryAggregate(dst, "tryAggregate", requireSuccess, chunk)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return unpackResults("tryAggregate", output)
+	})
+}
+
+// Aggregate3Value executes calls with the contract's aggregate3Value method,
+// forwarding each call's Value to its target. The sum of all values is
+// validated and sent as the msg.value of the multicall.
+func (c *Client) Aggregate3Value(ctx context.Context, calls []Call3Value, opts ...CallOption) ([]Result, error) {
+	if _, err := TotalValue(calls); err != nil {
+		return nil, err
+	}
+	if c.version < Version3 {
+		return c.aggregate3ValueCompat(ctx, calls, opts)
+	}
+	return chunked(ctx, c, calls, opts, methodSize, call3ValueSize, func(ctx context.Context, opts *callOptions, chunk []Call3Value) ([]Result, error) {
+		total, err := TotalValue(chunk)
+		if err != nil {
+			return nil, err
+		}
+		if len(c.backends) > 0 {
+			calls, values := make([]Call3, len(chunk)), make([]*big.Int, len(chunk))
+			for i, call := range chunk {
+				calls[i] = Call3{Target: call.Target, AllowFailure: call.AllowFailure, CallData: call.CallData}
+				values[i] = call.Value
+			}
+			if results, ok, err := c.executeOnNode(ctx, opts, calls, values); ok {
+				return results, err
+			}
+		}
+		output, err := c.callEncoded(ctx, opts, total, "aggregate3Value", func(dst []byte) []byte {
+			return AppendAggregate3Value(dst, chunk)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return unpackResults("aggregate3Value", output)
+	})
+}
+
+// BlockResult represents the result of a blockAndAggregate or
+// tryBlockAndAggregate multicall
+type BlockResult struct {
+	BlockNumber *big.Int
+	// BlockHash is computed on-chain as blockhash(block.number), which the
+	// EVM defines as zero for the block being executed.
+	BlockHash common.Hash
+	Results   []Result
+
+	// fingerprint identifies the block, with VerifyBlock
+	fingerprint *blockFingerprint
+}
+
+// BlockAndAggregate executes calls with the contract's blockAndAggregate
+// method. The whole batch reverts if any call fails.
+func (c *Client) BlockAndAggregate(ctx context.Context, calls []Call, opts ...CallOption) (*BlockResult, error) {
+	if c.version < Version2 {
+		return c.blockAggregateCompat(ctx, true, calls, opts)
+	}
+	return c.blockAggregate(ctx, "blockAndAggregate", calls, opts, methodSize, func(dst []byte, chunk []Call) []byte {
+		return appendAggregate(dst, "blockAndAggregate", chunk)
+	})
+}
+
+// TryBlockAndAggregate executes calls with the contract's tryBlockAndAggregate
+// method, with the same requireSuccess semantics as TryAggregate.
+func (c *Client) TryBlockAndAggregate(ctx context.Context, requireSuccess bool, calls []Call, opts ...CallOption) (*BlockResult, error) {
+	if c.version < Version2 {
+		return c.blockAggregateCompat(ctx, requireSuccess, calls, opts)
+	}
+	return c.blockAggregate(ctx, "tryBlockAndAggregate", calls, opts, methodSize+requireSuccessSize, func(dst []byte, chunk []Call) []byte {
+		return appendTryAggregate(dst, "tryBlockAndAggregate", requireSuccess, chunk)
+	})
+}
+
+// blockAggregate executes calls with method, one of blockAndAggregate and
+// tryBlockAndAggregate, whose calldata is encoded by encode.
+func (c *Client) blockAggregate(ctx context.Context, method string, calls []Call, opts []CallOption, baseSize int, encode func([]byte, []Call) []byte) (*BlockResult, error) {
+	var block blockTracker
+	verify := newCallOptions(opts).verifyBlock
+	if verify {
+		baseSize += fingerprintSize
+	}
+	results, err := chunked(ctx, c, calls, opts, baseSize, callSize, func(ctx context.Context, opts *callOptions, chunk []Call) ([]Result, error) {
+		if verify {
+			chunk = append(chunk[:len(chunk):len(chunk)], fingerprintCalls(c.address)...)
+		}
+		output, err := c.callEncoded(ctx, opts, nil, method, func(dst []byte) []byte {
+			return encode(dst, chunk)
+		})
+		if err != nil {
+			return nil, err
+		}
+		result, err := unpackBlockResult(method, output)
+		if err != nil {
+			return nil, err
+		}
+		if verify {
+			n := len(result.Results) - len(fingerprintMethods)
+			if result.fingerprint, err = unpackFingerprint(result.Results[n:]); err != nil {
+				return nil, err
+			}
+			result.Results = result.Results[:n]
+		}
+		if err := block.observe(result); err != nil {
+			return nil, err
+		}
+		return result.Results, nil
+	})
+	if err != nil && incomplete(err) == nil {
+		return nil, err
+	}
+	result := &BlockResult{Results: results}
+	if block.first != nil {
+		result.BlockNumber, result.BlockHash = block.first.BlockNumber, block.first.BlockHash
+		if verify {
+			if verifyErr := c.verifyBlock(ctx, result.BlockNumber, block.first.fingerprint); verifyErr != nil {
+				return result, helper.JoinErrors(verifyErr, err)
+			}
+		}
+	}
+	return result, err
+}
+
+// blockTracker checks that all chunks of a batch were executed at the same
+// block. It is safe for concurrent use.
+type blockTracker struct {
+	mu    sync.Mutex
+	first *BlockResult
+}
+
+// observe records the block of a chunk's result, failing if a previous chunk
+// was executed at a different block
+func (t *blockTracker) observe(result *BlockResult) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.first == nil {
+		t.first = result
+		return nil
+	}
+	if t.first.BlockNumber.Cmp(result.BlockNumber) != 0 {
+		return fmt.Errorf("multicall: chunks executed at different blocks %s and %s", t.first.BlockNumber, result.BlockNumber)
+	}
+	if t.first.fingerprint != nil && !t.first.fingerprint.equal(result.fingerprint) {
+		return fmt.Errorf("%w: chunks executed at different blocks numbered %s", ErrBlockMismatch, result.BlockNumber)
+	}
+	return nil
+}
+
+// unpackBlockResult decodes the (blockNumber, blockHash, Result[]) tuple
+// returned by method
+func unpackBlockResult(method string, output []byte) (*BlockResult, error) {
+	values, err := ABI.Unpack(method, output)
+	if err != nil {
+		return nil, withKind(ErrDecode, fmt.Errorf("multicall: unpack %s result: %w", method, err))
+	}
+	return &BlockResult{
+		BlockNumber: values[0].(*big.Int),
+		BlockHash:   common.Hash(values[1].([32]byte)),
+		Results:     *abi.ConvertType(values[2], new([]Result)).(*[]Result),
+	}, nil
+}
+
+// unpackResults decodes the Result[] returned by method
+func unpackResults(method string, output []byte) ([]Result, error) {
+	values, err := ABI.Unpack(method, output)
+	if err != nil {
+		return nil, withKind(ErrDecode, fmt.Errorf("multicall: unpack %s result: %w", method, err))
+	}
+	results := *abi.ConvertType(values[0], new([]Result)).(*[]Result)
+	return results, nil
+}
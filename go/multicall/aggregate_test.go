@@ -0,0 +1,43 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	client, caller := newFakeClient()
+	calls := []Call{{Target: tokenAddress, CallData: balanceCall(1).CallData}, {Target: tokenAddress, CallData: balanceCall(2).CallData}}
+	result, err := client.Aggregate(context.Background(), calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.BlockNumber.Int64() != caller.Block {
+		t.Errorf("got block %s, want %d", result.BlockNumber, caller.Block)
+	}
+	checkBalances(t, result.Results(), 1, 2)
+
+	calls = append(calls, Call{Target: reverterAddress})
+	if _, err := client.Aggregate(context.Background(), calls); !errors.Is(err, ErrExecutionReverted) {
+		t.Errorf("got error %v for a failing call, want ErrExecutionReverted", err)
+	}
+}
+
+func TestAggregate3AllowFailure(t *testing.T) {
+	client, _ := newFakeClient()
+	calls := []Call3{balanceCall(1), {Target: reverterAddress, AllowFailure: true}}
+	results, err := client.Aggregate3(context.Background(), calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].Success || results[1].Success {
+		t.Fatalf("got successes %t and %t, want true and false", results[0].Success, results[1].Success)
+	}
+	checkBalance(t, results[0], 1)
+
+	calls[1].AllowFailure = false
+	if _, err := client.Aggregate3(context.Background(), calls); !errors.Is(err, ErrExecutionReverted) {
+		t.Errorf("got error %v, want ErrExecutionReverted", err)
+	}
+}
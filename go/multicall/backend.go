@@ -0,0 +1,186 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/john-na4/multicall3/go/internal/helper"
+)
+
+// backendMaxCalls is the number of calls per request of a node backend
+// unless WithMaxCalls says otherwise. Each call is a transaction of its own,
+// which costs at least 21,000 gas out of the gas limit of the block and the
+// gas cap of the node.
+const backendMaxCalls = 500
+
+// methodNotFoundCode is the JSON-RPC error code of unknown methods
+const methodNotFoundCode = -32601
+
+// errBackendSkipped is returned by node backends that cannot execute calls
+// with the options they are given, which go through the next backend
+var errBackendSkipped = errors.New("multicall: backend skipped")
+
+// nodeBackend executes the calls of aggregate3 multicalls with a JSON-RPC
+// method of the node instead of Multicall3, see WithSimulate and
+// WithCallMany
+type nodeBackend struct {
+	method string
+	// execute executes calls, each sending values[i] wei if values is not
+	// nil, and returns one result per call, whether it succeeded or not
+	execute func(ctx context.Context, c *Client, client *rpc.Client, opts *callOptions, calls []Call3, values []*big.Int) ([]Result, error)
+	// unsupported is set once the node has rejected the method, after which
+	// multicalls go through the next backend, and finally Multicall3
+	unsupported atomic.Bool
+}
+
+// nodeBackend returns the first backend of the client the node has not
+// rejected, or nil if multicalls go through Multicall3
+func (c *Client) nodeBackend() *nodeBackend {
+	for _, backend := range c.backends {
+		if !backend.unsupported.Load() {
+			return backend
+		}
+	}
+	return nil
+}
+
+// executeOnNode executes calls like aggregate3, or aggregate3Value if values
+// is not nil, with the first backend of the client the node supports,
+// remembering those it rejects as unknown. It reports whether a backend
+// executed them, and if not, they should be executed with Multicall3.
+func (c *Client) executeOnNode(ctx context.Context, opts *callOptions, calls []Call3, values []*big.Int) ([]Result, bool, error) {
+	for _, backend := range c.backends {
+		if backend.unsupported.Load() {
+			continue
+		}
+		client, err := c.rpcClient(backend.method)
+		if err != nil {
+			return nil, true, err
+		}
+		results, err := backend.execute(ctx, c, client, opts, calls, values)
+		switch {
+		case errors.Is(err, errBackendSkipped):
+			continue
+		case err != nil && isMethodNotFound(err):
+			backend.unsupported.Store(true)
+			continue
+		case err != nil:
+			return nil, true, fmt.Errorf("multicall: %s: %w", backend.method, classify(err))
+		case len(results) != len(calls):
+			return nil, true, fmt.Errorf("multicall: %s: got %d results for %d calls", backend.method, len(results), len(calls))
+		}
+		// Like aggregate3, fail when a call that may not fail does
+		var errs []error
+		for i, result := range results {
+			if !result.Success && !calls[i].AllowFailure {
+				errs = append(errs, withKind(ErrExecutionReverted, fmt.Errorf("multicall: call %d failed: %s", i, result.Reason())))
+			}
+		}
+		if len(errs) > 0 {
+			return nil, true, helper.JoinErrors(errs...)
+		}
+		return results, true, nil
+	}
+	return nil, false, nil
+}
+
+// backendCall is a transaction of the JSON-RPC methods of node backends
+type backendCall struct {
+	From                 common.Address `json:"from"`
+	To                   common.Address `json:"to"`
+	Input                hexutil.Bytes  `json:"input"`
+	Value                *hexutil.Big   `json:"value,omitempty"`
+	GasPrice             *hexutil.Big   `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas,omitempty"`
+}
+
+// backendCalls returns the transactions of calls with opts, each sending
+// values[i] wei if values is not nil. They are sent from the multicall
+// contract, which Multicall3 sends them from, or from the sender given with
+// From.
+func backendCalls(c *Client, opts *callOptions, calls []Call3, values []*big.Int) []backendCall {
+	from := c.address
+	if opts.from != nil {
+		from = *opts.from
+	}
+	txs := make([]backendCall, len(calls))
+	for i, call := range calls {
+		txs[i] = backendCall{
+			From:                 from,
+			To:                   call.Target,
+			Input:                call.CallData,
+			GasPrice:             (*hexutil.Big)(opts.gasPrice),
+			MaxFeePerGas:         (*hexutil.Big)(opts.gasFeeCap),
+			MaxPriorityFeePerGas: (*hexutil.Big)(opts.gasTipCap),
+		}
+		if values != nil {
+			txs[i].Value = (*hexutil.Big)(values[i])
+		}
+	}
+	return txs
+}
+
+// backendOverrides returns the state overrides of a backend call with opts:
+// those of opts, with the Multicall3 runtime code at the client's address,
+// so that calls of its helpers, such as getBlockNumber, work wherever
+// Multicall3 is not deployed
+func (c *Client) backendOverrides(opts *callOptions) *StateOverride {
+	overrides := make(StateOverride, len(opts.overrides)+1)
+	for account, override := range opts.overrides {
+		overrides[account] = override
+	}
+	override := overrides[c.address]
+	if override.Code == nil {
+		override.Code = runtimeCode
+	}
+	overrides[c.address] = override
+	return &overrides
+}
+
+// isMethodNotFound reports whether err is the error of a node that does not
+// know the method called
+func isMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFoundCode {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "method") && (strings.Contains(msg, "not found") || strings.Contains(msg, "does not exist") || strings.Contains(msg, "not supported"))
+}
+
+// headNumber returns the number of the block selected by opts, from its
+// header, for clients that do not rely on Multicall3 to report it. Without an
+// RPC client, the header is read through the contract caller, see
+// CallBackend.
+func (c *Client) headNumber(ctx context.Context, opts *callOptions) (*big.Int, error) {
+	client, err := c.rpcClient("eth_getBlockByNumber")
+	if err != nil && opts.blockHash == nil {
+		header, err := c.headerByNumber(ctx, "eth_getBlockByNumber", opts.block)
+		if err != nil {
+			return nil, err
+		}
+		return header.Number, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var header *struct {
+		Number *hexutil.Big `json:"number"`
+	}
+	if err := client.CallContext(ctx, &header, "eth_getBlockByNumber", blockArg(opts), false); err != nil {
+		return nil, fmt.Errorf("multicall: get block: %w", classify(err))
+	}
+	if header == nil || header.Number == nil {
+		return nil, fmt.Errorf("multicall: get block %v: %w", blockArg(opts), ethereum.NotFound)
+	}
+	return header.Number.ToInt(), nil
+}
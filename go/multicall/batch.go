@@ -0,0 +1,302 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+)
+
+// Batch collects contract calls and executes them as a single aggregate3
+// multicall, packing the calldata and unpacking the return values of every
+// call. Any error encountered while building the batch is deferred until
+// Execute so calls can be chained:
+//
+//	values, err := client.NewBatch().
+//		Add(dai, daiABI, "symbol").
+//		Add(dai, daiABI, "balanceOf", owner).
+//		Execute(ctx)
+type Batch struct {
+	client *Client
+	calls  []batchCall
+	keys   map[string]int
+	err    error
+}
+
+// batchCall is a packed call along with what is needed to decode its output
+type batchCall struct {
+	target   common.Address
+	abi      abi.ABI
+	method   string
+	callData []byte
+	typed    TypedCall
+	key      string
+	meta     interface{}
+	// allowFailure lets the call revert without reverting the batch
+	allowFailure bool
+}
+
+// NewBatch returns an empty Batch executed through c
+func (c *Client) NewBatch() *Batch {
+	return &Batch{client: c}
+}
+
+// Add appends a call of method on the contract at target, described by
+// contractABI, with the given arguments.
+func (b *Batch) Add(target common.Address, contractABI abi.ABI, method string, args ...interface{}) *Batch {
+	if b.err != nil {
+		return b
+	}
+	callData, err := contractABI.Pack(method, args...)
+	if err != nil {
+		b.err = fmt.Errorf("multicall: pack call %d (%s): %w", len(b.calls), method, err)
+		return b
+	}
+	b.calls = append(b.calls, batchCall{
+		target:   target,
+		abi:      contractABI,
+		method:   method,
+		callData: callData,
+	})
+	return b
+}
+
+// Len returns the number of calls in the batch
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// CallResult is the outcome of a call: whether it succeeded on-chain and
+// its raw return data, from the embedded Result, along with the return values
+// decoded with the call's ABI and the metadata attached to the call with
+// Meta. Values is only set if the call succeeded and its return data could be
+// decoded, otherwise Err reports the decoding failure, or Success is false
+// and ReturnData holds the revert data, see Result.Reason. Neither affects
+// the other calls of a batch. Results can be handled with a single switch:
+//
+//	switch {
+//	case !result.Success:
+//		log.Printf("call reverted: %s", result.Reason())
+//	case result.Err != nil:
+//		log.Printf("cannot decode: %v", result.Err)
+//	default:
+//		use(result.Values)
+//	}
+type CallResult struct {
+	Result
+	Values []interface{}
+	Meta   interface{}
+	Err    error
+}
+
+// Decode decodes the result of a call of method, described by contractABI,
+// into a CallResult, so that the results of every aggregate method can be
+// handled like those of a Batch. A decoding failure is reported through Err.
+func (r Result) Decode(contractABI abi.ABI, method string) CallResult {
+	decoded := CallResult{Result: r}
+	if !r.Success {
+		return decoded
+	}
+	values, err := contractABI.Unpack(method, r.ReturnData)
+	if err != nil {
+		decoded.Err = withKind(ErrDecode, fmt.Errorf("multicall: unpack %s: %w", method, err))
+		return decoded
+	}
+	decoded.Values = values
+	return decoded
+}
+
+// Error returns the error of the call of method on subject, such as the
+// address of the contract called, classified as ErrExecutionReverted if it
+// reverted, or the decoding failure of Err, so that helpers reading many
+// results can report each failed call with what it was
+func (r CallResult) Error(subject interface{}, method string) error {
+	if !r.Success {
+		return withKind(ErrExecutionReverted, fmt.Errorf("multicall: %s of %s reverted: %s", method, subject, r.Reason()))
+	}
+	return r.Err
+}
+
+// AllowFailure lets the most recently added call revert without reverting
+// the whole batch. Its result then reports the failure through Success, and
+// its values are nil.
+func (b *Batch) AllowFailure() *Batch {
+	if b.err != nil {
+		return b
+	}
+	if len(b.calls) == 0 {
+		b.err = errors.New("multicall: failure allowed before any call was added")
+		return b
+	}
+	b.calls[len(b.calls)-1].allowFailure = true
+	return b
+}
+
+// Key labels the most recently added call, so its result can be looked up by
+// key in the map returned by ExecuteKeyed. Keys must be unique within a batch.
+func (b *Batch) Key(key string) *Batch {
+	if b.err != nil {
+		return b
+	}
+	switch {
+	case len(b.calls) == 0:
+		b.err = fmt.Errorf("multicall: key %q set before any call was added", key)
+	case key == "":
+		b.err = fmt.Errorf("multicall: empty key for call %d", len(b.calls)-1)
+	case b.hasKey(key):
+		b.err = fmt.Errorf("multicall: duplicate key %q for call %d", key, len(b.calls)-1)
+	case b.calls[len(b.calls)-1].key != "":
+		b.err = fmt.Errorf("multicall: call %d is already keyed %q", len(b.calls)-1, b.calls[len(b.calls)-1].key)
+	default:
+		if b.keys == nil {
+			b.keys = make(map[string]int)
+		}
+		b.keys[key] = len(b.calls) - 1
+		b.calls[len(b.calls)-1].key = key
+	}
+	return b
+}
+
+// Execute sends the batch and returns the unpacked return values of each call,
+// in the order the calls were added. The batch reverts if any call fails,
+// unless it is allowed to with AllowFailure, in which case its values are nil.
+// With PartialResults, the values of the calls that were not executed are nil.
+// Calls whose return data cannot be decoded do not fail the batch: their
+// values are nil, and the values of every other call are returned along with
+// the errors of all of them, joined with errors.Join, each wrapping
+// ErrDecode. Use ExecuteResults for the error of each call.
+func (b *Batch) Execute(ctx context.Context, opts ...CallOption) ([][]interface{}, error) {
+	results, err := b.execute(ctx, opts)
+	if err != nil && incomplete(err) == nil {
+		return nil, err
+	}
+	values := make([][]interface{}, len(results))
+	errs := []error{err}
+	for i, result := range results {
+		values[i] = result.Values
+		errs = append(errs, result.Err)
+	}
+	return values, helper.JoinErrors(errs...)
+}
+
+// ExecuteKeyed sends the batch like Execute, but returns the results of the
+// calls labeled with Key, indexed by their key, with their decoding errors.
+// Unlabeled calls are executed but left out of the map, like calls not
+// executed with PartialResults.
+func (b *Batch) ExecuteKeyed(ctx context.Context, opts ...CallOption) (map[string]CallResult, error) {
+	results, err := b.execute(ctx, opts)
+	incompleteErr := incomplete(err)
+	if err != nil && incompleteErr == nil {
+		return nil, err
+	}
+	keyed := make(map[string]CallResult, len(b.keys))
+	for key, i := range b.keys {
+		if !missed(incompleteErr, i) {
+			keyed[key] = results[i]
+		}
+	}
+	return keyed, err
+}
+
+// Meta attaches arbitrary metadata, such as a label or the token and wallet a
+// balance is queried for, to the most recently added call. It is returned
+// with the call's result by ExecuteResults and ExecuteKeyed, so results can
+// be processed without keeping track of what each call was for separately.
+func (b *Batch) Meta(meta interface{}) *Batch {
+	if b.err != nil {
+		return b
+	}
+	if len(b.calls) == 0 {
+		b.err = errors.New("multicall: metadata set before any call was added")
+		return b
+	}
+	b.calls[len(b.calls)-1].meta = meta
+	return b
+}
+
+// ExecuteResults sends the batch like Execute, but returns the full result of
+// every call, including its metadata and decoding error, in the order the
+// calls were added:
+//
+//	batch := client.NewBatch()
+//	for _, holding := range holdings {
+//		batch.Add(holding.Token, erc20ABI, "balanceOf", holding.Wallet).Meta(holding)
+//	}
+//	results, err := batch.ExecuteResults(ctx)
+//	for _, result := range results {
+//		holding := result.Meta.(Holding)
+//		...
+//	}
+func (b *Batch) ExecuteResults(ctx context.Context, opts ...CallOption) ([]CallResult, error) {
+	return b.execute(ctx, opts)
+}
+
+func (b *Batch) hasKey(key string) bool {
+	_, ok := b.keys[key]
+	return ok
+}
+
+// label identifies the i-th call in errors by its index, key and method
+func (b *Batch) label(i int) string {
+	call := b.calls[i]
+	if call.key != "" {
+		return fmt.Sprintf("call %d %q (%s)", i, call.key, call.method)
+	}
+	return fmt.Sprintf("call %d (%s)", i, call.method)
+}
+
+// execute sends the batch and decodes the result of every call
+func (b *Batch) execute(ctx context.Context, opts []CallOption) ([]CallResult, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.calls) == 0 {
+		return []CallResult{}, nil
+	}
+	calls := make([]Call3, len(b.calls))
+	for i, call := range b.calls {
+		calls[i] = Call3{Target: call.target, AllowFailure: call.allowFailure, CallData: call.callData}
+	}
+	results, err := b.client.aggregate3Deduped(ctx, calls, opts...)
+	incompleteErr := incomplete(err)
+	if err != nil && incompleteErr == nil {
+		return nil, err
+	}
+	if len(results) != len(b.calls) {
+		return nil, fmt.Errorf("multicall: got %d results for %d calls", len(results), len(b.calls))
+	}
+	decoded := make([]CallResult, len(results))
+	for i, result := range results {
+		call := b.calls[i]
+		if missed(incompleteErr, i) {
+			decoded[i] = CallResult{Meta: call.meta}
+			continue
+		}
+		decoded[i] = CallResult{Result: result, Meta: call.meta}
+		if !result.Success {
+			if call.typed != nil {
+				call.typed.fail(withKind(ErrExecutionReverted, fmt.Errorf("multicall: %s reverted: %s", b.label(i), result.Reason())))
+			}
+			continue
+		}
+		values, err := call.abi.Unpack(call.method, result.ReturnData)
+		if err != nil {
+			decoded[i].Err = withKind(ErrDecode, fmt.Errorf("multicall: unpack %s: %w", b.label(i), err))
+			if call.typed != nil {
+				call.typed.fail(decoded[i].Err)
+			}
+			continue
+		}
+		if call.typed != nil {
+			if err := call.typed.decode(values); err != nil {
+				decoded[i].Err = withKind(ErrDecode, fmt.Errorf("multicall: decode %s: %w", b.label(i), err))
+				continue
+			}
+		}
+		decoded[i].Values = values
+	}
+	return decoded, err
+}
@@ -0,0 +1,89 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestBatchExecute(t *testing.T) {
+	client, _ := newFakeClient()
+	values, err := client.NewBatch().
+		Add(tokenAddress, tokenABI, "balanceOf", owner(1)).
+		Add(tokenAddress, tokenABI, "balanceOf", owner(2)).
+		Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int64{1, 2} {
+		if got := values[i][0].(*big.Int); got.Int64() != want {
+			t.Errorf("call %d: got balance %s, want %d", i, got, want)
+		}
+	}
+
+	if _, err := client.NewBatch().Add(tokenAddress, tokenABI, "balanceOf", "not an address").Execute(context.Background()); err == nil {
+		t.Error("batch with arguments that do not pack executed")
+	}
+	if values, err := client.NewBatch().Execute(context.Background()); err != nil || len(values) != 0 {
+		t.Errorf("got %v, %v for an empty batch, want no values", values, err)
+	}
+}
+
+func TestBatchDecodeErrorsPerCall(t *testing.T) {
+	client, _ := newFakeClient()
+	batch := client.NewBatch().
+		Add(tokenAddress, tokenABI, "balanceOf", owner(1)).
+		// Accounts without code return no data, which does not decode
+		Add(owner(9), tokenABI, "balanceOf", owner(1)).
+		Add(tokenAddress, tokenABI, "balanceOf", owner(2))
+	values, err := batch.Execute(context.Background())
+	if !errors.Is(err, ErrDecode) || !strings.Contains(err.Error(), "call 1 (balanceOf)") {
+		t.Fatalf("got error %v, want the decode error of call 1", err)
+	}
+	if values[1] != nil {
+		t.Errorf("got values %v for the call that does not decode, want nil", values[1])
+	}
+	for i, want := range map[int]int64{0: 1, 2: 2} {
+		if got := values[i][0].(*big.Int); got.Int64() != want {
+			t.Errorf("call %d: got balance %s, want %d", i, got, want)
+		}
+	}
+
+	results, err := batch.ExecuteResults(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range results {
+		if got := errors.Is(result.Err, ErrDecode); got != (i == 1) {
+			t.Errorf("call %d: got error %v", i, result.Err)
+		}
+	}
+}
+
+func TestCallResultError(t *testing.T) {
+	client, _ := newFakeClient()
+	results, err := client.NewBatch().
+		Add(tokenAddress, tokenABI, "balanceOf", owner(1)).
+		Add(reverterAddress, tokenABI, "balanceOf", owner(1)).AllowFailure().
+		// Accounts without code return no data, which does not decode
+		Add(owner(9), tokenABI, "balanceOf", owner(1)).
+		ExecuteResults(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := results[0].Error(tokenAddress, "balanceOf"); err != nil {
+		t.Errorf("successful call has error %v", err)
+	}
+	err = results[1].Error(reverterAddress, "balanceOf")
+	if !errors.Is(err, ErrExecutionReverted) {
+		t.Errorf("got error %v for a reverted call, want ErrExecutionReverted", err)
+	}
+	if want := "multicall: balanceOf of " + reverterAddress.Hex() + " reverted: nope"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+	if err := results[2].Error(owner(9), "balanceOf"); !errors.Is(err, ErrDecode) {
+		t.Errorf("got error %v for undecodable data, want ErrDecode", err)
+	}
+}
@@ -0,0 +1,145 @@
+package multicall
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Batcher coalesces calls made independently, typically from many goroutines,
+// into shared aggregate3 multicalls. Calls are collected until maxSize calls
+// are pending or maxWait has passed since the first of them, and are then
+// executed together. Each call is sent with AllowFailure set, so a revert is
+// only reported to the caller that made it.
+type Batcher struct {
+	client  *Client
+	maxWait time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	pending []*Future
+	timer   *time.Timer
+}
+
+// NewBatcher returns a Batcher that executes calls through c. A maxSize of
+// zero or less puts no limit on the number of calls, so batches are only
+// flushed after maxWait.
+func (c *Client) NewBatcher(maxWait time.Duration, maxSize int) *Batcher {
+	return &Batcher{client: c, maxWait: maxWait, maxSize: maxSize}
+}
+
+// Future is the pending result of a call queued on a Batcher
+type Future struct {
+	ctx  context.Context
+	call Call3
+
+	done   chan struct{}
+	result Result
+	err    error
+}
+
+// Call queues a call of data on target and returns its future result. If ctx
+// is done before the batch is executed, the call is dropped from the batch.
+func (b *Batcher) Call(ctx context.Context, target common.Address, data []byte) *Future {
+	f := &Future{
+		ctx:  ctx,
+		call: Call3{Target: target, AllowFailure: true, CallData: data},
+		done: make(chan struct{}),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending = append(b.pending, f)
+	if b.maxSize > 0 && len(b.pending) >= b.maxSize {
+		b.flushLocked()
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(b.maxWait, b.Flush)
+	}
+	return f
+}
+
+// Flush executes the pending calls immediately rather than waiting for the
+// batch to fill up or time out.
+func (b *Batcher) Flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.flushLocked()
+}
+
+func (b *Batcher) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.pending) == 0 {
+		return
+	}
+	pending := b.pending
+	b.pending = nil
+	go b.execute(pending)
+}
+
+// execute runs a batch of futures as a single multicall and resolves them
+func (b *Batcher) execute(pending []*Future) {
+	var live []*Future
+	for _, f := range pending {
+		if err := f.ctx.Err(); err != nil {
+			f.resolve(Result{}, err)
+			continue
+		}
+		live = append(live, f)
+	}
+	if len(live) == 0 {
+		return
+	}
+
+	// The multicall is abandoned only once every caller has given up on it
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	remaining := int32(len(live))
+	for _, f := range live {
+		stop := context.AfterFunc(f.ctx, func() {
+			if atomic.AddInt32(&remaining, -1) == 0 {
+				cancel()
+			}
+		})
+		defer stop()
+	}
+
+	calls := make([]Call3, len(live))
+	for i, f := range live {
+		calls[i] = f.call
+	}
+	results, err := b.client.aggregate3Deduped(ctx, calls)
+	for i, f := range live {
+		if err != nil {
+			f.resolve(Result{}, err)
+		} else {
+			f.resolve(results[i], nil)
+		}
+	}
+}
+
+func (f *Future) resolve(result Result, err error) {
+	f.result, f.err = result, err
+	close(f.done)
+}
+
+// Done returns a channel that is closed once the result is available
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the call has been executed or ctx is done, and returns
+// its result. A call that reverted is reported through Result.Success, not
+// as an error.
+func (f *Future) Wait(ctx context.Context) (Result, error) {
+	select {
+	case <-f.done:
+		return f.result, f.err
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+}
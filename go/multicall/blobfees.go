@@ -0,0 +1,142 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Fees are the execution and blob gas prices of a block
+type Fees struct {
+	BlockNumber uint64
+	// BaseFee is the base fee per gas of the block, in wei
+	BaseFee *big.Int
+	// ExcessBlobGas and BlobBaseFee, the base fee per blob gas in wei, are
+	// nil for blocks before EIP-4844 and on chains without blobs
+	ExcessBlobGas *uint64
+	BlobBaseFee   *big.Int
+}
+
+// FeeCalls snapshot the fees of the block a batch executes in, along with
+// the other calls of the batch, see Batch.AddFees
+type FeeCalls struct {
+	client      *Client
+	blockNumber *CallOf[*big.Int]
+	baseFee     *CallOf[*big.Int]
+}
+
+// AddFees appends calls of getBlockNumber and getBasefee, recorded in fees,
+// so that once the batch is executed fees.Fees completes them with the blob
+// gas prices of the same block. Like AddSelf, it needs the multicall
+// contract to be Multicall3, deployed at the client's address or injected
+// there with WithCodeOverride.
+func (b *Batch) AddFees(fees *FeeCalls) *Batch {
+	fees.client = b.client
+	fees.blockNumber, fees.baseFee = BlockNumber(), BaseFee()
+	return b.AddSelf(fees.blockNumber, fees.baseFee)
+}
+
+// Fees returns the fees of the block the batch the calls were added to was
+// executed in, fetching its blob gas prices with Client.BlobFees
+func (f *FeeCalls) Fees(ctx context.Context) (*Fees, error) {
+	if f.client == nil {
+		return nil, errors.New("multicall: fee calls were not added to a batch")
+	}
+	number, err := f.blockNumber.Get()
+	if err != nil {
+		return nil, err
+	}
+	baseFee, err := f.baseFee.Get()
+	if err != nil {
+		return nil, err
+	}
+	excessBlobGas, blobBaseFee, err := f.client.BlobFees(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return &Fees{
+		BlockNumber:   number.Uint64(),
+		BaseFee:       baseFee,
+		ExcessBlobGas: excessBlobGas,
+		BlobBaseFee:   blobBaseFee,
+	}, nil
+}
+
+// Fees returns the fees of the block selected by opts, the latest by
+// default
+func (c *Client) Fees(ctx context.Context, opts ...CallOption) (*Fees, error) {
+	var fees FeeCalls
+	if _, err := c.NewBatch().AddFees(&fees).Execute(ctx, opts...); err != nil {
+		return nil, err
+	}
+	return fees.Fees(ctx)
+}
+
+// blobHeader holds the blob fields of a block header
+type blobHeader struct {
+	ExcessBlobGas *hexutil.Uint64 `json:"excessBlobGas"`
+}
+
+// blobFeeHistory holds the blob fields of an eth_feeHistory result
+type blobFeeHistory struct {
+	BaseFeePerBlobGas []*hexutil.Big `json:"baseFeePerBlobGas"`
+}
+
+// BlobFees returns the excess blob gas and the base fee per blob gas of the
+// block numbered number, both nil for blocks without blob gas. They are
+// fetched in a single JSON-RPC batch, with the RPC client of the client, see
+// WithRPCClient: the excess blob gas from the block header, and the blob
+// base fee from eth_feeHistory, which follows the blob parameters of the
+// fork the block is in. For nodes whose eth_feeHistory does not report blob
+// fees, the blob base fee is computed from the excess blob gas with the
+// parameters of EIP-4844, which later forks changed.
+func (c *Client) BlobFees(ctx context.Context, number *big.Int) (*uint64, *big.Int, error) {
+	client, err := c.rpcClient("BlobFees")
+	if err != nil {
+		return nil, nil, err
+	}
+	var header *blobHeader
+	var history blobFeeHistory
+	block := hexutil.EncodeBig(number)
+	batch := []rpc.BatchElem{
+		{Method: "eth_getBlockByNumber", Args: []interface{}{block, false}, Result: &header},
+		{Method: "eth_feeHistory", Args: []interface{}{hexutil.Uint64(1), block, []float64{}}, Result: &history},
+	}
+	if err := client.BatchCallContext(ctx, batch); err != nil {
+		return nil, nil, fmt.Errorf("multicall: get blob fees of block %s: %w", number, err)
+	}
+	if batch[0].Error != nil {
+		return nil, nil, fmt.Errorf("multicall: get block %s: %w", number, batch[0].Error)
+	}
+	if header == nil {
+		return nil, nil, fmt.Errorf("multicall: get block %s: %w", number, ethereum.NotFound)
+	}
+	if header.ExcessBlobGas == nil {
+		return nil, nil, nil
+	}
+	excessBlobGas := uint64(*header.ExcessBlobGas)
+	if batch[1].Error == nil && len(history.BaseFeePerBlobGas) > 0 && history.BaseFeePerBlobGas[0] != nil {
+		return &excessBlobGas, history.BaseFeePerBlobGas[0].ToInt(), nil
+	}
+	return &excessBlobGas, eip4844.CalcBlobFee(excessBlobGas), nil
+}
+
+// NextBlobBaseFee returns the base fee per blob gas of the next block, from
+// eth_blobBaseFee, which blob transactions sent now should pay at least
+func (c *Client) NextBlobBaseFee(ctx context.Context) (*big.Int, error) {
+	client, err := c.rpcClient("NextBlobBaseFee")
+	if err != nil {
+		return nil, err
+	}
+	var fee hexutil.Big
+	if err := client.CallContext(ctx, &fee, "eth_blobBaseFee"); err != nil {
+		return nil, fmt.Errorf("multicall: get blob base fee: %w", err)
+	}
+	return fee.ToInt(), nil
+}
@@ -0,0 +1,154 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// VerifyBlock makes BlockAndAggregate and TryBlockAndAggregate check that
+// the block they were executed at is the block the provider returns the
+// header of for the same number, and fail with ErrBlockMismatch, along with
+// their results, if it is not. This flags results that came from a block
+// since reorged out, or from another node than the header behind a load
+// balancer.
+//
+// The EVM cannot see the hash of the block it executes in, which is why the
+// BlockHash of a BlockResult is zero, so every multicall is sent with calls
+// of the multicall helpers that read the parent hash, timestamp and
+// prevrandao of the block, which must match its header. The header is
+// fetched with the RPC client of the client, see WithRPCClient, or through
+// its contract caller if it can read headers, see CallBackend.
+func VerifyBlock() CallOption {
+	return func(o *callOptions) {
+		o.verifyBlock = true
+	}
+}
+
+// fingerprintMethods are the helpers whose values identify the block of a
+// multicall, in the order they are appended to its calls
+var fingerprintMethods = []string{"getLastBlockHash", "getCurrentBlockTimestamp", "getCurrentBlockDifficulty"}
+
+// blockFingerprint are the values of the fingerprint methods
+type blockFingerprint struct {
+	parentHash common.Hash
+	timestamp  *big.Int
+	// difficulty is the value of DIFFICULTY, the prevrandao of the block
+	// since the merge
+	difficulty *big.Int
+}
+
+func (f *blockFingerprint) equal(other *blockFingerprint) bool {
+	return f.parentHash == other.parentHash && f.timestamp.Cmp(other.timestamp) == 0 && f.difficulty.Cmp(other.difficulty) == 0
+}
+
+// fingerprintCalls returns the calls of the fingerprint methods on the
+// multicall contract at address
+func fingerprintCalls(address common.Address) []Call {
+	calls := make([]Call, len(fingerprintMethods))
+	for i, method := range fingerprintMethods {
+		calls[i] = Call{Target: address, CallData: ABI.Methods[method].ID}
+	}
+	return calls
+}
+
+// fingerprintSize is the encoded size of the fingerprint calls
+var fingerprintSize = func() int {
+	size := 0
+	for _, call := range fingerprintCalls(common.Address{}) {
+		size += callSize(call)
+	}
+	return size
+}()
+
+// unpackFingerprint decodes the results of the fingerprint calls
+func unpackFingerprint(results []Result) (*blockFingerprint, error) {
+	values := make([]interface{}, len(fingerprintMethods))
+	for i, method := range fingerprintMethods {
+		if !results[i].Success {
+			return nil, fmt.Errorf("multicall: %s failed", method)
+		}
+		unpacked, err := ABI.Unpack(method, results[i].ReturnData)
+		if err != nil {
+			return nil, withKind(ErrDecode, fmt.Errorf("multicall: unpack %s result: %w", method, err))
+		}
+		values[i] = unpacked[0]
+	}
+	return &blockFingerprint{
+		parentHash: common.Hash(values[0].([32]byte)),
+		timestamp:  values[1].(*big.Int),
+		difficulty: values[2].(*big.Int),
+	}, nil
+}
+
+// fingerprintHeader holds the fields of a block header the fingerprint is
+// checked against
+type fingerprintHeader struct {
+	ParentHash common.Hash    `json:"parentHash"`
+	Time       hexutil.Uint64 `json:"timestamp"`
+	Difficulty *hexutil.Big   `json:"difficulty"`
+	MixDigest  common.Hash    `json:"mixHash"`
+}
+
+// verifyBlock checks the fingerprint of the block a multicall was executed
+// at against the header of the block with the same number
+func (c *Client) verifyBlock(ctx context.Context, number *big.Int, fingerprint *blockFingerprint) error {
+	header, err := c.fingerprintHeader(ctx, number)
+	if err != nil {
+		return err
+	}
+	if header == nil {
+		return fmt.Errorf("%w: block %s not found", ErrBlockMismatch, number)
+	}
+	switch {
+	case header.ParentHash != fingerprint.parentHash:
+		return fmt.Errorf("%w: block %s has parent %s, multicall saw %s", ErrBlockMismatch, number, header.ParentHash, fingerprint.parentHash)
+	case uint64(header.Time) != fingerprint.timestamp.Uint64():
+		return fmt.Errorf("%w: block %s has timestamp %d, multicall saw %s", ErrBlockMismatch, number, header.Time, fingerprint.timestamp)
+	case !matchesDifficulty(header, fingerprint.difficulty):
+		return fmt.Errorf("%w: block %s has another prevrandao than the multicall saw", ErrBlockMismatch, number)
+	}
+	return nil
+}
+
+// fingerprintHeader reads the header of the block with the given number with
+// the client's RPC client, or without one through its contract caller, see
+// CallBackend. It returns nil if the block is not found.
+func (c *Client) fingerprintHeader(ctx context.Context, number *big.Int) (*fingerprintHeader, error) {
+	client, err := c.rpcClient("VerifyBlock")
+	if err != nil {
+		header, err := c.headerByNumber(ctx, "VerifyBlock", number)
+		if errors.Is(err, ethereum.NotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &fingerprintHeader{
+			ParentHash: header.ParentHash,
+			Time:       hexutil.Uint64(header.Time),
+			Difficulty: (*hexutil.Big)(header.Difficulty),
+			MixDigest:  header.MixDigest,
+		}, nil
+	}
+	var header *fingerprintHeader
+	if err := client.CallContext(ctx, &header, "eth_getBlockByNumber", hexutil.EncodeBig(number), false); err != nil {
+		return nil, fmt.Errorf("multicall: get block %s: %w", number, err)
+	}
+	return header, nil
+}
+
+// matchesDifficulty reports whether DIFFICULTY returned difficulty in the
+// block of header: its prevrandao, the mix digest field, since the merge,
+// and its difficulty before, or on chains that define it otherwise
+func matchesDifficulty(header *fingerprintHeader, difficulty *big.Int) bool {
+	if common.BigToHash(difficulty) == header.MixDigest {
+		return true
+	}
+	return header.Difficulty != nil && header.Difficulty.ToInt().Cmp(difficulty) == 0
+}
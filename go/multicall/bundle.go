@@ -0,0 +1,235 @@
+package multicall
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BundleRelay submits bundles of transactions to a Flashbots-compatible
+// relay or builder, which includes them in a block as a whole or not at all,
+// without them ever being visible in the public transaction pool where they
+// could be frontrun. Requests are signed with an authentication key, which
+// identifies the searcher to the relay and need not hold any funds.
+type BundleRelay struct {
+	url     string
+	authKey *ecdsa.PrivateKey
+	client  *http.Client
+}
+
+// NewBundleRelay returns a BundleRelay for the relay at url, such as
+// https://relay.flashbots.net, whose requests are signed with authKey
+func NewBundleRelay(url string, authKey *ecdsa.PrivateKey) *BundleRelay {
+	return &BundleRelay{url: url, authKey: authKey, client: http.DefaultClient}
+}
+
+// BundleSimulation is the outcome of a bundle simulated with eth_callBundle
+type BundleSimulation struct {
+	BundleHash   common.Hash `json:"bundleHash"`
+	TotalGasUsed uint64      `json:"totalGasUsed"`
+	// CoinbaseDiff is what the bundle pays the fee recipient of the block,
+	// in wei
+	CoinbaseDiff *big.Int              `json:"-"`
+	Results      []BundleSimulatedCall `json:"results"`
+}
+
+// BundleSimulatedCall is the outcome of a transaction of a simulated bundle
+type BundleSimulatedCall struct {
+	TxHash  common.Hash   `json:"txHash"`
+	GasUsed uint64        `json:"gasUsed"`
+	Value   hexutil.Bytes `json:"value"`
+	// Error is set if the transaction failed, and Revert holds its revert
+	// reason
+	Error  string `json:"error"`
+	Revert string `json:"revert"`
+}
+
+// CallBundle simulates txs as a bundle for inclusion in the block numbered
+// block with eth_callBundle, on top of the state of the latest block
+func (r *BundleRelay) CallBundle(ctx context.Context, txs []*types.Transaction, block uint64) (*BundleSimulation, error) {
+	raw, err := encodeBundle(txs)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{
+		"txs":              raw,
+		"blockNumber":      hexutil.Uint64(block),
+		"stateBlockNumber": Latest,
+	}
+	var result struct {
+		BundleSimulation
+		CoinbaseDiff string `json:"coinbaseDiff"`
+	}
+	if err := r.call(ctx, &result, "eth_callBundle", params); err != nil {
+		return nil, err
+	}
+	simulation := result.BundleSimulation
+	if diff, ok := new(big.Int).SetString(result.CoinbaseDiff, 10); ok {
+		simulation.CoinbaseDiff = diff
+	}
+	return &simulation, nil
+}
+
+// SendBundle submits txs as a bundle for inclusion in the block numbered
+// block with eth_sendBundle and returns the hash of the bundle. A bundle that
+// is not included in that block is dropped, and must be sent again for a
+// later block.
+func (r *BundleRelay) SendBundle(ctx context.Context, txs []*types.Transaction, block uint64) (common.Hash, error) {
+	raw, err := encodeBundle(txs)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var result struct {
+		BundleHash common.Hash `json:"bundleHash"`
+	}
+	params := map[string]interface{}{"txs": raw, "blockNumber": hexutil.Uint64(block)}
+	if err := r.call(ctx, &result, "eth_sendBundle", params); err != nil {
+		return common.Hash{}, err
+	}
+	return result.BundleHash, nil
+}
+
+// encodeBundle returns the raw transactions of a bundle
+func encodeBundle(txs []*types.Transaction) ([]hexutil.Bytes, error) {
+	raw := make([]hexutil.Bytes, len(txs))
+	for i, tx := range txs {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("multicall: encode bundle transaction %d: %w", i, err)
+		}
+		raw[i] = data
+	}
+	return raw, nil
+}
+
+// call sends a JSON-RPC request of method with a single parameter to the
+// relay, signed with its authentication key as the X-Flashbots-Signature
+// header, and decodes its result into result
+func (r *BundleRelay) call(ctx context.Context, result interface{}, method string, param interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": []interface{}{param}})
+	if err != nil {
+		return fmt.Errorf("multicall: encode %s request: %w", method, err)
+	}
+	signature, err := crypto.Sign(accounts.TextHash([]byte(hexutil.Encode(crypto.Keccak256(body)))), r.authKey)
+	if err != nil {
+		return fmt.Errorf("multicall: sign %s request: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("multicall: %s: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Flashbots-Signature", crypto.PubkeyToAddress(r.authKey.PublicKey).Hex()+":"+hexutil.Encode(signature))
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("multicall: %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("multicall: %s: read response: %w", method, err)
+	}
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("multicall: %s: relay %s: %d %s", method, r.url, resp.StatusCode, http.StatusText(resp.StatusCode))
+		}
+		return fmt.Errorf("multicall: %s: decode response: %w", method, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("multicall: %s: relay error %d: %s", method, response.Error.Code, response.Error.Message)
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return withKind(ErrDecode, fmt.Errorf("multicall: %s: decode result: %w", method, err))
+	}
+	return nil
+}
+
+// SignAggregate signs calls into an aggregate3 transaction like
+// SendAggregate, but returns it without broadcasting it, to be sent through
+// other means, such as a bundle relay. The client's nonce manager tracks it
+// as if it had been sent.
+func (c *Client) SignAggregate(ctx context.Context, opts *bind.TransactOpts, calls []Call3) (*types.Transaction, error) {
+	if opts == nil {
+		return nil, errors.New("multicall: nil transact options")
+	}
+	method, data, err := c.aggregateData(calls)
+	if err != nil {
+		return nil, err
+	}
+	o := *opts
+	o.NoSend = true
+	return c.transact(ctx, &o, method, data)
+}
+
+// SendAggregateBundle signs calls into an aggregate3 transaction like
+// SendAggregate, simulates it with the relay's eth_callBundle and, unless it
+// fails, submits it as a bundle of its own for inclusion in the block
+// numbered block, the next block if zero. A transaction that would revert
+// fails with ErrExecutionReverted before it is submitted. It returns the
+// transaction and the hash of the bundle, or the error that kept the bundle
+// from being submitted. The transaction is only included in
+// that block, if at all; send it again for later blocks while it is not, for
+// example with the same opts and nonce.
+func (c *Client) SendAggregateBundle(ctx context.Context, relay *BundleRelay, opts *bind.TransactOpts, calls []Call3, block uint64) (*types.Transaction, common.Hash, error) {
+	if block == 0 {
+		head, err := c.headNumber(ctx, &callOptions{})
+		if err != nil {
+			return nil, common.Hash{}, err
+		}
+		block = head.Uint64() + 1
+	}
+	tx, err := c.SignAggregate(ctx, opts, calls)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	hash, err := c.sendBundle(ctx, relay, tx, block)
+	if err != nil {
+		// The transaction was never sent, so its nonce is free again, if
+		// the nonce manager handed it out. A nonce of opts may be that of a
+		// bundle sent before, which still holds it.
+		if c.nonces != nil && opts.Nonce == nil && opts.From == c.nonces.Account() {
+			c.nonces.forget(tx)
+		}
+		return nil, common.Hash{}, err
+	}
+	return tx, hash, nil
+}
+
+// sendBundle simulates a bundle of tx and submits it unless it fails
+func (c *Client) sendBundle(ctx context.Context, relay *BundleRelay, tx *types.Transaction, block uint64) (common.Hash, error) {
+	txs := []*types.Transaction{tx}
+	simulation, err := relay.CallBundle(ctx, txs, block)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	for _, result := range simulation.Results {
+		if result.Error != "" {
+			reason := result.Error
+			if result.Revert != "" {
+				reason += ": " + result.Revert
+			}
+			return common.Hash{}, withKind(ErrExecutionReverted, fmt.Errorf("multicall: simulate bundle: transaction %s failed: %s", result.TxHash, reason))
+		}
+	}
+	return relay.SendBundle(ctx, txs, block)
+}
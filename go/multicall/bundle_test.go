@@ -0,0 +1,109 @@
+package multicall
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/john-na4/multicall3/go/bindings"
+)
+
+// newRelay returns a relay whose simulations succeed and whose
+// eth_sendBundle fails while fail is set
+func newRelay(t *testing.T, fail *atomic.Bool) *BundleRelay {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch {
+		case req.Method == "eth_callBundle":
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"results":[],"coinbaseDiff":"0"}}`))
+		case fail.Load():
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"bundle rejected"}}`))
+		default:
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"bundleHash":"0x0000000000000000000000000000000000000000000000000000000000000001"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewBundleRelay(server.URL, key)
+}
+
+func TestSendAggregateBundleNonces(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{auth.From: {Balance: new(big.Int).Lsh(big.NewInt(1), 100)}}, 30_000_000)
+	t.Cleanup(func() { sim.Close() })
+	target, _, _, err := bindings.DeployMulticall3(auth, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	nonces := NewNonceManager(sim, auth.From)
+	client, err := NewClient(sim, WithAddress(target), WithNonceManager(nonces))
+	if err != nil {
+		t.Fatal(err)
+	}
+	getBlockNumber, err := ABI.Pack("getBlockNumber")
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := []Call3{{Target: target, CallData: getBlockNumber}}
+	var fail atomic.Bool
+	relay := newRelay(t, &fail)
+	ctx := context.Background()
+
+	// A bundle that fails gives back the nonce the manager handed out
+	fail.Store(true)
+	if _, _, err := client.SendAggregateBundle(ctx, relay, auth, calls, 10); err == nil || !strings.Contains(err.Error(), "bundle rejected") {
+		t.Fatalf("got error %v, want the relay's", err)
+	}
+	fail.Store(false)
+	tx, hash, err := client.SendAggregateBundle(ctx, relay, auth, calls, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 1 || hash != common.HexToHash("0x01") {
+		t.Errorf("got nonce %d and bundle %s, want nonce 1, the deployment having taken 0", tx.Nonce(), hash)
+	}
+
+	// Sending it again for a later block with its nonce fails, but the
+	// bundle sent first still holds the nonce
+	fail.Store(true)
+	resend := *auth
+	resend.Nonce = new(big.Int).SetUint64(tx.Nonce())
+	if _, _, err := client.SendAggregateBundle(ctx, relay, &resend, calls, 11); err == nil {
+		t.Fatal("resend of a rejected bundle succeeded")
+	}
+	if inFlight := nonces.InFlight(); len(inFlight) != 1 || inFlight[0].Nonce() != tx.Nonce() {
+		t.Errorf("got %d transactions in flight, want the one with nonce %d", len(inFlight), tx.Nonce())
+	}
+	if next, err := nonces.Next(ctx); err != nil || next != tx.Nonce()+1 {
+		t.Errorf("got next nonce %d, %v, want %d", next, err, tx.Nonce()+1)
+	}
+}
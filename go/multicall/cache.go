@@ -0,0 +1,204 @@
+package multicall
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultCacheTTLs are the time to live of the results of the getters that
+// NewResultCache caches by default, metadata of tokens and pairs that is set
+// when the contract is deployed and never changes after
+var DefaultCacheTTLs = map[string]time.Duration{
+	"decimals()": 24 * time.Hour,
+	"symbol()":   24 * time.Hour,
+	"name()":     24 * time.Hour,
+	"token0()":   24 * time.Hour,
+	"token1()":   24 * time.Hour,
+	"factory()":  24 * time.Hour,
+}
+
+// ResultCache caches the results of calls that are effectively immutable,
+// such as the decimals of a token, so that the clients using it, see
+// WithResultCache, stop executing them on every batch. Calls are cached by
+// target and calldata for the time to live of their method, and only if they
+// succeed with return data: calls to accounts without code succeed with
+// none. It is safe for concurrent use.
+type ResultCache struct {
+	ttls map[[4]byte]time.Duration
+
+	mu      sync.Mutex
+	entries map[callKey]cacheEntry
+	// pruneAt is the number of entries at which expired entries are evicted
+	pruneAt int
+}
+
+// minPruneAt is the number of entries below which expired entries are only
+// evicted when looked up
+const minPruneAt = 1024
+
+// cacheEntry is a cached result along with its expiry
+type cacheEntry struct {
+	returnData []byte
+	expires    time.Time
+}
+
+// NewResultCache returns a cache of the results of the methods of ttls, such
+// as "decimals()", each for its time to live. Methods are given by their
+// signature, and calls of a method with different arguments are cached
+// separately. A nil ttls means DefaultCacheTTLs.
+func NewResultCache(ttls map[string]time.Duration) *ResultCache {
+	if ttls == nil {
+		ttls = DefaultCacheTTLs
+	}
+	cache := &ResultCache{
+		ttls:    make(map[[4]byte]time.Duration, len(ttls)),
+		entries: make(map[callKey]cacheEntry),
+		pruneAt: minPruneAt,
+	}
+	for signature, ttl := range ttls {
+		if ttl > 0 {
+			cache.ttls[[4]byte(crypto.Keccak256([]byte(signature))[:4])] = ttl
+		}
+	}
+	return cache
+}
+
+// ttl returns the time to live of the result of call, or zero if it is not
+// cached
+func (r *ResultCache) ttl(call Call3) time.Duration {
+	if len(call.CallData) < 4 {
+		return 0
+	}
+	return r.ttls[[4]byte(call.CallData[:4])]
+}
+
+// get returns the unexpired result of call, if cached
+func (r *ResultCache) get(call Call3, now time.Time) (Result, bool) {
+	key := callKey{target: call.Target, callData: string(call.CallData)}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[key]
+	if !ok {
+		return Result{}, false
+	}
+	if !now.Before(entry.expires) {
+		delete(r.entries, key)
+		return Result{}, false
+	}
+	// Copied, so that callers cannot change the cached result
+	return Result{Success: true, ReturnData: append([]byte(nil), entry.returnData...)}, true
+}
+
+// put caches the result of call for ttl, evicting the expired entries once
+// they may make up half of the cache, so that it does not grow without bound
+// with calls that are not looked up again
+func (r *ResultCache) put(call Call3, result Result, ttl time.Duration, now time.Time) {
+	key := callKey{target: call.Target, callData: string(call.CallData)}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[key] = cacheEntry{returnData: append([]byte(nil), result.ReturnData...), expires: now.Add(ttl)}
+	if len(r.entries) < r.pruneAt {
+		return
+	}
+	for key, entry := range r.entries {
+		if !now.Before(entry.expires) {
+			delete(r.entries, key)
+		}
+	}
+	r.pruneAt = max(2*len(r.entries), minPruneAt)
+}
+
+// Len returns the number of results cached, including expired results that
+// have not been evicted yet
+func (r *ResultCache) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// Clear evicts every result from the cache
+func (r *ResultCache) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = make(map[callKey]cacheEntry)
+	r.pruneAt = minPruneAt
+}
+
+// aggregate3Cached executes the calls whose results are not in the client's
+// cache like Aggregate3, and caches the results of those of them that
+// are cacheable and succeed. The cache is bypassed by calls with state or
+// block overrides or another sender, whose results are not those of the
+// chain.
+func (c *Client) aggregate3Cached(ctx context.Context, calls []Call3, opts []CallOption) ([]Result, error) {
+	o := newCallOptions(opts)
+	if o.err != nil || len(o.overrides) > 0 || o.blockOverrides != nil || o.from != nil {
+		return c.aggregate3Uncached(ctx, calls, opts)
+	}
+	now := time.Now()
+	results := make([]Result, len(calls))
+	// index maps each call to its position among the calls executed, or -1
+	// if its result was cached
+	index := make([]int, len(calls))
+	var misses []Call3
+	for i, call := range calls {
+		if c.cache.ttl(call) > 0 {
+			if result, ok := c.cache.get(call, now); ok {
+				results[i] = result
+				index[i] = -1
+				continue
+			}
+		}
+		index[i] = len(misses)
+		misses = append(misses, call)
+	}
+	if len(misses) == len(calls) {
+		executed, err := c.aggregate3Uncached(ctx, calls, opts)
+		c.cacheResults(calls, executed, err, now)
+		return executed, err
+	}
+	if len(misses) == 0 {
+		return results, nil
+	}
+	executed, err := c.aggregate3Uncached(ctx, misses, opts)
+	incompleteErr := incomplete(err)
+	if err != nil && incompleteErr == nil {
+		remapOutOfGas(err, index)
+		return nil, err
+	}
+	if len(executed) != len(misses) {
+		return nil, fmt.Errorf("multicall: got %d results for %d calls", len(executed), len(misses))
+	}
+	c.cacheResults(misses, executed, err, now)
+	var missing []int
+	for i, j := range index {
+		if j < 0 {
+			continue
+		}
+		results[i] = executed[j]
+		if missed(incompleteErr, j) {
+			missing = append(missing, i)
+		}
+	}
+	if incompleteErr != nil {
+		return results, &IncompleteError{Missing: missing, Err: incompleteErr.Err}
+	}
+	return results, nil
+}
+
+// cacheResults caches the successful results of calls that are cacheable and
+// returned data, given the error calls were executed with
+func (c *Client) cacheResults(calls []Call3, results []Result, err error, now time.Time) {
+	incompleteErr := incomplete(err)
+	if err != nil && incompleteErr == nil || len(results) != len(calls) {
+		return
+	}
+	for i, call := range calls {
+		if ttl := c.cache.ttl(call); ttl > 0 && results[i].Success && len(results[i].ReturnData) > 0 && !missed(incompleteErr, i) {
+			c.cache.put(call, results[i], ttl, now)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package multicall
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestResultCacheTTL(t *testing.T) {
+	cache := NewResultCache(map[string]time.Duration{"balanceOf(address)": time.Minute})
+	call := balanceCall(1)
+	if ttl := cache.ttl(call); ttl != time.Minute {
+		t.Fatalf("got time to live %s, want 1m", ttl)
+	}
+	if ttl := cache.ttl(Call3{Target: tokenAddress, CallData: []byte{0x01}}); ttl != 0 {
+		t.Errorf("short calldata has time to live %s, want none", ttl)
+	}
+	if ttl := NewResultCache(nil).ttl(call); ttl != 0 {
+		t.Errorf("balanceOf cached by default for %s", ttl)
+	}
+
+	now := time.Now()
+	cache.put(call, Result{Success: true, ReturnData: []byte{1}}, time.Minute, now)
+	if _, ok := cache.get(call, now.Add(59*time.Second)); !ok {
+		t.Error("result not cached before its expiry")
+	}
+	if _, ok := cache.get(call, now.Add(time.Minute)); ok {
+		t.Error("result cached past its expiry")
+	}
+	if n := cache.Len(); n != 0 {
+		t.Errorf("expired result not evicted, %d results cached", n)
+	}
+}
+
+func TestResultCacheCopies(t *testing.T) {
+	cache := NewResultCache(nil)
+	call := balanceCall(1)
+	now := time.Now()
+	data := []byte{1, 2}
+	cache.put(call, Result{Success: true, ReturnData: data}, time.Minute, now)
+	data[0] = 9
+	result, _ := cache.get(call, now)
+	result.ReturnData[1] = 9
+	if result, _ := cache.get(call, now); !reflect.DeepEqual(result.ReturnData, []byte{1, 2}) {
+		t.Errorf("got cached data %x, want 0102", result.ReturnData)
+	}
+}
+
+func TestResultCachePrunes(t *testing.T) {
+	cache := NewResultCache(nil)
+	now := time.Now()
+	for i := 0; i < minPruneAt-1; i++ {
+		call := Call3{Target: common.BigToAddress(common.Big1), CallData: []byte{byte(i), byte(i >> 8), 0, 0}}
+		cache.put(call, Result{Success: true, ReturnData: []byte{1}}, time.Second, now)
+	}
+	// Putting one more once the others have expired evicts them
+	cache.put(balanceCall(1), Result{Success: true, ReturnData: []byte{1}}, time.Minute, now.Add(time.Second))
+	if n := cache.Len(); n != 1 {
+		t.Errorf("got %d results cached, want 1", n)
+	}
+}
+
+func TestClientCachesResults(t *testing.T) {
+	cache := NewResultCache(map[string]time.Duration{"balanceOf(address)": time.Minute})
+	client, caller := newFakeClient(WithResultCache(cache))
+	// Calls to accounts without code succeed with no data, and are not cached
+	noCode := balanceCall(2)
+	noCode.Target = common.Address{0xee}
+	calls := []Call3{balanceCall(1), noCode}
+	for i := 0; i < 2; i++ {
+		results, err := client.Aggregate3(context.Background(), calls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkBalance(t, results[0], 1)
+	}
+	if got, want := caller.Executed(), []int{2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("executed multicalls of %v calls, want %v", got, want)
+	}
+	if n := cache.Len(); n != 1 {
+		t.Errorf("got %d results cached, want 1", n)
+	}
+	cache.Clear()
+	if _, err := client.Aggregate3(context.Background(), calls[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(caller.Executed()); got != 3 {
+		t.Errorf("got %d multicalls after clearing the cache, want 3", got)
+	}
+}
@@ -0,0 +1,92 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// CallBackend is the connection to a node a Client uses, beyond the calls
+// themselves: the chain it is connected to, block headers and new heads.
+// NewClient only requires an ethereum.ContractCaller, and uses the other
+// methods when its caller implements them, so that *ethclient.Client, the
+// provider package's *Pool, wrappers of an *rpc.Client or custom transports
+// can all be used. Implementing the whole interface enables every feature
+// that does not need raw JSON-RPC requests, see WithRPCClient.
+type CallBackend interface {
+	ethereum.ContractCaller
+	ChainIDReader
+	headerReader
+	headSubscriber
+}
+
+var _ CallBackend = (*ethclient.Client)(nil)
+
+// headerReader is implemented by contract callers that can read block
+// headers, such as *ethclient.Client
+type headerReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// headSubscriber is implemented by contract callers that can subscribe to new
+// blocks, such as *ethclient.Client
+type headSubscriber interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// headerByNumber reads the header of the block with the given number, or of
+// the latest block if number is nil, through the client's contract caller,
+// for clients without an RPC client
+func (c *Client) headerByNumber(ctx context.Context, use string, number *big.Int) (*types.Header, error) {
+	reader, ok := c.caller.(headerReader)
+	if !ok {
+		return nil, fmt.Errorf("multicall: %s needs an RPC client or a contract caller that can read headers, see WithRPCClient", use)
+	}
+	header, err := reader.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("multicall: get block: %w", classify(err))
+	}
+	return header, nil
+}
+
+// Watch executes the batch at every new block, through the subscription to
+// new heads of the client's contract caller, and passes each header to handle
+// along with the results of the batch, or the error that prevented them from
+// being read, as returned by ExecuteResults. Blocks are handled one at a time
+// and blocks mined while handle runs may be skipped. It returns when ctx is
+// done, with its error, or once the subscription fails.
+func (b *Batch) Watch(ctx context.Context, handle func(header *types.Header, results []CallResult, err error), opts ...CallOption) error {
+	subscriber, ok := b.client.caller.(headSubscriber)
+	if !ok {
+		return errors.New("multicall: contract caller cannot subscribe to new blocks")
+	}
+	heads := make(chan *types.Header, 1)
+	sub, err := subscriber.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return fmt.Errorf("multicall: subscribe to new blocks: %w", err)
+	}
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			if err == nil {
+				return errors.New("multicall: new block subscription closed")
+			}
+			return fmt.Errorf("multicall: new block subscription: %w", err)
+		case header := <-heads:
+			// Skip to the latest head if more have arrived
+			for len(heads) > 0 {
+				header = <-heads
+			}
+			results, err := b.ExecuteResults(ctx, append(opts[:len(opts):len(opts)], AtBlock(header.Number))...)
+			handle(header, results, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package multicall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// callManyBundle is a bundle of transactions of eth_callMany
+type callManyBundle struct {
+	Transactions []backendCall `json:"transactions"`
+}
+
+// callManyContext is the state context of eth_callMany: the block whose
+// state the bundles execute on, and the index of the transaction of that
+// block they execute after, -1 for after all of them
+type callManyContext struct {
+	BlockNumber      interface{} `json:"blockNumber"`
+	TransactionIndex int         `json:"transactionIndex"`
+}
+
+// callManyResult is the result of a transaction of eth_callMany. Erigon
+// reports errors as JSON-RPC errors, with the revert data, and Reth as
+// strings.
+type callManyResult struct {
+	Value hexutil.Bytes   `json:"value"`
+	Error json.RawMessage `json:"error"`
+}
+
+// revertData returns the revert data of a failed transaction, if the node
+// reported it
+func (r *callManyResult) revertData() []byte {
+	var rpcErr struct {
+		Data hexutil.Bytes `json:"data"`
+	}
+	if json.Unmarshal(r.Error, &rpcErr) == nil {
+		return rpcErr.Data
+	}
+	return nil
+}
+
+// callMany executes calls as a single bundle with eth_callMany, on the state
+// at the end of the selected block. Block overrides are named differently by
+// Erigon and Reth, so calls with them are left to the next backend.
+func callMany(ctx context.Context, c *Client, client *rpc.Client, opts *callOptions, calls []Call3, values []*big.Int) ([]Result, error) {
+	if opts.blockOverrides != nil {
+		return nil, errBackendSkipped
+	}
+	bundle := callManyBundle{Transactions: backendCalls(c, opts, calls, values)}
+	state := callManyContext{BlockNumber: blockArg(opts), TransactionIndex: -1}
+	var bundles [][]callManyResult
+	if err := client.CallContext(ctx, &bundles, "eth_callMany", []callManyBundle{bundle}, state, c.backendOverrides(opts)); err != nil {
+		return nil, err
+	}
+	if len(bundles) != 1 {
+		return nil, fmt.Errorf("got %d bundles for 1", len(bundles))
+	}
+	results := make([]Result, len(bundles[0]))
+	for i, result := range bundles[0] {
+		if len(result.Error) > 0 && string(result.Error) != "null" {
+			results[i] = Result{ReturnData: result.revertData()}
+			continue
+		}
+		results[i] = Result{Success: true, ReturnData: result.Value}
+	}
+	return results, nil
+}
+
+// traceCallManyResult is the result of a transaction of trace_callMany
+type traceCallManyResult struct {
+	Output hexutil.Bytes `json:"output"`
+	// Trace holds the top-level call of the transaction, whose error is set
+	// if it failed
+	Trace []struct {
+		Error string `json:"error"`
+	} `json:"trace"`
+}
+
+// traceCallMany executes calls one after the other with trace_callMany,
+// which older Erigon and OpenEthereum nodes have instead of eth_callMany. It
+// takes no state overrides, so that calls with overrides are left to the
+// next backend, and the Multicall3 helpers need Multicall3 to be deployed.
+func traceCallMany(ctx context.Context, c *Client, client *rpc.Client, opts *callOptions, calls []Call3, values []*big.Int) ([]Result, error) {
+	if len(opts.overrides) > 0 || opts.blockOverrides != nil {
+		return nil, errBackendSkipped
+	}
+	txs := backendCalls(c, opts, calls, values)
+	params := make([][]interface{}, len(txs))
+	for i, tx := range txs {
+		params[i] = []interface{}{tx, []string{"trace"}}
+	}
+	var traces []traceCallManyResult
+	if err := client.CallContext(ctx, &traces, "trace_callMany", params, blockArg(opts)); err != nil {
+		return nil, err
+	}
+	results := make([]Result, len(traces))
+	for i, trace := range traces {
+		results[i] = Result{Success: len(trace.Trace) > 0 && trace.Trace[0].Error == "", ReturnData: trace.Output}
+	}
+	return results, nil
+}
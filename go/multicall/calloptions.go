@@ -0,0 +1,217 @@
+package multicall
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// CallOption configures the eth_call a multicall is executed with
+type CallOption func(*callOptions)
+
+// callOptions are the eth_call parameters shared by the multicalls of a batch
+type callOptions struct {
+	// block is the block the calls are executed against, nil for latest
+	block *big.Int
+	// blockHash, if set, takes precedence over block
+	blockHash *common.Hash
+	// overrides are applied to the state for the duration of the call
+	overrides StateOverride
+	// blockOverrides replace fields of the block context of the call
+	blockOverrides *BlockOverride
+	// from, if set, is the account the calls are made from
+	from *common.Address
+	// gas, gasPrice, gasFeeCap and gasTipCap are the gas fields of the
+	// eth_call, left to the node when unset
+	gas       uint64
+	gasPrice  *big.Int
+	gasFeeCap *big.Int
+	gasTipCap *big.Int
+	// partial keeps the results of the executed calls when the deadline
+	// expires
+	partial bool
+	// verifyBlock checks the block of block aggregates against its header
+	verifyBlock bool
+	// err reports an invalid option
+	err error
+}
+
+func newCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.err == nil && o.gasPrice != nil && (o.gasFeeCap != nil || o.gasTipCap != nil) {
+		o.err = errors.New("multicall: gas price cannot be combined with max fees per gas")
+	}
+	return o
+}
+
+// AtBlock executes the multicall against the state of the block with the
+// given number, instead of the latest block. Old blocks need an archive node.
+func AtBlock(number *big.Int) CallOption {
+	return func(o *callOptions) {
+		o.block = number
+		o.blockHash = nil
+	}
+}
+
+// AtBlockHash executes the multicall against the state of the block with the
+// given hash, which unlike a number also pins the chain the block is on. The
+// contract caller must implement CallContractAtHash, as ethclient.Client
+// does.
+func AtBlockHash(hash common.Hash) CallOption {
+	return func(o *callOptions) {
+		o.block = nil
+		o.blockHash = &hash
+	}
+}
+
+// BlockTag is a symbolic block of the eth_call block parameter
+type BlockTag string
+
+// The block tags nodes accept
+const (
+	// Latest is the most recent block, the default
+	Latest BlockTag = "latest"
+	// Safe is the most recent block that is unlikely to be reorged out
+	Safe BlockTag = "safe"
+	// Finalized is the most recent block that cannot be reorged out
+	Finalized BlockTag = "finalized"
+	// Pending is the block being built, including pending transactions
+	Pending BlockTag = "pending"
+	// Earliest is the genesis block
+	Earliest BlockTag = "earliest"
+)
+
+// blockTagNumbers are the numbers go-ethereum represents block tags with
+var blockTagNumbers = map[BlockTag]rpc.BlockNumber{
+	Latest:    rpc.LatestBlockNumber,
+	Safe:      rpc.SafeBlockNumber,
+	Finalized: rpc.FinalizedBlockNumber,
+	Pending:   rpc.PendingBlockNumber,
+	Earliest:  rpc.EarliestBlockNumber,
+}
+
+// ParseBlockTag returns the block tag named s, such as "finalized"
+func ParseBlockTag(s string) (BlockTag, error) {
+	tag := BlockTag(s)
+	if _, ok := blockTagNumbers[tag]; !ok {
+		return "", fmt.Errorf("multicall: unknown block tag %q", s)
+	}
+	return tag, nil
+}
+
+// AtBlockTag executes the multicall against the block the tag refers to when
+// it is sent, trading freshness for reorg safety: the Safe and Finalized
+// blocks lag behind the latest block but are unlikely to, or cannot, be
+// reorged out. When a batch is split into several multicalls, the tag is
+// resolved to a block number first so that they all see the same state,
+// except for Pending, which has no number yet.
+func AtBlockTag(tag BlockTag) CallOption {
+	return func(o *callOptions) {
+		number, ok := blockTagNumbers[tag]
+		if !ok {
+			o.err = fmt.Errorf("multicall: unknown block tag %q", tag)
+			return
+		}
+		o.block, o.blockHash = nil, nil
+		if tag != Latest {
+			o.block = big.NewInt(int64(number))
+		}
+	}
+}
+
+// StateOverride maps accounts to the changes made to their state for the
+// duration of a call: their balance, nonce, code or storage
+type StateOverride = map[common.Address]gethclient.OverrideAccount
+
+// OverrideState executes the multicall with the state of accounts changed as
+// given by overrides, for example to fake a balance, replace the code of a
+// contract or set storage slots. Accounts overridden several times keep the
+// last override. The client needs a caller supporting state overrides, set
+// with WithOverrideCaller or WithCodeOverride.
+func OverrideState(overrides StateOverride) CallOption {
+	return func(o *callOptions) {
+		if o.overrides == nil {
+			o.overrides = make(StateOverride, len(overrides))
+		}
+		for account, override := range overrides {
+			o.overrides[account] = override
+		}
+	}
+}
+
+// BlockOverride replaces fields of the block context a call is executed in:
+// its number, timestamp, base fee, coinbase and so on. Zero fields are left
+// unchanged.
+type BlockOverride = gethclient.BlockOverrides
+
+// OverrideBlock executes the multicall as if in a block whose context fields
+// are replaced by overrides, for example to query a vesting or auction
+// contract as of a future timestamp. The override caller set with
+// WithOverrideCaller must also implement CallContractWithBlockOverrides, as
+// *gethclient.Client does.
+func OverrideBlock(overrides BlockOverride) CallOption {
+	return func(o *callOptions) {
+		o.blockOverrides = &overrides
+	}
+}
+
+// From executes the calls of the multicall as if made directly by account,
+// for view functions whose result depends on msg.sender, such as permissioned
+// getters. Multicall3 makes the calls itself, so the multicall is sent to
+// account, from account, with the Multicall3 runtime code injected at it
+// using a state override: any code account has is replaced for the duration
+// of the call. The client needs a caller supporting state overrides, set with
+// WithOverrideCaller, and cannot be in deployless mode.
+func From(account common.Address) CallOption {
+	return func(o *callOptions) {
+		o.from = &account
+	}
+}
+
+// Gas sets the gas limit of the multicall, for providers whose default
+// eth_call gas cap is too low for large batches or that reject calls without
+// one. The gas is shared by all the calls of the multicall.
+func Gas(limit uint64) CallOption {
+	return func(o *callOptions) {
+		o.gas = limit
+	}
+}
+
+// GasPrice sets the legacy gas price of the multicall, for contracts that
+// read tx.gasprice. Nodes such as geth then check that the sender, see From,
+// can pay for the gas at that price. It cannot be combined with MaxFeePerGas.
+func GasPrice(price *big.Int) CallOption {
+	return func(o *callOptions) {
+		o.gasPrice = price
+	}
+}
+
+// MaxFeePerGas sets the EIP-1559 fee cap and priority fee of the multicall,
+// either of which may be nil. Like with GasPrice, the sender may need to be
+// able to pay for the gas.
+func MaxFeePerGas(feeCap, tipCap *big.Int) CallOption {
+	return func(o *callOptions) {
+		o.gasFeeCap = feeCap
+		o.gasTipCap = tipCap
+	}
+}
+
+// PartialResults makes a batch that runs out of time return the results of
+// the calls executed so far, instead of discarding them, along with an
+// *IncompleteError listing the calls that were not executed. Chunks that fail
+// because the context's deadline or the client's chunk timeout, see
+// WithChunkTimeout, expired are skipped, and any other error still fails the
+// whole batch. It is meant for large scans split into chunks, see
+// WithMaxCalls.
+func PartialResults() CallOption {
+	return func(o *callOptions) {
+		o.partial = true
+	}
+}
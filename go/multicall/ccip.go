@@ -0,0 +1,254 @@
+package multicall
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"golang.org/x/sync/errgroup"
+)
+
+// offchainLookupError is the EIP-3668 error contracts revert with to ask for
+// data to be fetched from an offchain gateway
+var offchainLookupError = helper.MustParseABI("multicall", `[{"type":"error","name":"OffchainLookup","inputs":[
+	{"name":"sender","type":"address"},
+	{"name":"urls","type":"string[]"},
+	{"name":"callData","type":"bytes"},
+	{"name":"callbackFunction","type":"bytes4"},
+	{"name":"extraData","type":"bytes"}
+]}]`).Errors["OffchainLookup"]
+
+// callbackArguments are the arguments of EIP-3668 callback functions
+var callbackArguments = abi.Arguments{{Type: bytesType}, {Type: bytesType}}
+
+var bytesType, _ = abi.NewType("bytes", "", nil)
+
+// maxOffchainLookups bounds the number of lookups a call may chain, as the
+// callback of a lookup may revert with another lookup
+const maxOffchainLookups = 4
+
+// offchainLookup is a decoded OffchainLookup revert
+type offchainLookup struct {
+	Sender           common.Address
+	URLs             []string
+	CallData         []byte
+	CallbackFunction [4]byte
+	ExtraData        []byte
+}
+
+// parseOffchainLookup decodes the revert data of a failed call as an
+// OffchainLookup, reporting false if it is not one
+func parseOffchainLookup(result Result) (*offchainLookup, bool) {
+	if result.Success || !bytes.HasPrefix(result.ReturnData, offchainLookupError.ID[:4]) {
+		return nil, false
+	}
+	values, err := offchainLookupError.Inputs.Unpack(result.ReturnData[4:])
+	if err != nil {
+		return nil, false
+	}
+	return &offchainLookup{
+		Sender:           values[0].(common.Address),
+		URLs:             values[1].([]string),
+		CallData:         values[2].([]byte),
+		CallbackFunction: values[3].([4]byte),
+		ExtraData:        values[4].([]byte),
+	}, true
+}
+
+// ccipReader fetches offchain data from EIP-3668 gateways
+type ccipReader struct {
+	client *http.Client
+}
+
+// gatewayStatusError reports a gateway response with an error status
+type gatewayStatusError struct {
+	url    string
+	status int
+}
+
+func (e *gatewayStatusError) Error() string {
+	return fmt.Sprintf("gateway %s: %d %s", e.url, e.status, http.StatusText(e.status))
+}
+
+// fetch queries the gateways of lookup in order until one responds, as EIP-3668
+// specifies: URLs with a {data} parameter are fetched with GET, the others
+// with a POST of the sender and calldata. A gateway failing with a 4xx status
+// ends the lookup, other failures move on to the next gateway.
+func (r *ccipReader) fetch(ctx context.Context, lookup *offchainLookup) ([]byte, error) {
+	sender := strings.ToLower(lookup.Sender.Hex())
+	data := hexutil.Encode(lookup.CallData)
+	var errs []error
+	for _, template := range lookup.URLs {
+		url := strings.ReplaceAll(strings.ReplaceAll(template, "{sender}", sender), "{data}", data)
+		var req *http.Request
+		var err error
+		if strings.Contains(template, "{data}") {
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		} else {
+			body, _ := json.Marshal(map[string]string{"data": data, "sender": sender})
+			req, err = http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+			if req != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("gateway %s: %w", url, err))
+			continue
+		}
+		response, err := r.do(req)
+		if err == nil {
+			return response, nil
+		}
+		errs = append(errs, err)
+		var statusErr *gatewayStatusError
+		if errors.As(err, &statusErr) && statusErr.status >= 400 && statusErr.status < 500 {
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("offchain lookup without gateway URLs")
+	}
+	return nil, errors.Join(errs...)
+}
+
+// do sends a gateway request and decodes the data of its response
+func (r *ccipReader) do(req *http.Request) ([]byte, error) {
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gateway %s: %w", req.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		io.Copy(io.Discard, resp.Body)
+		return nil, &gatewayStatusError{url: req.URL.String(), status: resp.StatusCode}
+	}
+	var body struct {
+		Data hexutil.Bytes `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("gateway %s: decode response: %w", req.URL, err)
+	}
+	return body.Data, nil
+}
+
+// aggregate3CCIP executes calls like Aggregate3, resolving the offchain
+// lookups the calls revert with. Every call is sent allowed to fail, so that
+// a lookup does not revert the whole multicall, and the calls that still fail
+// once their lookups are resolved fail the batch unless they are allowed to,
+// with the errors of all of them joined.
+func (c *Client) aggregate3CCIP(ctx context.Context, calls []Call3, opts []CallOption) ([]Result, error) {
+	lenient := make([]Call3, len(calls))
+	for i, call := range calls {
+		lenient[i] = call
+		lenient[i].AllowFailure = true
+	}
+	results, err := c.aggregate3(ctx, lenient, opts)
+	if err != nil {
+		return results, err
+	}
+	failures, err := c.resolveOffchainLookups(ctx, calls, results, opts)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for i, result := range results {
+		if result.Success || calls[i].AllowFailure {
+			continue
+		}
+		if failures[i] != nil {
+			errs = append(errs, withKind(ErrExecutionReverted, fmt.Errorf("multicall: call %d failed: offchain lookup: %w", i, failures[i])))
+		} else {
+			errs = append(errs, withKind(ErrExecutionReverted, fmt.Errorf("multicall: call %d failed: %s", i, result.Reason())))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, helper.JoinErrors(errs...)
+	}
+	return results, nil
+}
+
+// resolveOffchainLookups replaces the results of the calls that reverted with
+// an OffchainLookup by the results of their callbacks, once the data asked
+// for has been fetched, for up to maxOffchainLookups chained lookups. It
+// returns the error of the gateways of the lookups that could not be
+// resolved, by call.
+func (c *Client) resolveOffchainLookups(ctx context.Context, calls []Call3, results []Result, opts []CallOption) (map[int]error, error) {
+	failures := make(map[int]error)
+	for round := 0; round < maxOffchainLookups; round++ {
+		var (
+			pending []int
+			lookups []*offchainLookup
+		)
+		for i, result := range results {
+			lookup, ok := parseOffchainLookup(result)
+			// EIP-3668 only trusts lookups raised by the contract called
+			if ok && lookup.Sender == calls[i].Target && failures[i] == nil {
+				pending = append(pending, i)
+				lookups = append(lookups, lookup)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		responses := make([][]byte, len(lookups))
+		fetchErrs := make([]error, len(lookups))
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.SetLimit(8)
+		for j := range lookups {
+			j := j
+			group.Go(func() error {
+				responses[j], fetchErrs[j] = c.ccip.fetch(groupCtx, lookups[j])
+				return nil
+			})
+		}
+		group.Wait()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var (
+			callbacks []Call3
+			callers   []int
+		)
+		for j, i := range pending {
+			if fetchErrs[j] != nil {
+				failures[i] = fetchErrs[j]
+				continue
+			}
+			args, err := callbackArguments.Pack(responses[j], lookups[j].ExtraData)
+			if err != nil {
+				return nil, fmt.Errorf("multicall: pack callback of call %d: %w", i, err)
+			}
+			callbacks = append(callbacks, Call3{
+				Target:       lookups[j].Sender,
+				AllowFailure: true,
+				CallData:     append(lookups[j].CallbackFunction[:], args...),
+			})
+			callers = append(callers, i)
+		}
+		if len(callbacks) == 0 {
+			break
+		}
+		callbackResults, err := c.aggregate3(ctx, callbacks, opts)
+		if err != nil {
+			return nil, fmt.Errorf("multicall: execute offchain lookup callbacks: %w", err)
+		}
+		for j, i := range callers {
+			results[i] = callbackResults[j]
+		}
+	}
+	return failures, nil
+}
@@ -0,0 +1,213 @@
+package multicall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/internal/helper"
+)
+
+// resolverABI is the ABI of a CCIP-Read contract, whose resolve function
+// reverts with an offchain lookup answered by resolveWithProof
+var resolverABI = helper.MustParseABI("multicall", `[
+	{"type":"function","name":"resolve","stateMutability":"view","inputs":[{"name":"query","type":"bytes"}],"outputs":[{"name":"","type":"bytes"}]},
+	{"type":"function","name":"resolveWithProof","stateMutability":"view","inputs":[{"name":"response","type":"bytes"},{"name":"extraData","type":"bytes"}],"outputs":[{"name":"","type":"bytes"}]}
+]`)
+
+var resolverAddress = common.HexToAddress("0x5000000000000000000000000000000000000005")
+
+// resolver returns a contract at resolverAddress that looks queries up on
+// the gateways at urls, on behalf of sender, and answers with the response
+// of the gateway
+func resolver(sender common.Address, urls ...string) fake.Contract {
+	return fake.Methods(resolverABI, map[string]func(args []interface{}) ([]interface{}, error){
+		"resolve": func(args []interface{}) ([]interface{}, error) {
+			var callback [4]byte
+			copy(callback[:], resolverABI.Methods["resolveWithProof"].ID)
+			lookup, err := offchainLookupError.Inputs.Pack(sender, urls, args[0].([]byte), callback, []byte("extra"))
+			if err != nil {
+				return nil, err
+			}
+			return nil, &fake.Revert{Data: append(offchainLookupError.ID[:4:4], lookup...)}
+		},
+		"resolveWithProof": func(args []interface{}) ([]interface{}, error) {
+			if string(args[1].([]byte)) != "extra" {
+				return nil, &fake.Revert{}
+			}
+			return []interface{}{args[0]}, nil
+		},
+	})
+}
+
+// gateway answers lookups with "answer to " and the query, or with status if
+// it is set, counting the requests it gets
+type gateway struct {
+	status   int
+	requests atomic.Int32
+}
+
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.requests.Add(1)
+	if g.status != 0 {
+		http.Error(w, http.StatusText(g.status), g.status)
+		return
+	}
+	var query struct {
+		Data   hexutil.Bytes `json:"data"`
+		Sender string        `json:"sender"`
+	}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		// The path is /{sender}/{data}.json
+		parts := strings.Split(strings.TrimSuffix(r.URL.Path, ".json"), "/")
+		data, err := hexutil.Decode(parts[len(parts)-1])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		query.Data, query.Sender = data, parts[len(parts)-2]
+	}
+	if query.Sender != strings.ToLower(resolverAddress.Hex()) {
+		http.Error(w, "unknown sender", http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]hexutil.Bytes{"data": append([]byte("answer to "), query.Data...)})
+}
+
+func newGateway(t *testing.T, status int) (*gateway, string) {
+	t.Helper()
+	g := &gateway{status: status}
+	server := httptest.NewServer(g)
+	t.Cleanup(server.Close)
+	return g, server.URL
+}
+
+// newResolverClient returns a client following offchain lookups, executing
+// its calls against the fake token and resolver
+func newResolverClient(t *testing.T, resolver fake.Contract) *Client {
+	t.Helper()
+	client, err := NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		tokenAddress:    fakeToken,
+		resolverAddress: resolver,
+	}), WithCCIPRead(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// resolveCall returns a call of the resolver for query
+func resolveCall(query string) Call3 {
+	data, err := resolverABI.Pack("resolve", []byte(query))
+	if err != nil {
+		panic(err)
+	}
+	return Call3{Target: resolverAddress, CallData: data}
+}
+
+func checkAnswer(t *testing.T, result Result, query string) {
+	t.Helper()
+	if !result.Success {
+		t.Fatalf("lookup failed: %s", result.Reason())
+	}
+	values, err := resolverABI.Unpack("resolveWithProof", result.ReturnData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(values[0].([]byte)), "answer to "+query; got != want {
+		t.Errorf("got answer %q, want %q", got, want)
+	}
+}
+
+func TestCCIPRead(t *testing.T) {
+	_, url := newGateway(t, 0)
+	for _, template := range []string{url + "/{sender}/{data}.json", url} {
+		client := newResolverClient(t, resolver(resolverAddress, template))
+		results, err := client.Aggregate3(context.Background(), []Call3{resolveCall("alice"), balanceCall(1), resolveCall("bob")})
+		if err != nil {
+			t.Fatalf("%s: %v", template, err)
+		}
+		checkAnswer(t, results[0], "alice")
+		checkBalance(t, results[1], 1)
+		checkAnswer(t, results[2], "bob")
+	}
+
+	// Without CCIP-Read, the lookup is the revert of the call
+	client, caller := newFakeClient()
+	caller.Contracts[resolverAddress] = resolver(resolverAddress, url)
+	call := resolveCall("alice")
+	call.AllowFailure = true
+	results, err := client.Aggregate3(context.Background(), []Call3{call})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parseOffchainLookup(results[0]); !ok {
+		t.Errorf("got result %+v, want the offchain lookup", results[0])
+	}
+}
+
+func TestCCIPReadGatewayFailures(t *testing.T) {
+	down, downURL := newGateway(t, http.StatusBadGateway)
+	up, upURL := newGateway(t, 0)
+	client := newResolverClient(t, resolver(resolverAddress, downURL, upURL))
+	results, err := client.Aggregate3(context.Background(), []Call3{resolveCall("alice")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAnswer(t, results[0], "alice")
+	if down.requests.Load() != 1 || up.requests.Load() != 1 {
+		t.Errorf("gateways got %d and %d requests, want 1 each", down.requests.Load(), up.requests.Load())
+	}
+
+	// A 4xx status is the answer of the gateway, the next one is not asked
+	rejecting, rejectingURL := newGateway(t, http.StatusNotFound)
+	up.requests.Store(0)
+	client = newResolverClient(t, resolver(resolverAddress, rejectingURL, upURL))
+	_, err = client.Aggregate3(context.Background(), []Call3{resolveCall("alice")})
+	if !errors.Is(err, ErrExecutionReverted) || !strings.Contains(err.Error(), "offchain lookup") {
+		t.Errorf("got error %v, want the failed lookup", err)
+	}
+	if rejecting.requests.Load() != 1 || up.requests.Load() != 0 {
+		t.Errorf("gateways got %d and %d requests, want the first only", rejecting.requests.Load(), up.requests.Load())
+	}
+
+	// A call allowed to fail keeps the lookup as its result
+	call := resolveCall("alice")
+	call.AllowFailure = true
+	results, err = client.Aggregate3(context.Background(), []Call3{call, balanceCall(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Success {
+		t.Error("call with a failed lookup succeeded")
+	}
+	checkBalance(t, results[1], 2)
+}
+
+func TestCCIPReadUntrustedSender(t *testing.T) {
+	g, url := newGateway(t, 0)
+	// EIP-3668 only follows lookups raised by the contract called
+	client := newResolverClient(t, resolver(tokenAddress, url))
+	call := resolveCall("alice")
+	call.AllowFailure = true
+	results, err := client.Aggregate3(context.Background(), []Call3{call})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Success || g.requests.Load() != 0 {
+		t.Error("lookup raised on behalf of another contract followed")
+	}
+}
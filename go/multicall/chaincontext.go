@@ -0,0 +1,97 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BlockNumber returns a typed call of the Multicall3 getBlockNumber helper.
+// Like the other helper calls below, it reports on the block the batch
+// executes in and is added to a batch with AddSelf, or with AddCall on the
+// multicall contract's address:
+//
+//	number, timestamp := multicall.BlockNumber(), multicall.BlockTimestamp()
+//	batch.AddSelf(number, timestamp)
+func BlockNumber() *CallOf[*big.Int] {
+	return View[*big.Int](ABI, "getBlockNumber")
+}
+
+// BlockTimestamp returns a typed call of getCurrentBlockTimestamp, the
+// timestamp of the block in seconds
+func BlockTimestamp() *CallOf[*big.Int] {
+	return View[*big.Int](ABI, "getCurrentBlockTimestamp")
+}
+
+// Coinbase returns a typed call of getCurrentBlockCoinbase, the fee
+// recipient of the block
+func Coinbase() *CallOf[common.Address] {
+	return View[common.Address](ABI, "getCurrentBlockCoinbase")
+}
+
+// BaseFee returns a typed call of getBasefee, the base fee of the block in
+// wei. It is only available on Multicall3.
+func BaseFee() *CallOf[*big.Int] {
+	return View[*big.Int](ABI, "getBasefee")
+}
+
+// ChainID returns a typed call of getChainId. It is only available on
+// Multicall3.
+func ChainID() *CallOf[*big.Int] {
+	return View[*big.Int](ABI, "getChainId")
+}
+
+// LastBlockHash returns a typed call of getLastBlockHash, the hash of the
+// parent of the block
+func LastBlockHash() *CallOf[common.Hash] {
+	return View[common.Hash](ABI, "getLastBlockHash")
+}
+
+// BlockHash returns a typed call of getBlockHash, the hash of the block
+// numbered number, which is zero unless it is one of the 256 most recent
+// blocks before the one the batch executes in
+func BlockHash(number *big.Int) *CallOf[common.Hash] {
+	return View[common.Hash](ABI, "getBlockHash", number)
+}
+
+// AddSelf appends typed calls on the multicall contract itself, such as the
+// helper calls returned by BlockNumber. Like AddEthBalance, these need the
+// multicall contract to be deployed at the client's address, or injected
+// there with WithCodeOverride.
+func (b *Batch) AddSelf(calls ...TypedCall) *Batch {
+	for _, call := range calls {
+		b.AddCall(b.client.address, call)
+	}
+	return b
+}
+
+// ChainContext is the context of a block, as reported by the Multicall3
+// helpers
+type ChainContext struct {
+	BlockNumber   uint64         `multicall:"blockNumber"`
+	Timestamp     uint64         `multicall:"timestamp"`
+	Coinbase      common.Address `multicall:"coinbase"`
+	BaseFee       *big.Int       `multicall:"baseFee"`
+	ChainID       *big.Int       `multicall:"chainId"`
+	LastBlockHash common.Hash    `multicall:"lastBlockHash"`
+}
+
+// ChainContext fetches the block number, timestamp, coinbase, base fee,
+// chain ID and parent hash of the block the call executes in, selected by
+// opts, in a single call. It needs Multicall3, for the base fee and chain ID.
+func (c *Client) ChainContext(ctx context.Context, opts ...CallOption) (*ChainContext, error) {
+	var chain ChainContext
+	err := c.NewBatch().
+		Add(c.address, ABI, "getBlockNumber").Key("blockNumber").
+		Add(c.address, ABI, "getCurrentBlockTimestamp").Key("timestamp").
+		Add(c.address, ABI, "getCurrentBlockCoinbase").Key("coinbase").
+		Add(c.address, ABI, "getBasefee").Key("baseFee").
+		Add(c.address, ABI, "getChainId").Key("chainId").
+		Add(c.address, ABI, "getLastBlockHash").Key("lastBlockHash").
+		Into(ctx, &chain, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &chain, nil
+}
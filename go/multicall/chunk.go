@@ -0,0 +1,343 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"golang.org/x/sync/errgroup"
+)
+
+// Sizes in bytes of the ABI encoding of a multicall, used to split batches by
+// calldata size. Each call costs its fixed-size head, made of the offset of
+// the call in the array, its static fields and the offset and length of its
+// calldata, plus its calldata padded to a 32 byte word.
+const (
+	// selector, offset of the calls array and its length
+	methodSize = 4 + 32 + 32
+	// requireSuccess argument of tryAggregate and tryBlockAndAggregate
+	requireSuccessSize = 32
+
+	callHeadSize       = 4 * 32
+	call3HeadSize      = 5 * 32
+	call3ValueHeadSize = 6 * 32
+)
+
+func paddedSize(data []byte) int {
+	return (len(data) + 31) / 32 * 32
+}
+
+func callSize(call Call) int             { return callHeadSize + paddedSize(call.CallData) }
+func call3Size(call Call3) int           { return call3HeadSize + paddedSize(call.CallData) }
+func call3ValueSize(call Call3Value) int { return call3ValueHeadSize + paddedSize(call.CallData) }
+
+// chunkLimit returns the maximum number of calls per multicall, or zero if
+// the number of calls is unlimited
+func (c *Client) chunkLimit() int {
+	limit := c.maxCalls
+	if limit == 0 && c.nodeBackend() != nil {
+		limit = backendMaxCalls
+	}
+	if c.adaptive != nil {
+		if adaptive := c.adaptive.limit(); limit == 0 || adaptive < limit {
+			limit = adaptive
+		}
+	}
+	return limit
+}
+
+// chunkLen returns how many of the leading calls fit in a single multicall of
+// at most maxCalls calls and the client's maxCalldataSize bytes. baseSize is
+// the encoded size of the multicall without any call and size returns the
+// encoded size of one call. At least one call is always included.
+func chunkLen[T any](c *Client, calls []T, maxCalls, baseSize int, size func(T) int) int {
+	total := baseSize
+	for i, call := range calls {
+		full := maxCalls > 0 && i == maxCalls
+		total += size(call)
+		tooLarge := c.maxCalldataSize > 0 && total > c.maxCalldataSize
+		if i > 0 && (full || tooLarge) {
+			return i
+		}
+	}
+	return len(calls)
+}
+
+// executeFunc executes calls in a single multicall and returns one result
+// per call
+type executeFunc[T, R any] func(ctx context.Context, opts *callOptions, calls []T) ([]R, error)
+
+// chunked executes calls in chunks with execute and concatenates the results
+// in order. When the calls are split across several multicalls, they are all
+// executed against the same block.
+func chunked[T, R any](ctx context.Context, c *Client, calls []T, opts []CallOption, baseSize int, size func(T) int, execute executeFunc[T, R]) ([]R, error) {
+	ch := &chunker[T, R]{
+		client:   c,
+		opts:     newCallOptions(opts),
+		calls:    calls,
+		baseSize: baseSize,
+		size:     size,
+		execute:  execute,
+	}
+	if ch.opts.err != nil {
+		return nil, ch.opts.err
+	}
+	if len(calls) == 0 {
+		return ch.chunk(ctx, 0, 0)
+	}
+	var (
+		results []R
+		err     error
+	)
+	if c.maxCalls == 0 && c.maxCalldataSize == 0 && c.adaptive == nil || c.concurrency <= 1 {
+		results, err = ch.run(ctx, 0, len(calls))
+	} else {
+		results, err = ch.runConcurrently(ctx)
+	}
+	if err != nil || len(ch.missing) == 0 {
+		return results, err
+	}
+	sort.Ints(ch.missing)
+	return results, &IncompleteError{Missing: ch.missing, Err: ch.missingErr}
+}
+
+// chunker executes the calls of a batch in chunks
+type chunker[T, R any] struct {
+	client   *Client
+	opts     *callOptions
+	calls    []T
+	baseSize int
+	size     func(T) int
+	execute  executeFunc[T, R]
+	// done counts the calls whose chunk has completed
+	done atomic.Int64
+
+	// missing are the calls skipped with PartialResults, and missingErr the
+	// error of the first skipped chunk
+	mu         sync.Mutex
+	missing    []int
+	missingErr error
+}
+
+// run executes calls[start:end] one chunk after the other
+func (ch *chunker[T, R]) run(ctx context.Context, start, end int) ([]R, error) {
+	c := ch.client
+	results := make([]R, 0, end-start)
+	for offset := start; offset < end; {
+		n := chunkLen(c, ch.calls[offset:end], c.chunkLimit(), ch.baseSize, ch.size)
+		if n < len(ch.calls) {
+			if err := c.pinBlock(ctx, ch.opts); err != nil {
+				if ch.skip(ctx, err, offset, end) {
+					return append(results, make([]R, end-offset)...), nil
+				}
+				return nil, err
+			}
+		}
+		chunkResults, err := ch.chunk(ctx, offset, offset+n)
+		if err != nil {
+			if c.adaptive != nil && ctx.Err() == nil && isTooLargeError(err) && c.adaptive.shrink(n) {
+				continue
+			}
+			if ctx.Err() != nil && ch.skip(ctx, err, offset, end) {
+				return append(results, make([]R, end-offset)...), nil
+			}
+			if ch.skip(ctx, err, offset, offset+n) {
+				results = append(results, make([]R, n)...)
+				offset += n
+				continue
+			}
+			return nil, ch.wrap(err, offset, offset+n)
+		}
+		if c.adaptive != nil {
+			c.adaptive.grow()
+		}
+		results = append(results, chunkResults...)
+		offset += n
+	}
+	return results, nil
+}
+
+// runConcurrently splits the batch into chunks up front and runs them on up
+// to the client's concurrency limit at once. A failed chunk does not stop the
+// others, so that the errors of every failed chunk are reported.
+func (ch *chunker[T, R]) runConcurrently(ctx context.Context) ([]R, error) {
+	c := ch.client
+	var bounds []int
+	for offset := 0; offset < len(ch.calls); {
+		bounds = append(bounds, offset)
+		offset += chunkLen(c, ch.calls[offset:], c.chunkLimit(), ch.baseSize, ch.size)
+	}
+	bounds = append(bounds, len(ch.calls))
+	if len(bounds) == 2 {
+		return ch.run(ctx, 0, len(ch.calls))
+	}
+	if err := c.pinBlock(ctx, ch.opts); err != nil {
+		if ch.skip(ctx, err, 0, len(ch.calls)) {
+			return make([]R, len(ch.calls)), nil
+		}
+		return nil, err
+	}
+	chunkResults := make([][]R, len(bounds)-1)
+	errs := make([]error, len(chunkResults))
+	var group errgroup.Group
+	group.SetLimit(c.concurrency)
+	for i := range chunkResults {
+		i := i
+		group.Go(func() error {
+			chunkResults[i], errs[i] = ch.run(ctx, bounds[i], bounds[i+1])
+			return nil
+		})
+	}
+	group.Wait()
+	if err := ch.join(ctx, errs); err != nil {
+		return nil, err
+	}
+	results := make([]R, 0, len(ch.calls))
+	for _, chunk := range chunkResults {
+		results = append(results, chunk...)
+	}
+	return results, nil
+}
+
+// join joins the errors of the chunks that failed, in the order of the
+// chunks. Once ctx is done, every chunk left fails with its error, and only
+// the first failure is returned.
+func (ch *chunker[T, R]) join(ctx context.Context, errs []error) error {
+	if ctx.Err() != nil {
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return helper.JoinErrors(errs...)
+}
+
+// skip records calls[start:end] as missing if err is the expiry of the
+// deadline of ctx, or of a chunk's timeout, and the batch returns partial
+// results. It reports whether the calls were skipped.
+func (ch *chunker[T, R]) skip(ctx context.Context, err error, start, end int) bool {
+	if !ch.opts.partial || !errors.Is(err, context.DeadlineExceeded) && !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	for i := start; i < end; i++ {
+		ch.missing = append(ch.missing, i)
+	}
+	if ch.missingErr == nil {
+		ch.missingErr = err
+	}
+	return true
+}
+
+// chunk executes calls[start:end] as one chunk with bisect, within the
+// client's chunk timeout and, with deadline budgeting, a share of the time
+// left before the deadline of ctx
+func (ch *chunker[T, R]) chunk(ctx context.Context, start, end int) ([]R, error) {
+	c := ch.client
+	timeout := c.chunkTimeout
+	if deadline, ok := ctx.Deadline(); ok && c.budgetDeadline {
+		if share := ch.share(time.Until(deadline), end-start); timeout == 0 || share < timeout {
+			timeout = share
+		}
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	results, err := ch.bisect(ctx, start, end)
+	if err == nil {
+		ch.done.Add(int64(end - start))
+	}
+	return results, err
+}
+
+// share divides the time left evenly between the rounds of chunks of n calls
+// needed to execute the calls that are not done yet, up to the client's
+// concurrency limit of chunks running at once
+func (ch *chunker[T, R]) share(left time.Duration, n int) time.Duration {
+	calls := len(ch.calls) - int(ch.done.Load())
+	chunks := (calls + n - 1) / n
+	rounds := (chunks + ch.client.concurrency - 1) / ch.client.concurrency
+	if rounds < 1 {
+		rounds = 1
+	}
+	return left / time.Duration(rounds)
+}
+
+// bisect executes calls[start:end] in a single multicall, splitting them in
+// half and executing each half separately whenever they would exceed the gas
+// cap or run out of gas, until a call that runs out of gas on its own is
+// isolated. Transient failures are retried according to the client's retry
+// policy.
+func (ch *chunker[T, R]) bisect(ctx context.Context, start, end int) ([]R, error) {
+	results, err := withRetry(ctx, ch.client.retry, func() ([]R, error) {
+		return ch.execute(ctx, ch.opts, ch.calls[start:end])
+	})
+	if err == nil && len(results) != end-start {
+		return nil, fmt.Errorf("multicall: got %d results for %d calls", len(results), end-start)
+	}
+	outOfGas := errors.Is(err, ErrOutOfGas) && ctx.Err() == nil
+	if outOfGas && end-start == 1 {
+		return nil, &OutOfGasError{Call: start, Err: err}
+	}
+	if !errors.Is(err, errGasCapExceeded) && !outOfGas || end-start < 2 {
+		return results, err
+	}
+	if err := ch.client.pinBlock(ctx, ch.opts); err != nil {
+		return nil, err
+	}
+	// When a call of the first half runs out of gas, the second half is still
+	// executed, so that every call that does is reported
+	mid := start + (end-start)/2
+	first, firstErr := ch.bisect(ctx, start, mid)
+	var outOfGasErr *OutOfGasError
+	if firstErr != nil && !errors.As(firstErr, &outOfGasErr) {
+		return nil, firstErr
+	}
+	second, err := ch.bisect(ctx, mid, end)
+	if err := helper.JoinErrors(firstErr, err); err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
+
+// wrap annotates err with the range of calls it occurred in, unless the range
+// is the whole batch
+func (ch *chunker[T, R]) wrap(err error, start, end int) error {
+	if start == 0 && end == len(ch.calls) {
+		return err
+	}
+	return fmt.Errorf("%w (calls %d-%d of %d)", err, start, end-1, len(ch.calls))
+}
+
+// pinBlock fixes the block opts executes against to the current block, or to
+// the number of the block its tag refers to, unless it is already fixed, so
+// that several multicalls observe the same state.
+func (c *Client) pinBlock(ctx context.Context, opts *callOptions) error {
+	if opts.blockHash != nil || opts.block != nil && opts.block.Sign() >= 0 {
+		return nil
+	}
+	if opts.block != nil && opts.block.Int64() == int64(rpc.PendingBlockNumber) {
+		return nil
+	}
+	// The block is looked up without the overrides of the calls, which may
+	// change the reported block number
+	block, err := withRetry(ctx, c.retry, func() (*big.Int, error) {
+		return c.blockNumber(ctx, &callOptions{block: opts.block})
+	})
+	if err != nil {
+		return err
+	}
+	opts.block = block
+	return nil
+}
@@ -0,0 +1,132 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/fake"
+)
+
+func TestAggregate3SplitsByMaxCalls(t *testing.T) {
+	client, caller := newFakeClient(WithMaxCalls(3))
+	calls := make([]Call3, 7)
+	for i := range calls {
+		calls[i] = balanceCall(byte(i + 1))
+	}
+	results, err := client.Aggregate3(context.Background(), calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := caller.Executed(), []int{3, 3, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("executed multicalls of %v calls, want %v", got, want)
+	}
+	checkBalances(t, results, 1, 2, 3, 4, 5, 6, 7)
+}
+
+func TestAggregate3SplitsByCalldataSize(t *testing.T) {
+	// Each balanceOf call takes its head and two words of calldata
+	size := methodSize + 2*call3Size(balanceCall(1))
+	client, caller := newFakeClient(WithMaxCalldataSize(size))
+	calls := []Call3{balanceCall(1), balanceCall(2), balanceCall(3), balanceCall(4), balanceCall(5)}
+	results, err := client.Aggregate3(context.Background(), calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := caller.Executed(), []int{2, 2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("executed multicalls of %v calls, want %v", got, want)
+	}
+	checkBalances(t, results, 1, 2, 3, 4, 5)
+}
+
+func TestAggregate3ConcurrentChunks(t *testing.T) {
+	client, _ := newFakeClient(WithMaxCalls(2), WithConcurrency(3))
+	calls := make([]Call3, 9)
+	for i := range calls {
+		calls[i] = balanceCall(byte(i + 1))
+	}
+	results, err := client.Aggregate3(context.Background(), calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBalances(t, results, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+}
+
+func TestAggregate3BoundsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	var running, most int
+	slow := func(ctx context.Context, data []byte) ([]byte, error) {
+		mu.Lock()
+		running++
+		most = max(most, running)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil, nil
+	}
+	target := common.Address{0x55}
+	client, err := NewClient(fake.NewCaller(map[common.Address]fake.Contract{target: slow}), WithMaxCalls(1), WithConcurrency(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := make([]Call3, 8)
+	for i := range calls {
+		calls[i] = Call3{Target: target}
+	}
+	if _, err := client.Aggregate3(context.Background(), calls); err != nil {
+		t.Fatal(err)
+	}
+	if most != 3 {
+		t.Errorf("got at most %d chunks executed at once, want 3", most)
+	}
+}
+
+func TestAggregate3PartialResults(t *testing.T) {
+	client, _ := newFakeClient(WithMaxCalls(1), WithChunkTimeout(20*time.Millisecond))
+	calls := []Call3{balanceCall(1), {Target: stallerAddress}, balanceCall(3)}
+	results, err := client.Aggregate3(context.Background(), calls, PartialResults())
+	var incompleteErr *IncompleteError
+	if !errors.As(err, &incompleteErr) {
+		t.Fatalf("got error %v, want an IncompleteError", err)
+	}
+	if !reflect.DeepEqual(incompleteErr.Missing, []int{1}) {
+		t.Errorf("got missing calls %v, want [1]", incompleteErr.Missing)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error %v is not context.DeadlineExceeded", err)
+	}
+	checkBalance(t, results[0], 1)
+	checkBalance(t, results[2], 3)
+
+	if _, err := client.Aggregate3(context.Background(), calls); err == nil || incomplete(err) != nil {
+		t.Errorf("got error %v without PartialResults, want a plain error", err)
+	}
+}
+
+// checkBalances checks that results are the balances of the fake token
+
+func TestAggregate3BisectsOutOfGas(t *testing.T) {
+	client, caller := newFakeClient()
+	calls := []Call3{balanceCall(1), balanceCall(2), {Target: guzzlerAddress}, balanceCall(4)}
+	_, err := client.Aggregate3(context.Background(), calls)
+	var outOfGasErr *OutOfGasError
+	if !errors.As(err, &outOfGasErr) {
+		t.Fatalf("got error %v, want an OutOfGasError", err)
+	}
+	if outOfGasErr.Call != 2 {
+		t.Errorf("got call %d out of gas, want 2", outOfGasErr.Call)
+	}
+	if !errors.Is(err, ErrOutOfGas) {
+		t.Errorf("error %v is not ErrOutOfGas", err)
+	}
+	// The whole batch, its halves, then the calls of the half that ran out
+	if got, want := caller.Executed(), []int{4, 2, 2, 1, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("executed multicalls of %v calls, want %v", got, want)
+	}
+}
@@ -0,0 +1,212 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Client executes batches of calls through a Multicall3 contract
+type Client struct {
+	caller  ethereum.ContractCaller
+	address common.Address
+	chainID *big.Int
+	// expectedChainID is the chain given with WithChainID, or zero
+	expectedChainID uint64
+
+	// addressSet records WithAddress, whose address takes precedence over
+	// per-chain addresses
+	addressSet     bool
+	chainAddresses map[uint64]common.Address
+	version        Version
+	deployless     bool
+	overrider      OverrideCaller
+	injectCode     bool
+	ccip           *ccipReader
+	backends       []*nodeBackend
+	rpc            *rpc.Client
+	transactor     bind.ContractTransactor
+	nonces         *NonceManager
+	feePolicy      *FeePolicy
+	dryRun         bool
+	cache          *ResultCache
+	maxRPCBatch    int
+
+	maxCalls        int
+	maxCalldataSize int
+	gasCap          uint64
+	gasCapSet       bool
+	estimator       ethereum.GasEstimator
+	adaptive        *adaptiveLimit
+	concurrency     int
+	chunkTimeout    time.Duration
+	budgetDeadline  bool
+	retry           *RetryPolicy
+	pooled          bool
+}
+
+// NewClient returns a Client that sends its calls through caller, which is
+// typically an *ethclient.Client.
+func NewClient(caller ethereum.ContractCaller, opts ...Option) (*Client, error) {
+	if caller == nil {
+		return nil, errors.New("multicall: nil contract caller")
+	}
+	c := &Client{
+		caller:      caller,
+		address:     Address,
+		version:     Version3,
+		concurrency: 1,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxCalls < 0 {
+		return nil, fmt.Errorf("multicall: negative max calls %d", c.maxCalls)
+	}
+	if c.maxCalldataSize < 0 {
+		return nil, fmt.Errorf("multicall: negative max calldata size %d", c.maxCalldataSize)
+	}
+	if c.maxRPCBatch < 0 {
+		return nil, fmt.Errorf("multicall: negative max RPC batch size %d", c.maxRPCBatch)
+	}
+	if c.version < Version1 || c.version > Version3 {
+		return nil, fmt.Errorf("multicall: invalid version %d", int(c.version))
+	}
+	if c.concurrency < 1 {
+		return nil, fmt.Errorf("multicall: invalid concurrency %d", c.concurrency)
+	}
+	if c.adaptive != nil && (c.adaptive.min < 1 || c.adaptive.min > c.adaptive.max) {
+		return nil, fmt.Errorf("multicall: invalid adaptive chunk size range [%d, %d]", c.adaptive.min, c.adaptive.max)
+	}
+	if c.chunkTimeout < 0 {
+		return nil, fmt.Errorf("multicall: negative chunk timeout %s", c.chunkTimeout)
+	}
+	if c.retry != nil && c.retry.MaxAttempts < 1 {
+		return nil, fmt.Errorf("multicall: invalid retry max attempts %d", c.retry.MaxAttempts)
+	}
+	if p := c.feePolicy; p != nil && (p.Blocks < 1 || p.Percentile < 0 || p.Percentile > 100 || p.BaseFeeMultiplier < 1) {
+		return nil, fmt.Errorf("multicall: invalid fee policy: %d blocks, percentile %g, base fee multiplier %g", p.Blocks, p.Percentile, p.BaseFeeMultiplier)
+	}
+	if c.injectCode && c.overrider == nil {
+		return nil, errors.New("multicall: nil override caller")
+	}
+	if c.injectCode && c.deployless {
+		return nil, errors.New("multicall: code override and deployless mode are mutually exclusive")
+	}
+	if c.injectCode && c.gasCap > 0 {
+		return nil, errors.New("multicall: gas cap cannot be used with code override, gas estimates do not apply overrides")
+	}
+	if c.gasCap > 0 {
+		estimator, ok := caller.(ethereum.GasEstimator)
+		if !ok {
+			return nil, errors.New("multicall: gas cap requires a contract caller that can estimate gas")
+		}
+		c.estimator = estimator
+	}
+	return c, nil
+}
+
+// Address returns the Multicall3 address the client sends its calls to
+func (c *Client) Address() common.Address {
+	return c.address
+}
+
+// call packs method with args, executes it against the Multicall3 contract and
+// returns the raw output.
+func (c *Client) call(ctx context.Context, opts *callOptions, method string, args ...interface{}) ([]byte, error) {
+	return c.callValue(ctx, opts, nil, method, args...)
+}
+
+// callValue is like call, but also sends value wei with the call
+func (c *Client) callValue(ctx context.Context, opts *callOptions, value *big.Int, method string, args ...interface{}) ([]byte, error) {
+	data, err := ABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("multicall: pack %s: %w", method, err)
+	}
+	return c.send(ctx, opts, value, method, data)
+}
+
+// callEncoded is like callValue, but encodes the calldata with encode, which
+// appends it to the buffer it is given. With pooled buffers enabled, the
+// buffer is reused once the call has returned.
+func (c *Client) callEncoded(ctx context.Context, opts *callOptions, value *big.Int, method string, encode func([]byte) []byte) ([]byte, error) {
+	if !c.pooled {
+		return c.send(ctx, opts, value, method, encode(nil))
+	}
+	buf := getBuffer()
+	data := encode(*buf)
+	output, err := c.send(ctx, opts, value, method, data)
+	putBuffer(buf, data)
+	return output, err
+}
+
+// send executes method, encoded as data, against the Multicall3 contract
+func (c *Client) send(ctx context.Context, opts *callOptions, value *big.Int, method string, data []byte) ([]byte, error) {
+	if err := c.checkVersion(method); err != nil {
+		return nil, err
+	}
+	msg := ethereum.CallMsg{
+		To:        &c.address,
+		Data:      data,
+		Value:     value,
+		Gas:       opts.gas,
+		GasPrice:  opts.gasPrice,
+		GasFeeCap: opts.gasFeeCap,
+		GasTipCap: opts.gasTipCap,
+	}
+	if opts.from != nil {
+		if c.deployless {
+			return nil, errors.New("multicall: From cannot be used in deployless mode")
+		}
+		// The multicall runs at the sender, see From
+		msg.From, msg.To = *opts.from, opts.from
+	}
+	if c.deployless {
+		msg.To, msg.Data = nil, deploylessData(data)
+	}
+	if c.estimator != nil {
+		if len(opts.overrides) > 0 || opts.blockOverrides != nil || opts.from != nil {
+			return nil, errors.New("multicall: gas cap cannot be used with overrides, gas estimates do not apply them")
+		}
+		if err := c.checkGas(ctx, method, msg); err != nil {
+			return nil, err
+		}
+	}
+	output, err := c.callContract(ctx, msg, opts)
+	if err != nil {
+		return nil, fmt.Errorf("multicall: execute %s: %w", method, classify(err))
+	}
+	// Every multicall method returns data, calls to an account without code
+	// succeed with none
+	if len(output) == 0 {
+		return nil, fmt.Errorf("%w: no contract at %s", ErrMulticallNotDeployed, c.address)
+	}
+	return output, nil
+}
+
+// errGasCapExceeded is returned when a multicall is estimated to need more
+// gas than the client's gas cap, signalling that it should be split
+var errGasCapExceeded = withKind(ErrBatchTooLarge, errors.New("multicall: estimated gas exceeds gas cap"))
+
+// checkGas estimates the gas needed by msg and fails with errGasCapExceeded if
+// it exceeds the client's gas cap.
+func (c *Client) checkGas(ctx context.Context, method string, msg ethereum.CallMsg) error {
+	gas, err := c.estimator.EstimateGas(ctx, msg)
+	if err != nil {
+		if isGasLimitError(err) {
+			return fmt.Errorf("%w: %v", errGasCapExceeded, err)
+		}
+		return fmt.Errorf("multicall: estimate gas for %s: %w", method, err)
+	}
+	if gas > c.gasCap {
+		return fmt.Errorf("%w: %s needs %d gas, cap is %d", errGasCapExceeded, method, gas, c.gasCap)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package multicall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/john-na4/multicall3/go/internal/helper"
+)
+
+// The methods below emulate the Multicall3 API on contracts of older
+// versions, which share their aggregate and tryAggregate signatures with
+// Multicall3 but lack aggregate3 and aggregate3Value. The original Multicall
+// also lacks tryAggregate, so it can only execute batches in which every call
+// must succeed.
+
+// aggregate3Compat executes calls with tryAggregate, or aggregate on the
+// original Multicall, failing like aggregate3 would when a call that is not
+// allowed to fail does
+func (c *Client) aggregate3Compat(ctx context.Context, calls []Call3, opts []CallOption) ([]Result, error) {
+	plain := make([]Call, len(calls))
+	requireSuccess := true
+	for i, call := range calls {
+		plain[i] = Call{Target: call.Target, CallData: call.CallData}
+		if call.AllowFailure {
+			requireSuccess = false
+		}
+	}
+	results, err := c.tryAggregateCompat(ctx, requireSuccess, plain, opts)
+	incompleteErr := incomplete(err)
+	if err != nil && incompleteErr == nil {
+		return nil, err
+	}
+	var errs []error
+	for i, result := range results {
+		if !result.Success && !calls[i].AllowFailure && !missed(incompleteErr, i) {
+			errs = append(errs, withKind(ErrExecutionReverted, fmt.Errorf("multicall: call %d failed", i)))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, helper.JoinErrors(errs...)
+	}
+	return results, err
+}
+
+// aggregate3ValueCompat executes calls like aggregate3Compat, which is only
+// possible if none of them sends value
+func (c *Client) aggregate3ValueCompat(ctx context.Context, calls []Call3Value, opts []CallOption) ([]Result, error) {
+	call3s := make([]Call3, len(calls))
+	for i, call := range calls {
+		if call.Value != nil && call.Value.Sign() != 0 {
+			return nil, fmt.Errorf("multicall: call %d sends value, which requires Multicall3, contract is %s", i, c.version)
+		}
+		call3s[i] = Call3{Target: call.Target, AllowFailure: call.AllowFailure, CallData: call.CallData}
+	}
+	return c.aggregate3Compat(ctx, call3s, opts)
+}
+
+// tryAggregateCompat executes calls with tryAggregate, or with aggregate on
+// the original Multicall if every call must succeed
+func (c *Client) tryAggregateCompat(ctx context.Context, requireSuccess bool, calls []Call, opts []CallOption) ([]Result, error) {
+	if c.version >= Version2 {
+		return c.TryAggregate(ctx, requireSuccess, calls, opts...)
+	}
+	result, err := c.blockAggregateCompat(ctx, requireSuccess, calls, opts)
+	if err != nil && incomplete(err) == nil {
+		return nil, err
+	}
+	return result.Results, err
+}
+
+// blockAggregateCompat executes calls with aggregate on the original
+// Multicall, which returns the block number like blockAndAggregate. The
+// block hash that Multicall3 returns is always zero anyway.
+func (c *Client) blockAggregateCompat(ctx context.Context, requireSuccess bool, calls []Call, opts []CallOption) (*BlockResult, error) {
+	if !requireSuccess {
+		return nil, fmt.Errorf("multicall: calls allowed to fail require Multicall2 or later, contract is %s", c.version)
+	}
+	result, err := c.Aggregate(ctx, calls, opts...)
+	incompleteErr := incomplete(err)
+	if err != nil && incompleteErr == nil {
+		return nil, err
+	}
+	results := make([]Result, len(result.ReturnData))
+	for i, data := range result.ReturnData {
+		if !missed(incompleteErr, i) {
+			results[i] = Result{Success: true, ReturnData: data}
+		}
+	}
+	return &BlockResult{BlockNumber: result.BlockNumber, Results: results}, err
+}
@@ -0,0 +1,57 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestAggregate3OnMulticall2(t *testing.T) {
+	client, _ := newVersionClient(Version2, WithVersion(Version2))
+	calls := []Call3{balanceCall(1), {Target: reverterAddress, AllowFailure: true}}
+	results, err := client.Aggregate3(context.Background(), calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, results[0], 1)
+	if results[1].Success || results[1].Reason() != "nope" {
+		t.Errorf("got result %+v of a reverting call, want it failed with nope", results[1])
+	}
+
+	// tryAggregate lets every call fail once one may, but Aggregate3 still
+	// fails for the others
+	calls = []Call3{{Target: reverterAddress}, {Target: reverterAddress, AllowFailure: true}}
+	if _, err := client.Aggregate3(context.Background(), calls); !errors.Is(err, ErrExecutionReverted) {
+		t.Errorf("got error %v for a required call, want ErrExecutionReverted", err)
+	}
+}
+
+func TestAggregate3OnMulticall(t *testing.T) {
+	client, _ := newVersionClient(Version1, WithVersion(Version1), WithMaxCalls(1))
+	results, err := client.Aggregate3(context.Background(), []Call3{balanceCall(1), balanceCall(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBalances(t, results, 1, 2)
+
+	if _, err := client.Aggregate3(context.Background(), []Call3{{Target: reverterAddress, AllowFailure: true}}); err == nil {
+		t.Error("call allowed to fail executed on the original Multicall")
+	}
+	if _, err := client.TryAggregate(context.Background(), false, []Call{{Target: tokenAddress}}); err == nil {
+		t.Error("tryAggregate sent to the original Multicall")
+	}
+}
+
+func TestAggregate3ValueOnMulticall2(t *testing.T) {
+	client, _ := newVersionClient(Version2, WithVersion(Version2))
+	call := balanceCall(1)
+	results, err := client.Aggregate3Value(context.Background(), []Call3Value{{Target: call.Target, CallData: call.CallData, Value: new(big.Int)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBalances(t, results, 1)
+	if _, err := client.Aggregate3Value(context.Background(), []Call3Value{{Target: call.Target, Value: big.NewInt(1)}}); err == nil {
+		t.Error("call sending value executed on Multicall2")
+	}
+}
@@ -0,0 +1,191 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// Sizes of the ABI encoding of aggregate3 results
+const (
+	// offset and length of the array of results
+	resultsHeadSize = 2 * 32
+	// offset of a result in the array, success flag, and offset and length
+	// of its return data
+	resultHeadSize = 4 * 32
+)
+
+// CostReport is what executing calls would cost, as multicalls split by the
+// client's limits, see Client.CostReport
+type CostReport struct {
+	// Gas is the gas estimated for the multicalls, summed over all of them
+	Gas uint64
+	// CalldataSize is the size in bytes of the calldata of the multicalls
+	CalldataSize int
+	// ReturnDataSize is the size in bytes the return data of the multicalls
+	// is expected to have. Calls whose return data is not known in advance
+	// are counted as returning a single word, and dynamic values, such as
+	// strings, as empty.
+	ReturnDataSize int
+	// Chunks is the number of multicalls the calls are split into, see
+	// WithMaxCalls and WithMaxCalldataSize
+	Chunks int
+	// Requests is the number of JSON-RPC requests of each method executing
+	// the calls takes
+	Requests map[string]int
+}
+
+// ComputeUnits returns the compute units the requests of the report cost with
+// a provider that charges units for each JSON-RPC method, as in the
+// ComputeUnits of a provider.Endpoint. Methods not listed cost one unit.
+func (r *CostReport) ComputeUnits(units map[string]float64) float64 {
+	var total float64
+	for method, n := range r.Requests {
+		cost, ok := units[method]
+		if !ok {
+			cost = 1
+		}
+		total += cost * float64(n)
+	}
+	return total
+}
+
+// CostReport reports what executing calls with Aggregate3 would cost, to
+// budget a batch against gas caps, response size limits and provider quotas
+// before executing it: the gas of each multicall the client would split the
+// calls into, estimated with eth_estimateGas on the latest block, the size of
+// their calldata and return data, and the requests they take. The estimate
+// fails if a call that may not fail would revert. The return data of each
+// call is counted as a single word, the size of most getters; use
+// Batch.CostReport for calls whose outputs are known. The gas options of
+// opts apply to the estimates; the other call options are not supported. Gas
+// is estimated like EstimateGas does, through the transactor set with
+// WithTransactor or the contract caller of the client, and cannot be with
+// WithCodeOverride.
+func (c *Client) CostReport(ctx context.Context, calls []Call3, opts ...CallOption) (*CostReport, error) {
+	returnSizes := make([]int, len(calls))
+	for i := range returnSizes {
+		returnSizes[i] = 32
+	}
+	return c.costReport(ctx, calls, returnSizes, opts)
+}
+
+// CostReport reports what executing the batch would cost, as
+// Client.CostReport does, with the return data of each call sized after the
+// outputs of its method. Calls are not deduplicated.
+func (b *Batch) CostReport(ctx context.Context, opts ...CallOption) (*CostReport, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	calls := make([]Call3, len(b.calls))
+	returnSizes := make([]int, len(b.calls))
+	for i, call := range b.calls {
+		calls[i] = Call3{Target: call.target, AllowFailure: call.allowFailure, CallData: call.callData}
+		returnSizes[i] = 32
+		if method, ok := call.abi.Methods[call.method]; ok {
+			returnSizes[i] = outputSize(method.Outputs)
+		}
+	}
+	return b.client.costReport(ctx, calls, returnSizes, opts)
+}
+
+// costReport reports the cost of calls whose return data are expected to be
+// of the given sizes
+func (c *Client) costReport(ctx context.Context, calls []Call3, returnSizes []int, opts []CallOption) (*CostReport, error) {
+	o := newCallOptions(opts)
+	if o.err != nil {
+		return nil, o.err
+	}
+	if err := c.checkVersion("aggregate3"); err != nil {
+		return nil, err
+	}
+	if len(o.overrides) > 0 || o.blockOverrides != nil || o.from != nil || o.block != nil || o.blockHash != nil {
+		return nil, errors.New("multicall: cost reports only take gas options")
+	}
+	report := &CostReport{Requests: make(map[string]int)}
+	start := 0
+	for _, n := range c.chunkLens(calls) {
+		data := AppendAggregate3(nil, calls[start:start+n])
+		gas, err := c.estimateData(ctx, o, "aggregate3", data)
+		if err != nil {
+			return nil, err
+		}
+		report.Gas += gas
+		report.CalldataSize += len(data)
+		if c.deployless {
+			report.CalldataSize += len(deploylessPrefix)
+		}
+		report.ReturnDataSize += resultsHeadSize
+		for _, size := range returnSizes[start : start+n] {
+			report.ReturnDataSize += resultHeadSize + size
+		}
+		report.Chunks++
+		start += n
+	}
+	method := "eth_call"
+	if backend := c.nodeBackend(); backend != nil {
+		method = backend.method
+	}
+	report.Requests[method] = report.Chunks
+	if c.gasCap > 0 {
+		// Multicalls are estimated before they are sent, see WithGasCap
+		report.Requests["eth_estimateGas"] = report.Chunks
+	}
+	return report, nil
+}
+
+// outputSize returns the size of the ABI encoding of outputs, counting
+// dynamic values as empty
+func outputSize(outputs abi.Arguments) int {
+	size := 0
+	for _, output := range outputs {
+		size += typeSize(output.Type)
+	}
+	return size
+}
+
+// typeSize returns the size of the ABI encoding of a value of type t,
+// counting dynamic values as empty: an offset, followed by a zero length for
+// strings, bytes and slices
+func typeSize(t abi.Type) int {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy:
+		return 2 * 32
+	case abi.ArrayTy:
+		size := t.Size * typeSize(*t.Elem)
+		if isDynamicType(*t.Elem) {
+			size += 32
+		}
+		return size
+	case abi.TupleTy:
+		size := 0
+		dynamic := false
+		for _, elem := range t.TupleElems {
+			size += typeSize(*elem)
+			dynamic = dynamic || isDynamicType(*elem)
+		}
+		if dynamic {
+			size += 32
+		}
+		return size
+	}
+	return 32
+}
+
+// isDynamicType reports whether values of type t are encoded after an offset
+func isDynamicType(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy:
+		return true
+	case abi.ArrayTy:
+		return isDynamicType(*t.Elem)
+	case abi.TupleTy:
+		for _, elem := range t.TupleElems {
+			if isDynamicType(*elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package multicall
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+// convertValues stores the unpacked outputs of a method in dst, which must be a
+// non-nil pointer. Methods with a single output are converted to the pointed-to
+// type, methods with several outputs are copied into the fields of a struct.
+func convertValues(outputs abi.Arguments, values []interface{}, dst interface{}) error {
+	target := reflect.ValueOf(dst).Elem()
+	if len(values) == 1 {
+		return convertValue(reflect.ValueOf(values[0]), target)
+	}
+	if target.Kind() != reflect.Struct || len(outputs) != len(values) || !allNamed(outputs) {
+		return outputs.Copy(dst, values)
+	}
+	// Match outputs to fields by name, the same way abi.Arguments.Copy does
+	for i, output := range outputs {
+		name := abi.ToCamelCase(output.Name)
+		field := target.FieldByName(name)
+		if !field.IsValid() || !field.CanSet() {
+			return fmt.Errorf("no settable field %s in %s for output %d", name, target.Type(), i)
+		}
+		if err := convertValue(reflect.ValueOf(values[i]), field); err != nil {
+			return fmt.Errorf("output %s: %w", output.Name, err)
+		}
+	}
+	return nil
+}
+
+func allNamed(args abi.Arguments) bool {
+	for _, arg := range args {
+		if arg.Name == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// convertValue sets dst to src, converting between integer representations
+// when no precision is lost and between structurally identical types such as
+// tuples and user-defined structs.
+func convertValue(src, dst reflect.Value) (err error) {
+	if !src.IsValid() {
+		return fmt.Errorf("cannot convert nil to %s", dst.Type())
+	}
+	srcType, dstType := src.Type(), dst.Type()
+	switch {
+	case srcType.AssignableTo(dstType):
+		dst.Set(src)
+		return nil
+	case srcType == bigIntType && isInteger(dstType.Kind()):
+		return setBigInt(src.Interface().(*big.Int), dst)
+	case isInteger(srcType.Kind()) && dstType == bigIntType:
+		dst.Set(reflect.ValueOf(integerToBig(src)))
+		return nil
+	case isInteger(srcType.Kind()) && isInteger(dstType.Kind()):
+		return setBigInt(integerToBig(src), dst)
+	case srcType.ConvertibleTo(dstType) && srcType.Kind() == dstType.Kind():
+		dst.Set(src.Convert(dstType))
+		return nil
+	}
+	// ConvertType handles structurally identical types, such as tuples decoded
+	// into user-defined structs, and panics on anything else
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cannot convert %s to %s: %v", srcType, dstType, r)
+		}
+	}()
+	converted := reflect.ValueOf(abi.ConvertType(src.Interface(), dst.Addr().Interface()))
+	if converted.Pointer() != dst.Addr().Pointer() {
+		dst.Set(converted.Elem())
+	}
+	return nil
+}
+
+func isInteger(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func integerToBig(v reflect.Value) *big.Int {
+	if v.CanInt() {
+		return big.NewInt(v.Int())
+	}
+	return new(big.Int).SetUint64(v.Uint())
+}
+
+// setBigInt stores n in the integer dst, failing if it does not fit
+func setBigInt(n *big.Int, dst reflect.Value) error {
+	if dst.CanInt() {
+		if !n.IsInt64() || dst.OverflowInt(n.Int64()) {
+			return fmt.Errorf("value %s overflows %s", n, dst.Type())
+		}
+		dst.SetInt(n.Int64())
+		return nil
+	}
+	if !n.IsUint64() || dst.OverflowUint(n.Uint64()) {
+		return fmt.Errorf("value %s overflows %s", n, dst.Type())
+	}
+	dst.SetUint(n.Uint64())
+	return nil
+}
@@ -0,0 +1,87 @@
+package multicall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// callKey identifies calls that are guaranteed to return the same result when
+// executed in the same multicall
+type callKey struct {
+	target   common.Address
+	callData string
+}
+
+// dedupe removes repeated (target, calldata) pairs from calls. It returns the
+// unique calls along with, for each original call, the index of the unique
+// call whose result it shares. A merged call only allows failure if every one
+// of its duplicates does.
+func dedupe(calls []Call3) ([]Call3, []int) {
+	unique := make([]Call3, 0, len(calls))
+	index := make([]int, len(calls))
+	seen := make(map[callKey]int, len(calls))
+	for i, call := range calls {
+		key := callKey{target: call.Target, callData: string(call.CallData)}
+		if j, ok := seen[key]; ok {
+			unique[j].AllowFailure = unique[j].AllowFailure && call.AllowFailure
+			index[i] = j
+			continue
+		}
+		seen[key] = len(unique)
+		index[i] = len(unique)
+		unique = append(unique, call)
+	}
+	return unique, index
+}
+
+// aggregate3Deduped executes calls like Aggregate3, but sends each distinct
+// call only once and fans its result out to all of its duplicates.
+func (c *Client) aggregate3Deduped(ctx context.Context, calls []Call3, opts ...CallOption) ([]Result, error) {
+	unique, index := dedupe(calls)
+	if len(unique) == len(calls) {
+		return c.Aggregate3(ctx, calls, opts...)
+	}
+	results, err := c.Aggregate3(ctx, unique, opts...)
+	incompleteErr := incomplete(err)
+	if err != nil && incompleteErr == nil {
+		remapOutOfGas(err, index)
+		return nil, err
+	}
+	if len(results) != len(unique) {
+		return nil, fmt.Errorf("multicall: got %d results for %d calls", len(results), len(unique))
+	}
+	fanned := make([]Result, len(calls))
+	var missing []int
+	for i, j := range index {
+		fanned[i] = results[j]
+		if missed(incompleteErr, j) {
+			missing = append(missing, i)
+		}
+	}
+	if incompleteErr != nil {
+		return fanned, &IncompleteError{Missing: missing, Err: incompleteErr.Err}
+	}
+	return fanned, nil
+}
+
+// remapOutOfGas replaces the index of the deduplicated call of every
+// OutOfGasError in err by the index of the first call it stands for
+func remapOutOfGas(err error, index []int) {
+	switch err := err.(type) {
+	case *OutOfGasError:
+		for i, j := range index {
+			if j == err.Call {
+				err.Call = i
+				break
+			}
+		}
+	case interface{ Unwrap() []error }:
+		for _, err := range err.Unwrap() {
+			remapOutOfGas(err, index)
+		}
+	case interface{ Unwrap() error }:
+		remapOutOfGas(err.Unwrap(), index)
+	}
+}
@@ -0,0 +1,89 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDedupe(t *testing.T) {
+	first, second := balanceCall(1), balanceCall(2)
+	allowed := first
+	allowed.AllowFailure = true
+	unique, index := dedupe([]Call3{allowed, second, first, second})
+	if len(unique) != 2 {
+		t.Fatalf("got %d unique calls, want 2", len(unique))
+	}
+	if want := []int{0, 1, 0, 1}; !reflect.DeepEqual(index, want) {
+		t.Errorf("got index %v, want %v", index, want)
+	}
+	// A duplicate not allowed to fail makes the merged call required
+	if unique[0].AllowFailure {
+		t.Error("merged call allows failure, want it required")
+	}
+}
+
+func TestBatchFansOutDuplicates(t *testing.T) {
+	client, caller := newFakeClient()
+	batch := client.NewBatch()
+	for _, b := range []byte{1, 2, 1, 1, 3} {
+		batch.Add(tokenAddress, tokenABI, "balanceOf", owner(b))
+	}
+	values, err := batch.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := caller.Executed(), []int{3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("executed multicalls of %v calls, want %v", got, want)
+	}
+	for i, want := range []int64{1, 2, 1, 1, 3} {
+		if got := values[i][0].(*big.Int); got.Int64() != want {
+			t.Errorf("call %d: got balance %s, want %d", i, got, want)
+		}
+	}
+}
+
+func TestBatchPartialResultsOfDuplicates(t *testing.T) {
+	client, _ := newFakeClient(WithMaxCalls(1), WithChunkTimeout(20*time.Millisecond))
+	batch := client.NewBatch().
+		Add(tokenAddress, tokenABI, "balanceOf", owner(1)).
+		Add(stallerAddress, tokenABI, "balanceOf", owner(2)).
+		Add(tokenAddress, tokenABI, "balanceOf", owner(3)).
+		Add(stallerAddress, tokenABI, "balanceOf", owner(2))
+	results, err := batch.ExecuteResults(context.Background(), PartialResults())
+	var incompleteErr *IncompleteError
+	if !errors.As(err, &incompleteErr) {
+		t.Fatalf("got error %v, want an IncompleteError", err)
+	}
+	// Both calls standing for the stalled one are missing
+	if want := []int{1, 3}; !reflect.DeepEqual(incompleteErr.Missing, want) {
+		t.Errorf("got missing calls %v, want %v", incompleteErr.Missing, want)
+	}
+	for i, want := range map[int]int64{0: 1, 2: 3} {
+		if results[i].Err != nil || results[i].Values[0].(*big.Int).Int64() != want {
+			t.Errorf("call %d: got %v, %v, want balance %d", i, results[i].Values, results[i].Err, want)
+		}
+	}
+	if results[1].Values != nil || results[3].Values != nil {
+		t.Error("missing calls have values")
+	}
+}
+
+func TestBatchOutOfGasIndexOfDuplicates(t *testing.T) {
+	client, _ := newFakeClient()
+	batch := client.NewBatch().
+		Add(tokenAddress, tokenABI, "balanceOf", owner(1)).
+		Add(tokenAddress, tokenABI, "balanceOf", owner(1)).
+		Add(guzzlerAddress, tokenABI, "balanceOf", owner(2))
+	_, err := batch.Execute(context.Background())
+	var outOfGasErr *OutOfGasError
+	if !errors.As(err, &outOfGasErr) {
+		t.Fatalf("got error %v, want an OutOfGasError", err)
+	}
+	if outOfGasErr.Call != 2 {
+		t.Errorf("got call %d out of gas, want 2", outOfGasErr.Call)
+	}
+}
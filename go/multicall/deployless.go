@@ -0,0 +1,57 @@
+package multicall
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/john-na4/multicall3/go/bindings"
+)
+
+// creationCode is the Multicall3 creation bytecode
+var creationCode = common.FromHex(bindings.Multicall3MetaData.Bin)
+
+// deploylessPrefix is the init code that a deployless multicall executes as
+// a contract creation, followed by creationCode. It deploys Multicall3 with
+// CREATE and calls it with the rest of the code as calldata, forwarding the
+// call value, then returns or reverts with what the call returned. With S
+// the size of the creation code and E = 70 + S the offset of the calldata:
+//
+//	PUSH2 S PUSH2 70 PUSH1 0 CODECOPY         copy the creation code to memory
+//	PUSH2 S PUSH1 0 PUSH1 0 CREATE            deploy it                [addr]
+//	DUP1 ISZERO PUSH2 fail JUMPI
+//	PUSH2 E CODESIZE SUB                      calldata size            [addr n]
+//	DUP1 PUSH2 E PUSH1 0 CODECOPY             copy the calldata to memory
+//	PUSH1 0 PUSH1 0 DUP3 PUSH1 0 CALLVALUE DUP7 GAS CALL               [addr n ok]
+//	RETURNDATASIZE PUSH1 0 PUSH1 0 RETURNDATACOPY
+//	PUSH2 success JUMPI RETURNDATASIZE PUSH1 0 REVERT
+//	success: JUMPDEST RETURNDATASIZE PUSH1 0 RETURN
+//	fail: JUMPDEST PUSH1 0 DUP1 REVERT
+var deploylessPrefix = func() []byte {
+	const prefixSize = 70
+	size := len(creationCode)
+	code := common.FromHex(fmt.Sprintf(
+		"61%04x610046600039"+
+			"61%04x60006000f0"+
+			"801561004157"+
+			"61%04x3803"+
+			"8061%04x600039"+
+			"6000600082600034865af1"+
+			"3d600060003e"+
+			"61003c573d6000fd"+
+			"5b3d6000f3"+
+			"5b600080fd",
+		size, size, prefixSize+size, prefixSize+size))
+	if len(code) != prefixSize {
+		panic("multicall: invalid deployless prefix")
+	}
+	return append(code, creationCode...)
+}()
+
+// deploylessData wraps the calldata of a multicall so that it can be sent as
+// a contract creation, executing against a Multicall3 deployed on the fly
+func deploylessData(data []byte) []byte {
+	wrapped := make([]byte, 0, len(deploylessPrefix)+len(data))
+	wrapped = append(wrapped, deploylessPrefix...)
+	return append(wrapped, data...)
+}
@@ -0,0 +1,88 @@
+package multicall
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/john-na4/multicall3/go/bindings"
+)
+
+// newSimulatedChain returns a simulated chain with an account holding 1 ETH
+// and a Multicall3 deployed at an address other than the canonical one, to
+// serve as the target of calls
+func newSimulatedChain(t *testing.T) (*backends.SimulatedBackend, common.Address, common.Address) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rich := common.Address{0x77}
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{
+		auth.From: {Balance: new(big.Int).Lsh(big.NewInt(1), 100)},
+		rich:      {Balance: big.NewInt(1e18)},
+	}, 30_000_000)
+	t.Cleanup(func() { sim.Close() })
+	target, _, _, err := bindings.DeployMulticall3(auth, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	return sim, rich, target
+}
+
+func TestDeployless(t *testing.T) {
+	sim, rich, target := newSimulatedChain(t)
+	if code, err := sim.CodeAt(context.Background(), Address, nil); err != nil || len(code) != 0 {
+		t.Fatalf("got code %x, %v at the canonical address, want none", code, err)
+	}
+	client, err := NewClient(sim, WithDeployless())
+	if err != nil {
+		t.Fatal(err)
+	}
+	balanceOf, err := ABI.Pack("getEthBalance", rich)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := client.Aggregate3(context.Background(), []Call3{
+		{Target: target, CallData: balanceOf},
+		// Multicall3 has no fallback function
+		{Target: target, AllowFailure: true, CallData: []byte{0xde, 0xad, 0xbe, 0xef}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := new(big.Int).SetBytes(results[0].ReturnData); !results[0].Success || got.Int64() != 1e18 {
+		t.Errorf("got balance %s, want 1e18", got)
+	}
+	if results[1].Success {
+		t.Error("call of an unknown selector succeeded")
+	}
+
+	// A whole multicall that reverts reverts the creation
+	if _, err := client.Aggregate3(context.Background(), []Call3{{Target: target, CallData: []byte{0xde, 0xad, 0xbe, 0xef}}}); err == nil {
+		t.Error("multicall of a failing call succeeded")
+	}
+}
+
+func TestDeploylessPrefix(t *testing.T) {
+	if !bytes.HasSuffix(deploylessPrefix, creationCode) {
+		t.Fatal("deployless prefix does not end with the creation code")
+	}
+	data := []byte{1, 2, 3}
+	wrapped := deploylessData(data)
+	if !bytes.Equal(wrapped, append(append([]byte(nil), deploylessPrefix...), data...)) {
+		t.Errorf("got %d bytes of deployless data, want the prefix followed by the calldata", len(wrapped))
+	}
+}
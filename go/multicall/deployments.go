@@ -0,0 +1,294 @@
+// Code generated by gendeployments from deployments.json. DO NOT EDIT.
+
+package multicall
+
+import "github.com/ethereum/go-ethereum/common"
+
+var deployments = []Deployment{
+	{Name: "Mainnet", ChainID: 1, Address: Address, URL: "https://etherscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Kovan", ChainID: 42, Address: Address, URL: "https://kovan.etherscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Rinkeby", ChainID: 4, Address: Address, URL: "https://rinkeby.etherscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Görli", ChainID: 5, Address: Address, URL: "https://goerli.etherscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Ropsten", ChainID: 3, Address: Address, URL: "https://ropsten.etherscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Sepolia", ChainID: 11155111, Address: Address, URL: "https://sepolia.etherscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Holesky", ChainID: 17000, Address: Address, URL: "https://holesky.etherscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Story", ChainID: 1514, Address: Address, URL: "https://www.storyscan.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Story Aeneid Testnet", ChainID: 1315, Address: Address, URL: "https://aeneid.storyscan.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Xterio Chain", ChainID: 112358, Address: Address, URL: "https://xterscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Xterio Testnet", ChainID: 1637450, Address: Address, URL: "https://testnet.xterscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Optimism", ChainID: 10, Address: Address, URL: "https://optimistic.etherscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Optimism Kovan", ChainID: 69, Address: Address, URL: "https://kovan-optimistic.etherscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Optimism Görli", ChainID: 420, Address: Address, URL: "https://blockscout.com/optimism/goerli/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Optimism Sepolia", ChainID: 11155420, Address: Address, URL: "https://optimism-sepolia.blockscout.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Arbitrum", ChainID: 42161, Address: Address, URL: "https://arbiscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Arbitrum Nova", ChainID: 42170, Address: Address, URL: "https://nova.arbiscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Arbitrum Görli", ChainID: 421613, Address: Address, URL: "https://goerli-rollup-explorer.arbitrum.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Arbitrum Sepolia", ChainID: 421614, Address: Address, URL: "https://sepolia-explorer.arbitrum.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Arbitrum Rinkeby", ChainID: 421611, Address: Address, URL: "https://testnet.arbiscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Stylus Testnet", ChainID: 23011913, Address: Address, URL: "https://stylus-testnet-explorer.arbitrum.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Polygon", ChainID: 137, Address: Address, URL: "https://polygonscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Mumbai", ChainID: 80001, Address: Address, URL: "https://mumbai.polygonscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Amoy", ChainID: 80002, Address: Address, URL: "https://amoy.polygonscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Polygon zkEVM", ChainID: 1101, Address: Address, URL: "https://zkevm.polygonscan.com/address/0xca11bde05977b3631167028862be2a173976ca11#code"},
+	{Name: "Polygon zkEVM Testnet", ChainID: 1442, Address: Address, URL: "https://testnet-zkevm.polygonscan.com/address/0xca11bde05977b3631167028862be2a173976ca11#code"},
+	{Name: "Cardona zkEVM Testnet", ChainID: 2442, Address: Address, URL: "https://cardona-zkevm.polygonscan.com/address/0xca11bde05977b3631167028862be2a173976ca11#code"},
+	{Name: "Gnosis Chain (xDai)", ChainID: 100, Address: Address, URL: "https://blockscout.com/xdai/mainnet/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Chiado (Gnosis Chain Testnet)", ChainID: 10200, Address: Address, URL: "https://blockscout.chiadochain.net/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Avalanche", ChainID: 43114, Address: Address, URL: "https://snowtrace.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Avalanche Fuji", ChainID: 43113, Address: Address, URL: "https://testnet.snowtrace.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Fantom Testnet", ChainID: 4002, Address: Address, URL: "https://testnet.ftmscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Fantom Opera", ChainID: 250, Address: Address, URL: "https://ftmscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Fantom Sonic", ChainID: 64240, Address: Address, URL: "https://public-sonic.fantom.network/address/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "Sonic Network", ChainID: 146, Address: Address, URL: "https://sonicscan.org/address/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "BNB Smart Chain", ChainID: 56, Address: Address, URL: "https://bscscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "BNB Smart Chain Testnet", ChainID: 97, Address: Address, URL: "https://testnet.bscscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "opBNB Testnet", ChainID: 5611, Address: Address, URL: "https://opbnbscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?p=1&tab=Contract"},
+	{Name: "opBNB Mainnet", ChainID: 204, Address: Address, URL: "https://mainnet.opbnbscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?p=1&tab=Contract"},
+	{Name: "Moonbeam", ChainID: 1284, Address: Address, URL: "https://moonscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Moonriver", ChainID: 1285, Address: Address, URL: "https://moonriver.moonscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Moonbase Alpha Testnet", ChainID: 1287, Address: Address, URL: "https://moonbase.moonscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Palm", ChainID: 11297108109, Address: Address, URL: "https://palm.chainlens.com/contracts/0xca11bde05977b3631167028862be2a173976ca11/sources"},
+	{Name: "Palm Testnet", ChainID: 11297108099, Address: Address, URL: "https://testnet.palm.chainlens.com/contracts/0xca11bde05977b3631167028862be2a173976ca11/sources"},
+	{Name: "Harmony", ChainID: 1666600000, Address: Address, URL: "https://explorer.harmony.one/address/0xcA11bde05977b3631167028862bE2a173976CA11?activeTab=7"},
+	{Name: "Cronos", ChainID: 25, Address: Address, URL: "https://cronoscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Cronos Testnet", ChainID: 338, Address: Address, URL: "https://cronos.org/explorer/testnet3/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Reya Cronos", ChainID: 89346162, Address: Address, URL: "https://reya-cronos.blockscout.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Reya Network", ChainID: 1729, Address: Address, URL: "https://explorer.reya.network/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Fuse", ChainID: 122, Address: Address, URL: "https://explorer.fuse.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Flare Mainnet", ChainID: 14, Address: Address, URL: "https://flare-explorer.flare.network/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Songbird Canary Network", ChainID: 19, Address: Address, URL: "https://songbird-explorer.flare.network/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Coston Testnet", ChainID: 16, Address: Address, URL: "https://coston-explorer.flare.network/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Coston2 Testnet", ChainID: 114, Address: Address, URL: "https://coston2-explorer.flare.network/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Boba", ChainID: 288, Address: Address, URL: "https://blockexplorer.boba.network/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Aurora", ChainID: 1313161554, Address: Address, URL: "https://explorer.mainnet.aurora.dev/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Astar", ChainID: 592, Address: Address, URL: "https://blockscout.com/astar/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Astar zKyoto Testnet", ChainID: 6038361, Address: Address, URL: "https://zkyoto.explorer.startale.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Astar zkEVM", ChainID: 3776, Address: Address, URL: "https://astar-zkevm.explorer.startale.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "OKC", ChainID: 66, Address: Address, URL: "https://www.oklink.com/en/okc/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Heco Chain", ChainID: 128, Address: Address, URL: "https://hecoinfo.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Metis Andromeda", ChainID: 1088, Address: Address, URL: "https://andromeda-explorer.metis.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Metis Goerli", ChainID: 599, Address: Address, URL: "https://goerli.explorer.metisdevops.link/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Metis Sepolia", ChainID: 59902, Address: Address, URL: "https://sepolia-explorer.metisdevops.link/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Over Protocol", ChainID: 54176, Address: common.HexToAddress("0x03657CDcDA1523C073b5e09c37dd199E6fBD1b99"), URL: "https://scan.over.network/address/0x03657CDcDA1523C073b5e09c37dd199E6fBD1b99"},
+	{Name: "Over Protocol Dolphin Testnet", ChainID: 541764, Address: common.HexToAddress("0x03657CDcDA1523C073b5e09c37dd199E6fBD1b99"), URL: "https://dolphin-scan.over.network/address/0x03657CDcDA1523C073b5e09c37dd199E6fBD1b99"},
+	{Name: "RSK", ChainID: 30, Address: Address, URL: "https://explorer.rsk.co/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "RSK Testnet", ChainID: 31, Address: Address, URL: "https://explorer.testnet.rsk.co/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Evmos", ChainID: 9001, Address: Address, URL: "https://evm.evmos.org/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Evmos Testnet", ChainID: 9000, Address: Address, URL: "https://evm.evmos.dev/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Thundercore", ChainID: 108, Address: Address, URL: "https://viewblock.io/thundercore/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=code"},
+	{Name: "Thundercore Testnet", ChainID: 18, Address: Address, URL: "https://explorer-testnet.thundercore.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Oasis", ChainID: 42262, Address: Address, URL: "https://explorer.emerald.oasis.dev/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Oasis Sapphire", ChainID: 23294, Address: Address, URL: "https://explorer.sapphire.oasis.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Celo", ChainID: 42220, Address: Address, URL: "https://explorer.celo.org/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Celo Alfajores Testnet", ChainID: 44787, Address: Address, URL: "https://explorer.celo.org/alfajores/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Godwoken", ChainID: 71402, Address: Address, URL: "https://v1.gwscan.com/account/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Godwoken Testnet", ChainID: 71401, Address: Address, URL: "https://gw-explorer.nervosdao.community/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Klaytn", ChainID: 8217, Address: Address, URL: "https://scope.klaytn.com/account/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Klaytn Testnet (Baobab)", ChainID: 1001, Address: Address, URL: "https://baobab.klaytnscope.com/account/0xca11bde05977b3631167028862be2a173976ca11?tabId=contractCode"},
+	{Name: "Milkomeda", ChainID: 2001, Address: Address, URL: "https://explorer-mainnet-cardano-evm.c1.milkomeda.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "KCC", ChainID: 321, Address: Address, URL: "https://explorer.kcc.io/en/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Velas", ChainID: 106, Address: Address, URL: "https://evmexplorer.velas.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Telos", ChainID: 40, Address: Address, URL: "https://www.teloscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#contract"},
+	{Name: "Step Network", ChainID: 1234, Address: Address, URL: "https://stepscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Canto", ChainID: 7700, Address: Address, URL: "https://tuber.build/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Canto Testnet", ChainID: 7701, Address: Address, URL: "https://testnet.tuber.build/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Iotex", ChainID: 4689, Address: Address, URL: "https://iotexscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#transactions"},
+	{Name: "Bitgert", ChainID: 32520, Address: Address, URL: "https://brisescan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Kava", ChainID: 2222, Address: Address, URL: "https://explorer.kava.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Mantle Sepolia Testnet", ChainID: 5003, Address: Address, URL: "https://explorer.sepolia.mantle.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Mantle Testnet", ChainID: 5001, Address: Address, URL: "https://explorer.testnet.mantle.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Mantle", ChainID: 5000, Address: Address, URL: "https://explorer.mantle.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Shardeum Sphinx", ChainID: 8082, Address: Address, URL: "https://explorer.testnet.mantle.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Base Testnet (Goerli)", ChainID: 84531, Address: Address, URL: "https://goerli.basescan.org/address/0xca11bde05977b3631167028862be2a173976ca11#code"},
+	{Name: "Base Testnet (Sepolia)", ChainID: 84532, Address: Address, URL: "https://base-sepolia.blockscout.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Base", ChainID: 8453, Address: Address, URL: "https://basescan.org/address/0xca11bde05977b3631167028862be2a173976ca11#code"},
+	{Name: "Kroma Testnet (Sepolia)", ChainID: 2358, Address: Address, URL: "https://sepolia.kromascan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Kroma", ChainID: 255, Address: Address, URL: "https://kromascan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "DeFiChain EVM Mainnet", ChainID: 1130, Address: Address, URL: "https://meta.defiscan.live/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "DeFiChain EVM Testnet", ChainID: 1131, Address: Address, URL: "https://meta.defiscan.live/address/0xcA11bde05977b3631167028862bE2a173976CA11?network=TestNet"},
+	{Name: "Defi Oracle Meta Mainnet", ChainID: 138, Address: Address, URL: "https://blockscout.defi-oracle.io/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "DFK Chain Test", ChainID: 335, Address: Address, URL: "https://subnets-test.avax.network/defi-kingdoms/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "DFK Chain", ChainID: 53935, Address: Address, URL: "https://subnets.avax.network/defi-kingdoms/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Neon EVM DevNet", ChainID: 245022926, Address: Address, URL: "https://devnet.neonscan.org/address/0xcA11bde05977b3631167028862bE2a173976CA11#contract"},
+	{Name: "Linea Sepolia Testnet", ChainID: 59141, Address: Address, URL: "https://sepolia.lineascan.build/address/0xca11bde05977b3631167028862be2a173976ca11#code"},
+	{Name: "Linea Goerli Testnet", ChainID: 59140, Address: Address, URL: "https://explorer.goerli.linea.build/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Linea Mainnet", ChainID: 59144, Address: Address, URL: "https://lineascan.build/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Hashbit", ChainID: 11119, Address: Address, URL: "https://explorer.hashbit.org/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Syscoin", ChainID: 57, Address: Address, URL: "https://explorer.syscoin.org/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Syscoin Rollux Mainnet", ChainID: 570, Address: Address, URL: "https://explorer.rollux.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Syscoin Tannebaum Testnet", ChainID: 5700, Address: Address, URL: "https://tanenbaum.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Syscoin Tannebaum Rollux", ChainID: 57000, Address: Address, URL: "https://rollux.tanenbaum.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Pulsechain v4 Testnet", ChainID: 943, Address: Address, URL: "https://scan.v4.testnet.pulsechain.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Pulsechain Mainnet", ChainID: 369, Address: Address, URL: "https://scan.pulsechain.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Zora Goerli Testnet", ChainID: 999, Address: Address, URL: "https://testnet.explorer.zora.co/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Zora", ChainID: 7777777, Address: Address, URL: "https://explorer.zora.co/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Zora Sepolia Testnet", ChainID: 999999999, Address: Address, URL: "https://sepolia.explorer.zora.energy/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Darwinia Crab Network", ChainID: 44, Address: Address, URL: "https://crab.subscan.io/account/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "Darwinia Network", ChainID: 46, Address: Address, URL: "https://darwinia.subscan.io/account/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "Chain Verse Mainnet", ChainID: 5555, Address: Address, URL: "https://explorer.chainverse.info/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Scroll Alpha Testnet", ChainID: 534353, Address: Address, URL: "https://blockscout.scroll.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Scroll Sepolia Testnet", ChainID: 534351, Address: Address, URL: "https://sepolia.scrollscan.dev/address/0xca11bde05977b3631167028862be2a173976ca11#code"},
+	{Name: "Scroll", ChainID: 534352, Address: Address, URL: "https://scrollscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Xodex", ChainID: 2415, Address: Address, URL: "https://explorer.xo-dex.com/contracts/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "EOS EVM Testnet", ChainID: 15557, Address: Address, URL: "https://explorer.testnet.evm.eosnetwork.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "EOS EVM", ChainID: 17777, Address: Address, URL: "https://explorer.evm.eosnetwork.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Crossbell", ChainID: 3737, Address: Address, URL: "https://scan.crossbell.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Dogechain", ChainID: 2000, Address: Address, URL: "https://explorer.dogechain.dog/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "MEVerse Chain Testnet", ChainID: 4759, Address: Address, URL: "https://testnet.meversescan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "MEVerse Chain Mainnet", ChainID: 7518, Address: Address, URL: "https://meversescan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "SKALE Calypso Testnet", ChainID: 974399131, Address: Address, URL: "https://giant-half-dual-testnet.explorer.testnet.skalenodes.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "SKALE Europa Testnet", ChainID: 1444673419, Address: Address, URL: "https://juicy-low-small-testnet.explorer.testnet.skalenodes.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "SKALE Nebula Testnet", ChainID: 37084624, Address: Address, URL: "https://lanky-ill-funny-testnet.explorer.testnet.skalenodes.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "SKALE Titan Testnet", ChainID: 1020352220, Address: Address, URL: "https://aware-fake-trim-testnet.explorer.testnet.skalenodes.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "SKALE Calypso Hub", ChainID: 1564830818, Address: Address, URL: "https://honorable-steel-rasalhague.explorer.mainnet.skalenodes.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "SKALE Europa Liquidity Hub", ChainID: 2046399126, Address: Address, URL: "https://elated-tan-skat.explorer.mainnet.skalenodes.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "SKALE Nebula Gaming Hub", ChainID: 1482601649, Address: Address, URL: "https://green-giddy-denebola.explorer.mainnet.skalenodes.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "SKALE Titan AI Hub", ChainID: 1350216234, Address: Address, URL: "https://parallel-stormy-spica.explorer.mainnet.skalenodes.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Ronin Saigon Testnet", ChainID: 2021, Address: Address, URL: "https://saigon-app.roninchain.com/address/ronin:ca11bde05977b3631167028862be2a173976ca11?t=contract"},
+	{Name: "Ronin Mainnet", ChainID: 2020, Address: Address, URL: "https://app.roninchain.com/address/ronin:ca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "Qitmeer Testnet", ChainID: 8131, Address: Address, URL: "https://testnet-qng.qitmeer.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Qitmeer QNG Mainnet", ChainID: 813, Address: Address, URL: "https://qng.meerscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Q Testnet", ChainID: 35443, Address: Address, URL: "https://explorer.qtestnet.org/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Q Devnet", ChainID: 35442, Address: Address, URL: "https://explorer.qdevnet.org/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Q Mainnet", ChainID: 35441, Address: Address, URL: "https://explorer.q.org/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Neon Mainnet", ChainID: 245022934, Address: Address, URL: "https://neonscan.org/address/0xca11bde05977b3631167028862be2a173976ca11#contract"},
+	{Name: "LUKSO Testnet", ChainID: 4201, Address: Address, URL: "https://explorer.execution.testnet.lukso.network/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "LUKSO Mainnet", ChainID: 42, Address: Address, URL: "https://explorer.execution.mainnet.lukso.network/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Edgeware EdgeEVM", ChainID: 2021, Address: Address, URL: "https://edgscan.live/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts"},
+	{Name: "Meter Testnet", ChainID: 83, Address: Address, URL: "https://scan-warringstakes.meter.io/address/0xca11bde05977b3631167028862be2a173976ca11?tab=0&p=1"},
+	{Name: "Meter", ChainID: 82, Address: Address, URL: "https://scan.meter.io/address/0xca11bde05977b3631167028862be2a173976ca11?tab=0&p=1"},
+	{Name: "Sepolia PGN (Public Goods Network) Testnet", ChainID: 58008, Address: Address, URL: "https://explorer.sepolia.publicgoods.network/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "PGN (Public Goods Network)", ChainID: 424, Address: Address, URL: "https://explorer.publicgoods.network/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "ShimmerEVM", ChainID: 148, Address: Address, URL: "https://explorer.evm.shimmer.network/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Highbury EVM", ChainID: 710, Address: Address, URL: "https://explorer.furya.io/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Arthera Testnet", ChainID: 10243, Address: Address, URL: "https://explorer-test.arthera.net/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Arthera Mainnet", ChainID: 10242, Address: Address, URL: "https://explorer.arthera.net/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Manta Pacific Mainnet", ChainID: 169, Address: Address, URL: "https://pacific-explorer.manta.network/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Jolnir (Taiko Testnet)", ChainID: 167007, Address: Address, URL: "https://explorer.jolnir.taiko.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Katla (Taiko A6 Testnet)", ChainID: 167008, Address: Address, URL: "https://explorer.katla.taiko.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Filecoin Mainnet", ChainID: 314, Address: Address, URL: "https://filfox.info/en/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Filecoin Calibration Testnet", ChainID: 314159, Address: Address, URL: "https://calibration.filscan.io/en/tx/0xdbfa261cd7d17bb40479a0493ad6c0fee435859e37aae73aa7e803f3122cc465/"},
+	{Name: "Fusion", ChainID: 32659, Address: Address, URL: "https://fsnscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#contract"},
+	{Name: "Fusion Testnet", ChainID: 46688, Address: Address, URL: "https://testnet.fsnscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#contract"},
+	{Name: "Xai Testnet", ChainID: 47279324479, Address: Address, URL: "https://testnet-explorer.xai-chain.net/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "JFIN Chain", ChainID: 3501, Address: Address, URL: "https://jfinscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "JFIN Chain Testnet", ChainID: 3502, Address: Address, URL: "https://testnet.jfinscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Chiliz Chain", ChainID: 88888, Address: Address, URL: "https://scan.chiliz.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Chiliz Spicy Testnet", ChainID: 88882, Address: Address, URL: "https://testnet.chiliscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11/contract/88882/code"},
+	{Name: "CORE", ChainID: 1116, Address: Address, URL: "https://scan.coredao.org/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Core Testnet", ChainID: 1115, Address: Address, URL: "https://scan.test.btcs.network/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Core Testnet2", ChainID: 1114, Address: Address, URL: "https://scan.test2.btcs.network/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Ethereum Classic", ChainID: 61, Address: Address, URL: "https://etc.blockscout.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Frame Testnet", ChainID: 68840142, Address: Address, URL: "https://explorer.testnet.frame.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Etherlink Mainnet", ChainID: 42793, Address: Address, URL: "https://explorer.etherlink.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Etherlink Testnet", ChainID: 128123, Address: Address, URL: "https://testnet-explorer.etherlink.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "ZetaChain Athens 3 Testnet", ChainID: 7001, Address: Address, URL: "https://explorer.zetachain.com/address/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "ZetaChain ", ChainID: 7000, Address: Address, URL: "https://explorer.zetachain.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "X1 Testnet", ChainID: 195, Address: Address, URL: "https://www.oklink.com/x1-test/address/0xca11bde05977b3631167028862be2a173976ca11/contract"},
+	{Name: "Lumiterra Layer3", ChainID: 94168, Address: Address, URL: "https://scan.layerlumi.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "BitTorrent Chain Mainnet", ChainID: 199, Address: Address, URL: "https://bttcscan.com/address/0xca11bde05977b3631167028862be2a173976ca11#code"},
+	{Name: "BTT Chain Testnet", ChainID: 1029, Address: Address, URL: "https://testnet.bttcscan.com/address/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "Callisto Mainnet", ChainID: 820, Address: Address, URL: "https://explorer.callisto.network/address/0xcA11bde05977b3631167028862bE2a173976CA11/transactions"},
+	{Name: "Areon Network Testnet", ChainID: 462, Address: Address, URL: "https://areonscan.com/contracts/0xca11bde05977b3631167028862be2a173976ca11?page=0"},
+	{Name: "Areon Network Mainnet", ChainID: 463, Address: Address, URL: "https://areonscan.com/contracts/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "zkFair Mainnet", ChainID: 42766, Address: Address, URL: "https://scan.zkfair.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "Mode Mainnet", ChainID: 34443, Address: Address, URL: "https://explorer.mode.network/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Blast Sepolia", ChainID: 168587773, Address: Address, URL: "https://testnet.blastscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contract/168587773/code"},
+	{Name: "Blast", ChainID: 81457, Address: Address, URL: "https://blastscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Xai", ChainID: 660279, Address: Address, URL: "https://explorer.xai-chain.net/address/0xcA11bde05977b3631167028862bE2a173976CA11/contracts#address-tabs"},
+	{Name: "DOS Chain", ChainID: 7979, Address: Address, URL: "https://doscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "DOS Chain Testnet", ChainID: 3939, Address: Address, URL: "https://test.doscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Tron", ChainID: 728126428, Address: common.HexToAddress("0x32a4F47A74a6810BD0bF861CABAb99656a75DE9E"), URL: "https://tronscan.org/#/contract/TEazPvZwDjDtFeJupyo7QunvnrnUjPH8ED/code"},
+	{Name: "zkSync Era", ChainID: 324, Address: common.HexToAddress("0xF9cda624FBC7e059355ce98a31693d299FACd963"), URL: "https://explorer.zksync.io/address/0xF9cda624FBC7e059355ce98a31693d299FACd963#contract"},
+	{Name: "zkSync Era Goerli Testnet", ChainID: 280, Address: common.HexToAddress("0xF9cda624FBC7e059355ce98a31693d299FACd963"), URL: "https://goerli.explorer.zksync.io/address/0xF9cda624FBC7e059355ce98a31693d299FACd963#contract"},
+	{Name: "zkSync Era Sepolia Testnet", ChainID: 300, Address: common.HexToAddress("0xF9cda624FBC7e059355ce98a31693d299FACd963"), URL: "https://sepolia.explorer.zksync.io/address/0xF9cda624FBC7e059355ce98a31693d299FACd963#contract"},
+	{Name: "PlayFi Albireo Testnet", ChainID: 1612127, Address: common.HexToAddress("0xF9cda624FBC7e059355ce98a31693d299FACd963"), URL: "https://albireo-explorer.playfi.ai/address/0xF9cda624FBC7e059355ce98a31693d299FACd963#contract"},
+	{Name: "Abstract Testnet", ChainID: 11124, Address: common.HexToAddress("0xF9cda624FBC7e059355ce98a31693d299FACd963"), URL: "https://explorer.testnet.abs.xyz/address/0xF9cda624FBC7e059355ce98a31693d299FACd963#contract"},
+	{Name: "Abstract Mainnet", ChainID: 2741, Address: common.HexToAddress("0xF9cda624FBC7e059355ce98a31693d299FACd963"), URL: "https://abscan.org/address/0xF9cda624FBC7e059355ce98a31693d299FACd963#code"},
+	{Name: "Fraxtal Mainnet", ChainID: 252, Address: Address, URL: "https://fraxscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Fraxtal Holesky Testnet", ChainID: 2522, Address: Address, URL: "https://holesky.fraxscan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "Omax Mainnet", ChainID: 311, Address: Address, URL: "https://omaxray.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Syndicate Frame Chain", ChainID: 5101, Address: Address, URL: "https://explorer-frame.syndicate.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Dela Sepolia", ChainID: 9393, Address: Address, URL: "https://sepolia-delascan.deperp.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "NeoX Testnet", ChainID: 12227330, Address: Address, URL: "https://xt2scan.ngd.network/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Sanko Mainnet", ChainID: 1996, Address: Address, URL: "https://explorer.sanko.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Sanko Testnet", ChainID: 1992, Address: Address, URL: "https://testnet.sankoscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Berachain Mainnet", ChainID: 80094, Address: Address, URL: "https://berascan.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Berachain Bepolia Testnet", ChainID: 80069, Address: Address, URL: "https://bepolia.beratrail.io/address/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "Shibarium", ChainID: 109, Address: Address, URL: "https://www.shibariumscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Immutable zkEVM Mainnet", ChainID: 13371, Address: Address, URL: "https://explorer.immutable.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Immutable zkEVM Testnet", ChainID: 13473, Address: Address, URL: "https://explorer.testnet.immutable.com/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "RSS3 VSL Mainnet", ChainID: 12553, Address: Address, URL: "https://scan.rss3.io/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "RSS3 VSL Sepolia Testnet", ChainID: 2331, Address: Address, URL: "https://scan.testnet.rss3.io/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Morph Sepolia Testnet", ChainID: 2710, Address: Address, URL: "https://explorer-testnet.morphl2.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Morph Holesky Testnet", ChainID: 2810, Address: Address, URL: "https://explorer-holesky.morphl2.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Morph", ChainID: 2818, Address: Address, URL: "https://explorer.morphl2.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "JIBCHAIN L1", ChainID: 8899, Address: Address, URL: "https://exp-l1.jibchain.net/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Haqq Mainnet", ChainID: 11235, Address: Address, URL: "https://explorer.haqq.network/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Zircuit Sepolia Testnet", ChainID: 48899, Address: Address, URL: "https://explorer.zircuit.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?activeTab=3"},
+	{Name: "re.al", ChainID: 111188, Address: Address, URL: "https://explorer.re.al/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Merlin Testnet", ChainID: 686868, Address: Address, URL: "https://testnet-scan.merlinchain.io/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "IOTA EVM", ChainID: 8822, Address: Address, URL: "https://explorer.evm.iota.org/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Planq", ChainID: 7070, Address: Address, URL: "https://evm.planq.network/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Cyber Testnet", ChainID: 111557560, Address: Address, URL: "https://testnet.cyberscan.co/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Unit Zero Mainnet", ChainID: 88811, Address: Address, URL: "https://explorer.unit0.dev/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Unit Zero Stagenet", ChainID: 88819, Address: Address, URL: "https://explorer-stagenet.unit0.dev/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Unit Zero Testnet", ChainID: 88817, Address: Address, URL: "https://explorer-testnet.unit0.dev/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Sei EVM Devnet", ChainID: 713715, Address: Address, URL: "https://seitrace.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Sei EVM Mainnet", ChainID: 1329, Address: Address, URL: "https://seitrace.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?chain=pacific-1&tab=contract"},
+	{Name: "Hekla (Taiko A7 Testnet)", ChainID: 167009, Address: Address, URL: "https://explorer.hekla.taiko.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Taiko Mainnet", ChainID: 167000, Address: Address, URL: "https://taikoscan.io/address/0xca11bde05977b3631167028862be2a173976ca11#code"},
+	{Name: "Cyber Mainnet", ChainID: 7560, Address: Address, URL: "https://cyberscan.co/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "DreyerX Mainnet", ChainID: 23451, Address: Address, URL: "https://scan.dreyerx.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Sahara Testnet", ChainID: 313313, Address: Address, URL: "https://explorer.saharaa.info/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "BOX Chain", ChainID: 42299, Address: Address, URL: "https://explorerl2new-boxchain-t4zoh9y5dr.t.conduit.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "OX Chain", ChainID: 6699, Address: Address, URL: "https://explorer-ox-chain-2s86s7wp21.t.conduit.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Conflux Espace", ChainID: 1030, Address: Address, URL: "https://evm.confluxscan.net/address/0xca11bde05977b3631167028862be2a173976ca11?tab=contract-viewer"},
+	{Name: "BEVM Testnet", ChainID: 11503, Address: Address, URL: "https://scan-testnet.bevm.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Aura Mainnet", ChainID: 6322, Address: Address, URL: "https://aurascan.io/evm-contracts/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "Superposition Testnet", ChainID: 98985, Address: Address, URL: "https://testnet-explorer.superposition.so/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "X Layer Mainnet", ChainID: 196, Address: Address, URL: "https://www.okx.com/web3/explorer/xlayer/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Nahmii 3 Mainnet", ChainID: 4061, Address: Address, URL: "https://explorer.nahmii.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Nahmii 3 Testnet", ChainID: 4062, Address: Address, URL: "https://explorer.testnet.nahmii.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Plume Testnet", ChainID: 98867, Address: Address, URL: "https://testnet-explorer.plumenetwork.xyz/address/0xca11bde05977b3631167028862be2a173976ca11?tab=contract"},
+	{Name: "Plume Mainnet", ChainID: 98866, Address: Address, URL: "https://phoenix-explorer.plumenetwork.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Algen L1", ChainID: 8911, Address: Address, URL: "https://scan.algen.network/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Bitlayer Mainnet", ChainID: 200901, Address: Address, URL: "https://www.btrscan.com/address/0xca11bde05977b3631167028862be2a173976ca11?tab=Contract"},
+	{Name: "Lisk Mainnet", ChainID: 1135, Address: Address, URL: "https://blockscout.lisk.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Gravity Alpha Mainnet", ChainID: 1625, Address: Address, URL: "https://explorer.gravity.xyz/address/0xca11bde05977b3631167028862be2a173976ca11?tab=contract"},
+	{Name: "Yominet", ChainID: 5264468217, Address: Address, URL: "https://yominet.explorer.caldera.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Bob", ChainID: 60808, Address: Address, URL: "https://explorer.gobob.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Superseed", ChainID: 53302, Address: Address, URL: "https://sepolia-explorer.superseed.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Rupaya", ChainID: 499, Address: Address, URL: "https://scan.rupaya.io/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Fluence Testnet", ChainID: 52164803, Address: Address, URL: "https://blockscout.testnet.fluence.dev/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Fluence Stage", ChainID: 123420000220, Address: Address, URL: "https://blockscout.stage.fluence.dev/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Fluence", ChainID: 9999999, Address: Address, URL: "https://blockscout.mainnet.fluence.dev/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Camp Testnet V2", ChainID: 325000, Address: Address, URL: "https://camp-network-testnet.blockscout.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Ontology Testnet", ChainID: 5851, Address: Address, URL: "https://explorer.ont.io/testnet/contract/other/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "Ontology Mainnet", ChainID: 58, Address: Address, URL: "https://explorer.ont.io/contract/all/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "Viction Testnet", ChainID: 89, Address: Address, URL: "https://testnet.vicscan.xyz/address/0xca11bde05977b3631167028862be2a173976ca11#code"},
+	{Name: "Viction Mainnet", ChainID: 88, Address: Address, URL: "https://www.vicscan.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "World Chain", ChainID: 480, Address: Address, URL: "https://worldchain-mainnet.explorer.alchemy.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Flow Mainnet", ChainID: 747, Address: Address, URL: "https://evm.flowscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Flow Testnet", ChainID: 545, Address: Address, URL: "https://evm-testnet.flowscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Superposition", ChainID: 55244, Address: Address, URL: "https://explorer.superposition.so/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Starchain Testnet", ChainID: 1570, Address: Address, URL: "https://devnet.starchainscan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Starchain Mainnet", ChainID: 1578, Address: Address, URL: "https://starchainscan.io/address/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "ApeChain Mainnet", ChainID: 33139, Address: Address, URL: "https://apescan.io/address/0xcA11bde05977b3631167028862bE2a173976CA11#code"},
+	{Name: "WEMIX 3.0 Mainnet", ChainID: 1111, Address: Address, URL: "https://wemixscan.com/address/0xca11bde05977b3631167028862be2a173976ca11"},
+	{Name: "Aleph Zero EVM Mainnet", ChainID: 41455, Address: Address, URL: "https://evm-explorer.alephzero.org/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "EDUChain Testnet", ChainID: 656476, Address: Address, URL: "https://edu-chain-testnet.blockscout.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Form Testnet", ChainID: 132902, Address: Address, URL: "https://explorer.form.network/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "peaq", ChainID: 3338, Address: Address, URL: "https://peaq.subscan.io/account/0xca11bde05977b3631167028862be2a173976ca11?tab=contract&evm_contract_tab=code"},
+	{Name: "HyperEVM", ChainID: 999, Address: Address, URL: "https://hyperliquid.cloud.blockscout.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Monad Testnet", ChainID: 10143, Address: Address, URL: "https://testnet.monadexplorer.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=Contract"},
+	{Name: "Powerloom Mainnet", ChainID: 7869, Address: Address, URL: "https://explorer-v2.powerloom.network/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Hoodi", ChainID: 560048, Address: Address, URL: "https://hoodi.etherscan.io/address/0xca11bde05977b3631167028862be2a173976ca11#code"},
+	{Name: "MegaETH Testnet", ChainID: 6342, Address: Address, URL: "https://www.megaexplorer.xyz/address/0xcA11bde05977b3631167028862bE2a173976CA11"},
+	{Name: "Ink Sepolia", ChainID: 763373, Address: Address, URL: "https://explorer-sepolia.inkonchain.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Ink", ChainID: 57073, Address: Address, URL: "https://explorer.inkonchain.com/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Bittensor", ChainID: 964, Address: Address, URL: "https://evm.taostats.io/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+	{Name: "Whitechain", ChainID: 1875, Address: Address, URL: "https://explorer.whitechain.io/address/0xcA11bde05977b3631167028862bE2a173976CA11/contract"},
+	{Name: "Tangle Testnet", ChainID: 3799, Address: Address, URL: "https://testnet-explorer.tangle.tools/address/0xca11bde05977b3631167028862be2a173976ca11?tab=contract"},
+	{Name: "Tangle Mainnet", ChainID: 8545, Address: Address, URL: "https://explorer.tangle.tools/address/0xcA11bde05977b3631167028862bE2a173976CA11?tab=contract"},
+}
@@ -0,0 +1,131 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// DryRunError is returned by clients created with WithDryRun when the
+// simulation of a transaction reverts, before it is signed
+type DryRunError struct {
+	// Failed holds the indexes of the calls not allowed to fail that would
+	// revert, in increasing order. It is empty when the multicall would
+	// revert for another reason, or when its calls cannot be told apart, as
+	// for older contract versions.
+	Failed []int
+	// Reasons holds the reason each call of Failed would revert with
+	Reasons []string
+	// Err is the error the simulation of the transaction failed with
+	Err error
+}
+
+func (e *DryRunError) Error() string {
+	if len(e.Failed) == 0 {
+		return fmt.Sprintf("multicall: dry run: %v", e.Err)
+	}
+	failures := make([]string, len(e.Failed))
+	for i, call := range e.Failed {
+		failures[i] = fmt.Sprintf("call %d: %s", call, e.Reasons[i])
+	}
+	return fmt.Sprintf("multicall: dry run: %d calls would fail: %s", len(e.Failed), strings.Join(failures, "; "))
+}
+
+func (e *DryRunError) Unwrap() error {
+	return e.Err
+}
+
+// dryRunTransaction simulates the transaction of method, encoded as data,
+// that opts describes with an eth_call, on the pending state when the
+// contract caller can read it, which the transaction's nonce would be
+// executed on: eth_call itself does not check nonces. Fees are only set along
+// with a gas limit, since the node would otherwise charge its gas cap.
+func (c *Client) dryRunTransaction(ctx context.Context, opts *bind.TransactOpts, method string, data []byte) error {
+	msg := ethereum.CallMsg{
+		From:  opts.From,
+		To:    &c.address,
+		Gas:   opts.GasLimit,
+		Value: opts.Value,
+		Data:  data,
+	}
+	if msg.Gas != 0 {
+		msg.GasPrice, msg.GasFeeCap, msg.GasTipCap = opts.GasPrice, opts.GasFeeCap, opts.GasTipCap
+	}
+	_, err := c.callPending(ctx, msg)
+	if err == nil {
+		return nil
+	}
+	err = classify(err)
+	if !errors.Is(err, ErrExecutionReverted) {
+		return fmt.Errorf("multicall: dry run %s: %w", method, err)
+	}
+	dryRunErr := &DryRunError{Err: err}
+	// Find the calls that revert by running them all allowed to fail
+	method, allowed, relaxed, ok := relaxCalls(data)
+	if !ok {
+		return dryRunErr
+	}
+	msg.Data = relaxed
+	output, err := c.callPending(ctx, msg)
+	if err != nil {
+		return dryRunErr
+	}
+	results, err := unpackResults(method, output)
+	if err != nil || len(results) != len(allowed) {
+		return dryRunErr
+	}
+	for i, result := range results {
+		if !result.Success && !allowed[i] {
+			dryRunErr.Failed = append(dryRunErr.Failed, i)
+			dryRunErr.Reasons = append(dryRunErr.Reasons, result.Reason())
+		}
+	}
+	return dryRunErr
+}
+
+// callPending executes msg on the pending state if the client's contract
+// caller can, and on the latest block otherwise
+func (c *Client) callPending(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	if caller, ok := c.caller.(ethereum.PendingContractCaller); ok {
+		return caller.PendingCallContract(ctx, msg)
+	}
+	return c.caller.CallContract(ctx, msg, nil)
+}
+
+// relaxCalls decodes the calls of an aggregate3 or aggregate3Value
+// transaction encoded as data and returns its method and whether each call is
+// allowed to fail, along with the transaction with all of them allowed to
+// fail. It reports false for other methods, whose calls cannot be allowed to
+// fail.
+func relaxCalls(data []byte) (string, []bool, []byte, bool) {
+	if len(data) < 4 {
+		return "", nil, nil, false
+	}
+	method, err := ABI.MethodById(data[:4])
+	if err != nil || method.Name != "aggregate3" && method.Name != "aggregate3Value" {
+		return "", nil, nil, false
+	}
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil || len(values) != 1 {
+		return "", nil, nil, false
+	}
+	if method.Name == "aggregate3" {
+		calls := *abi.ConvertType(values[0], new([]Call3)).(*[]Call3)
+		allowed := make([]bool, len(calls))
+		for i := range calls {
+			allowed[i], calls[i].AllowFailure = calls[i].AllowFailure, true
+		}
+		return method.Name, allowed, AppendAggregate3(nil, calls), true
+	}
+	calls := *abi.ConvertType(values[0], new([]Call3Value)).(*[]Call3Value)
+	allowed := make([]bool, len(calls))
+	for i := range calls {
+		allowed[i], calls[i].AllowFailure = calls[i].AllowFailure, true
+	}
+	return method.Name, allowed, AppendAggregate3Value(nil, calls), true
+}
@@ -0,0 +1,138 @@
+package multicall
+
+import (
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The encoders below produce the same calldata as ABI.Pack for the multicall
+// methods, but write it directly into a buffer sized up front, without going
+// through reflection. They append to dst and return the extended slice, so a
+// buffer can be reused across multicalls.
+
+// AppendAggregate3 appends the calldata of an aggregate3 multicall of calls to
+// dst and returns the extended buffer. Passing a reused buffer, for example
+// one taken from a sync.Pool, makes encoding allocation free.
+func AppendAggregate3(dst []byte, calls []Call3) []byte {
+	return appendCalls(dst, "aggregate3", nil, calls, call3Size, func(dst []byte, call Call3) ([]byte, []byte) {
+		dst = appendAddress(dst, call.Target)
+		dst = appendBool(dst, call.AllowFailure)
+		return dst, call.CallData
+	})
+}
+
+// AppendAggregate3Value appends the calldata of an aggregate3Value multicall
+// of calls to dst like AppendAggregate3. Nil values are encoded as zero; the
+// values must otherwise be valid uint256s, as checked by TotalValue.
+func AppendAggregate3Value(dst []byte, calls []Call3Value) []byte {
+	return appendCalls(dst, "aggregate3Value", nil, calls, call3ValueSize, func(dst []byte, call Call3Value) ([]byte, []byte) {
+		dst = appendAddress(dst, call.Target)
+		dst = appendBool(dst, call.AllowFailure)
+		dst = appendUint256(dst, call.Value)
+		return dst, call.CallData
+	})
+}
+
+// appendAggregate appends the calldata of method, aggregate or
+// blockAndAggregate, whose only argument is a Call array
+func appendAggregate(dst []byte, method string, calls []Call) []byte {
+	return appendCalls(dst, method, nil, calls, callSize, appendCall)
+}
+
+// appendTryAggregate appends the calldata of method, tryAggregate or
+// tryBlockAndAggregate, whose arguments are requireSuccess and a Call array
+func appendTryAggregate(dst []byte, method string, requireSuccess bool, calls []Call) []byte {
+	return appendCalls(dst, method, []bool{requireSuccess}, calls, callSize, appendCall)
+}
+
+func appendCall(dst []byte, call Call) ([]byte, []byte) {
+	return appendAddress(dst, call.Target), call.CallData
+}
+
+// appendCalls appends the calldata of method called with the static bool
+// arguments flags followed by an array of calls. size returns the encoded
+// size of a call as used for chunking, which includes the call's offset in
+// the array, and fields appends the static fields of a call and returns its
+// dynamic calldata.
+func appendCalls[T any](dst []byte, method string, flags []bool, calls []T, size func(T) int, fields func([]byte, T) ([]byte, []byte)) []byte {
+	total := methodSize + len(flags)*32
+	for _, call := range calls {
+		total += size(call)
+	}
+	dst = slices.Grow(dst, total)
+
+	dst = append(dst, ABI.Methods[method].ID...)
+	for _, flag := range flags {
+		dst = appendBool(dst, flag)
+	}
+	dst = appendInt(dst, (len(flags)+1)*32)
+	dst = appendInt(dst, len(calls))
+
+	// Offsets of the calls, relative to the first offset, followed by the calls
+	offset := len(calls) * 32
+	for _, call := range calls {
+		dst = appendInt(dst, offset)
+		offset += size(call) - 32
+	}
+	for _, call := range calls {
+		start := len(dst)
+		var data []byte
+		dst, data = fields(dst, call)
+		dst = appendInt(dst, len(dst)-start+32)
+		dst = appendInt(dst, len(data))
+		dst = append(dst, data...)
+		dst = append(dst, make([]byte, paddedSize(data)-len(data))...)
+	}
+	return dst
+}
+
+var zeroWord [32]byte
+
+func appendAddress(dst []byte, address common.Address) []byte {
+	dst = append(dst, zeroWord[:32-common.AddressLength]...)
+	return append(dst, address[:]...)
+}
+
+func appendBool(dst []byte, b bool) []byte {
+	if b {
+		return appendInt(dst, 1)
+	}
+	return appendInt(dst, 0)
+}
+
+func appendInt(dst []byte, n int) []byte {
+	dst = append(dst, zeroWord[:24]...)
+	return append(dst, byte(n>>56), byte(n>>48), byte(n>>40), byte(n>>32), byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+func appendUint256(dst []byte, n *big.Int) []byte {
+	start := len(dst)
+	dst = append(dst, zeroWord[:]...)
+	if n != nil {
+		n.FillBytes(dst[start:])
+	}
+	return dst
+}
+
+// maxPooledBufferSize bounds the buffers kept for reuse, so that a single
+// huge multicall does not pin its buffer in memory
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any { return new([]byte) },
+}
+
+func getBuffer() *[]byte {
+	return bufferPool.Get().(*[]byte)
+}
+
+func putBuffer(buf *[]byte, data []byte) {
+	if cap(data) > maxPooledBufferSize {
+		return
+	}
+	*buf = data[:0]
+	bufferPool.Put(buf)
+}
@@ -0,0 +1,104 @@
+package multicall
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// encodeData is the calldata of the calls encoded in the tests, covering no
+// calldata and calldata shorter than, as long as and longer than a word
+var encodeData = [][]byte{nil, {}, {0x01}, bytes.Repeat([]byte{0x02}, 31), bytes.Repeat([]byte{0x03}, 32), bytes.Repeat([]byte{0x04}, 33), bytes.Repeat([]byte{0x05}, 68)}
+
+// encodeCalls returns the calls of every length up to the calldata of
+// encodeData, the first of which is an empty batch
+func encodeCalls() [][]Call {
+	batches := make([][]Call, len(encodeData)+1)
+	for n := range batches {
+		batches[n] = make([]Call, n)
+		for i := range batches[n] {
+			batches[n][i] = Call{Target: common.Address{byte(i + 1)}, CallData: encodeData[i]}
+		}
+	}
+	return batches
+}
+
+// checkEncoded checks that got is the calldata ABI.Pack encodes method with
+func checkEncoded(t *testing.T, got []byte, method string, args ...interface{}) {
+	t.Helper()
+	want, err := ABI.Pack(method, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s: got calldata\n%x\nwant\n%x", method, got, want)
+	}
+}
+
+func TestAppendAggregate3(t *testing.T) {
+	for _, batch := range encodeCalls() {
+		calls := make([]Call3, len(batch))
+		for i, call := range batch {
+			calls[i] = Call3{Target: call.Target, AllowFailure: i%2 == 1, CallData: call.CallData}
+		}
+		checkEncoded(t, AppendAggregate3(nil, calls), "aggregate3", calls)
+	}
+}
+
+func TestAppendAggregate3Value(t *testing.T) {
+	for _, batch := range encodeCalls() {
+		calls := make([]Call3Value, len(batch))
+		packed := make([]Call3Value, len(batch))
+		for i, call := range batch {
+			calls[i] = Call3Value{Target: call.Target, AllowFailure: i%2 == 0, CallData: call.CallData}
+			packed[i] = calls[i]
+			// Nil values are encoded as zero, which ABI.Pack does not accept
+			packed[i].Value = new(big.Int)
+			if i%3 != 0 {
+				calls[i].Value = new(big.Int).Lsh(big.NewInt(int64(i)), 200)
+				packed[i].Value = calls[i].Value
+			}
+		}
+		checkEncoded(t, AppendAggregate3Value(nil, calls), "aggregate3Value", packed)
+	}
+}
+
+func TestAppendAggregate(t *testing.T) {
+	for _, calls := range encodeCalls() {
+		for _, method := range []string{"aggregate", "blockAndAggregate"} {
+			checkEncoded(t, appendAggregate(nil, method, calls), method, calls)
+		}
+		for _, method := range []string{"tryAggregate", "tryBlockAndAggregate"} {
+			for _, requireSuccess := range []bool{false, true} {
+				checkEncoded(t, appendTryAggregate(nil, method, requireSuccess, calls), method, requireSuccess, calls)
+			}
+		}
+	}
+}
+
+func TestAppendAggregate3KeepsDst(t *testing.T) {
+	calls := []Call3{{Target: common.Address{1}, CallData: []byte{0x01, 0x02}}}
+	prefix := []byte("prefix")
+	got := AppendAggregate3(append([]byte(nil), prefix...), calls)
+	if !bytes.HasPrefix(got, prefix) {
+		t.Fatalf("got calldata %x, want it to start with %x", got, prefix)
+	}
+	checkEncoded(t, got[len(prefix):], "aggregate3", calls)
+}
+
+func TestEncodedSizes(t *testing.T) {
+	// The sizes chunking relies on are those of the encoded calldata
+	for _, batch := range encodeCalls() {
+		calls := make([]Call3, len(batch))
+		size := methodSize
+		for i, call := range batch {
+			calls[i] = Call3{Target: call.Target, CallData: call.CallData}
+			size += call3Size(calls[i])
+		}
+		if got := len(AppendAggregate3(nil, calls)); got != size {
+			t.Errorf("%d calls: got %d bytes of calldata, want %d", len(calls), got, size)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package multicall
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// ErrorRegistry decodes the revert data of failed calls into the custom
+// errors of the contracts registered with it, on top of what DecodeRevert
+// decodes. It is safe for concurrent use.
+type ErrorRegistry struct {
+	mu     sync.RWMutex
+	errors map[[4]byte]abi.Error
+}
+
+// NewErrorRegistry returns an empty ErrorRegistry
+func NewErrorRegistry() *ErrorRegistry {
+	return &ErrorRegistry{errors: make(map[[4]byte]abi.Error)}
+}
+
+// RegisterABI registers every custom error declared in contractABI
+func (r *ErrorRegistry) RegisterABI(contractABI abi.ABI) {
+	for _, errorABI := range contractABI.Errors {
+		r.RegisterError(errorABI)
+	}
+}
+
+// RegisterError registers a single custom error. An error with the same
+// selector as one registered before replaces it.
+func (r *ErrorRegistry) RegisterError(errorABI abi.Error) {
+	var selector [4]byte
+	copy(selector[:], errorABI.ID[:4])
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors[selector] = errorABI
+}
+
+// Decode decodes revert data like DecodeRevert, and into a registered custom
+// error when its selector matches one
+func (r *ErrorRegistry) Decode(data []byte) *Revert {
+	revert := DecodeRevert(data)
+	if len(data) < 4 || revert.Message != "" || revert.PanicCode != nil {
+		return revert
+	}
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	r.mu.RLock()
+	errorABI, ok := r.errors[selector]
+	r.mu.RUnlock()
+	if !ok {
+		return revert
+	}
+	args, err := errorABI.Inputs.Unpack(data[4:])
+	if err != nil {
+		return revert
+	}
+	revert.CustomError, revert.Args = &errorABI, args
+	return revert
+}
+
+// Revert decodes the revert data of result with Decode if the call failed,
+// and returns nil if it succeeded
+func (r *ErrorRegistry) Revert(result Result) *Revert {
+	if result.Success {
+		return nil
+	}
+	return r.Decode(result.ReturnData)
+}
+
+// Reason is like Result.Reason, but names the registered custom errors and
+// lists their arguments
+func (r *ErrorRegistry) Reason(result Result) string {
+	if result.Success {
+		return ""
+	}
+	return r.Revert(result).String()
+}
@@ -0,0 +1,34 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestErrorRegistryDecodesCustomErrors(t *testing.T) {
+	client, _ := newFakeClient()
+	data, err := tokenABI.Pack("transfer", owner(2), big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := client.Aggregate3(context.Background(), []Call3{{Target: tokenAddress, AllowFailure: true, CallData: data}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := NewErrorRegistry()
+	if revert := registry.Revert(results[0]); revert.CustomError != nil {
+		t.Fatalf("unregistered error decoded as %s", revert.CustomError.Name)
+	}
+	registry.RegisterABI(tokenABI)
+	revert := registry.Revert(results[0])
+	if revert.CustomError == nil || revert.CustomError.Name != "InsufficientBalance" {
+		t.Fatalf("got revert %+v, want InsufficientBalance", revert)
+	}
+	if required := revert.Args[1].(*big.Int); required.Int64() != 5 {
+		t.Errorf("got required %s, want 5", required)
+	}
+	if got, want := registry.Reason(results[0]), "InsufficientBalance(available: 0, required: 5)"; got != want {
+		t.Errorf("got reason %q, want %q", got, want)
+	}
+}
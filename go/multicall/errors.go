@@ -0,0 +1,186 @@
+package multicall
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Errors that classify the failures of a multicall, to be tested with
+// errors.Is. They wrap the original error, from the node or otherwise, which
+// remains available through errors.Is and errors.As too.
+var (
+	// ErrBatchTooLarge reports that a multicall was too large for the
+	// provider, by calldata or response size, gas or execution time, and may
+	// succeed split into smaller multicalls
+	ErrBatchTooLarge = errors.New("multicall: batch too large")
+	// ErrRateLimited reports that the provider rejected a request for
+	// exceeding its rate limit
+	ErrRateLimited = errors.New("multicall: rate limited")
+	// ErrExecutionReverted reports that the multicall itself reverted, for
+	// example because a call that was not allowed to fail did
+	ErrExecutionReverted = errors.New("multicall: execution reverted")
+	// ErrMulticallNotDeployed reports that there is no Multicall3 contract,
+	// or no known deployment, at the client's address on the chain
+	ErrMulticallNotDeployed = errors.New("multicall: Multicall3 not deployed")
+	// ErrDecode reports that return data could not be decoded
+	ErrDecode = errors.New("multicall: decode failed")
+	// ErrOutOfGas reports that a multicall ran out of gas, or was aborted by
+	// the node for running too long. Such multicalls are split until the
+	// call responsible is isolated, which is then reported with an
+	// OutOfGasError.
+	ErrOutOfGas = errors.New("multicall: out of gas")
+	// ErrBlockMismatch reports that the block a multicall was executed at
+	// does not match the header the provider returns for its number, as
+	// after a reorg or with a load balancer whose nodes disagree
+	ErrBlockMismatch = errors.New("multicall: block mismatch")
+	// ErrValueMismatch reports that the value of an aggregate3Value
+	// transaction is not the sum of the values its calls forward
+	ErrValueMismatch = errors.New("multicall: value mismatch")
+)
+
+// OutOfGasError reports the call that makes a multicall run out of gas, or
+// be aborted, on its own
+type OutOfGasError struct {
+	// Call is the index of the call in the batch
+	Call int
+	// Err is the error the multicall of the call alone failed with
+	Err error
+}
+
+func (e *OutOfGasError) Error() string {
+	return fmt.Sprintf("multicall: call %d runs out of gas: %v", e.Call, e.Err)
+}
+
+func (e *OutOfGasError) Unwrap() error {
+	return e.Err
+}
+
+// kindError classifies err as one of the errors above without changing its
+// message
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// withKind classifies err, which may be nil, as kind
+func withKind(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &kindError{kind: kind, err: err}
+}
+
+// classify classifies an error returned by the node for a multicall
+func classify(err error) error {
+	switch {
+	case isRateLimitError(err):
+		return withKind(ErrRateLimited, err)
+	case isRevertError(err):
+		return withKind(ErrExecutionReverted, err)
+	case isOutOfGasError(err):
+		return withKind(ErrOutOfGas, err)
+	case isTooLargeError(err) || isGasLimitError(err):
+		return withKind(ErrBatchTooLarge, err)
+	}
+	return err
+}
+
+// isRateLimitError reports whether err is an HTTP 429 or JSON-RPC rate
+// limiting error
+func isRateLimitError(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rateLimitedCode
+}
+
+// revertedCode is the JSON-RPC error code geth returns for reverted calls
+const revertedCode = 3
+
+func isRevertError(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == revertedCode {
+		return true
+	}
+	return containsAny(err, []string{"execution reverted"})
+}
+
+// outOfGasErrors are fragments of the errors nodes return when a call runs
+// out of gas or is aborted while executing
+var outOfGasErrors = []string{
+	"out of gas",
+	"execution aborted",
+}
+
+func isOutOfGasError(err error) bool {
+	return containsAny(err, outOfGasErrors)
+}
+
+// gasLimitErrors are fragments of the errors nodes return when a call needs
+// more gas than they allow
+var gasLimitErrors = []string{
+	"gas required exceeds allowance",
+	"exceeds block gas limit",
+	"out of gas",
+	"intrinsic gas too high",
+}
+
+func isGasLimitError(err error) bool {
+	return containsAny(err, gasLimitErrors)
+}
+
+// tooLargeErrors are fragments of the errors providers return when a request
+// or its response is too large, or takes too long, to be served
+var tooLargeErrors = []string{
+	"request too large",
+	"request entity too large",
+	"payload too large",
+	"response too large",
+	"response size exceeded",
+	"response size should not",
+	"query timeout",
+	"execution timeout",
+	"timed out",
+}
+
+// isTooLargeError reports whether err indicates that a multicall may succeed
+// if it is sent with fewer calls
+func isTooLargeError(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusRequestEntityTooLarge, http.StatusGatewayTimeout, http.StatusRequestTimeout:
+			return true
+		}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return containsAny(err, tooLargeErrors)
+}
+
+func containsAny(err error, fragments []string) bool {
+	msg := strings.ToLower(err.Error())
+	for _, fragment := range fragments {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// GasEstimate is what a transaction of calls would cost, see
+// Client.EstimateGas
+type GasEstimate struct {
+	// Gas is the gas estimated for the transaction, as a single multicall
+	Gas uint64
+	// CalldataSize is the size of the calldata of the transaction in bytes
+	CalldataSize int
+	// Chunks is the number of multicalls the client's limits on calls and
+	// calldata size split the calls into, see WithMaxCalls and
+	// WithMaxCalldataSize, one if they fit in a single multicall
+	Chunks int
+}
+
+// EstimateGas estimates with eth_estimateGas, on the latest block, the gas of
+// a transaction of calls, as sent by SendAggregate, along with the size of
+// its calldata and the number of multicalls the client would split it into,
+// to know what a batch costs, and whether it needs splitting, before sending
+// it. The estimate fails if a call that may not fail would revert. The gas
+// options of opts apply to the estimate; the other call options are not
+// supported. Gas is estimated through the transactor set with
+// WithTransactor, or the contract caller of the client.
+func (c *Client) EstimateGas(ctx context.Context, calls []Call3, opts ...CallOption) (*GasEstimate, error) {
+	o := newCallOptions(opts)
+	if o.err != nil {
+		return nil, o.err
+	}
+	if c.deployless {
+		return nil, errors.New("multicall: transactions cannot be estimated in deployless mode")
+	}
+	if len(o.overrides) > 0 || o.blockOverrides != nil || o.from != nil || o.block != nil || o.blockHash != nil {
+		return nil, errors.New("multicall: gas estimates only take gas options")
+	}
+	method, data, err := c.aggregateData(calls)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := c.estimateData(ctx, o, method, data)
+	if err != nil {
+		return nil, err
+	}
+	return &GasEstimate{Gas: gas, CalldataSize: len(data), Chunks: len(c.chunkLens(calls))}, nil
+}
+
+// estimateData estimates the gas of a transaction of method, encoded as
+// data, to the Multicall3 contract, as a contract creation in deployless
+// mode, with the gas options of opts. Clients injecting the code of
+// Multicall3 cannot estimate gas, since eth_estimateGas takes no state
+// overrides.
+func (c *Client) estimateData(ctx context.Context, opts *callOptions, method string, data []byte) (uint64, error) {
+	if c.injectCode {
+		return 0, errors.New("multicall: gas cannot be estimated with code overrides")
+	}
+	var estimator ethereum.GasEstimator = c.transactor
+	if estimator == nil {
+		var ok bool
+		if estimator, ok = c.caller.(ethereum.GasEstimator); !ok {
+			return 0, errors.New("multicall: contract caller cannot estimate gas")
+		}
+	}
+	msg := ethereum.CallMsg{
+		To:        &c.address,
+		Data:      data,
+		Gas:       opts.gas,
+		GasPrice:  opts.gasPrice,
+		GasFeeCap: opts.gasFeeCap,
+		GasTipCap: opts.gasTipCap,
+	}
+	if c.deployless {
+		msg.To, msg.Data = nil, deploylessData(data)
+	}
+	gas, err := estimator.EstimateGas(ctx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("multicall: estimate gas for %s: %w", method, classify(err))
+	}
+	return gas, nil
+}
+
+// chunkLens returns the number of calls of each multicall the client's limits
+// on calls and calldata size split calls into, a single multicall of no calls
+// if there are none
+func (c *Client) chunkLens(calls []Call3) []int {
+	var lens []int
+	limit := c.chunkLimit()
+	for start := 0; start < len(calls) || len(lens) == 0; {
+		n := chunkLen(c, calls[start:], limit, methodSize, call3Size)
+		lens = append(lens, n)
+		start += n
+	}
+	return lens
+}
@@ -0,0 +1,35 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AddEthBalance appends a call of the Multicall3 getEthBalance helper, whose
+// value is the native balance of account as a *big.Int, so native balances
+// can be fetched along with token balances in the same batch. It needs the
+// multicall contract to be deployed at the client's address, or injected
+// there with WithCodeOverride, and so is not available in deployless mode.
+func (b *Batch) AddEthBalance(account common.Address) *Batch {
+	return b.Add(b.client.address, ABI, "getEthBalance", account)
+}
+
+// EthBalances returns the native balances of accounts, in wei, fetched in a
+// single batch like AddEthBalance
+func (c *Client) EthBalances(ctx context.Context, accounts []common.Address, opts ...CallOption) ([]*big.Int, error) {
+	batch := c.NewBatch()
+	for _, account := range accounts {
+		batch.AddEthBalance(account)
+	}
+	values, err := batch.Execute(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	balances := make([]*big.Int, len(values))
+	for i, value := range values {
+		balances[i] = value[0].(*big.Int)
+	}
+	return balances, nil
+}
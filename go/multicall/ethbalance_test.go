@@ -0,0 +1,42 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/fake"
+)
+
+func TestEthBalances(t *testing.T) {
+	// The balances are read by the code of Multicall3 in an EVM
+	client, err := NewClient(fake.NewCaller(nil), WithCodeOverride(newEVMCaller(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	balances, err := client.EthBalances(context.Background(), []common.Address{funded, {0x78}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(balances) != 2 || balances[0].Int64() != 1e18 || balances[1].Sign() != 0 {
+		t.Errorf("got balances %v, want 1e18 and 0", balances)
+	}
+}
+
+func TestAddEthBalance(t *testing.T) {
+	client, caller := newFakeClient()
+	caller.Contracts[Address] = fake.Methods(ABI, map[string]func(args []interface{}) ([]interface{}, error){
+		"getEthBalance": fake.Returns(big.NewInt(42)),
+	})
+	values, err := client.NewBatch().
+		AddEthBalance(owner(1)).
+		Add(tokenAddress, tokenABI, "balanceOf", owner(1)).
+		Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eth, token := values[0][0].(*big.Int), values[1][0].(*big.Int); eth.Int64() != 42 || token.Int64() != 1 {
+		t.Errorf("got balances %s and %s, want 42 wei and 1 token", eth, token)
+	}
+}
@@ -0,0 +1,119 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/internal/helper"
+)
+
+// tokenABI is the part of the ERC-20 ABI the fake token implements
+var tokenABI = helper.MustParseABI("multicall", `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}
+]`)
+
+// fakeToken answers balanceOf with the last byte of the owner's address, and
+// reverts transfers with InsufficientBalance(0, amount)
+var fakeToken = fake.Methods(tokenABI, map[string]func(args []interface{}) ([]interface{}, error){
+	"balanceOf": func(args []interface{}) ([]interface{}, error) {
+		owner := args[0].(common.Address)
+		return []interface{}{big.NewInt(int64(owner[len(owner)-1]))}, nil
+	},
+	"transfer": func(args []interface{}) ([]interface{}, error) {
+		insufficient := tokenABI.Errors["InsufficientBalance"]
+		revert, err := insufficient.Inputs.Pack(common.Big0, args[1])
+		if err != nil {
+			return nil, err
+		}
+		return nil, &fake.Revert{Data: append(insufficient.ID[:4:4], revert...)}
+	},
+})
+
+// revertWith reverts every call with Error(reason)
+func revertWith(reason string) fake.Contract {
+	return func(ctx context.Context, data []byte) ([]byte, error) {
+		packed, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &fake.Revert{Data: append(append([]byte(nil), errorSelector...), packed...)}
+	}
+}
+
+// outOfGas runs the multicall of every call out of gas
+func outOfGas(ctx context.Context, data []byte) ([]byte, error) {
+	return nil, fake.ErrOutOfGas
+}
+
+// stall never answers, until ctx is done
+func stall(ctx context.Context, data []byte) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+var (
+	tokenAddress    = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	reverterAddress = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	guzzlerAddress  = common.HexToAddress("0x3000000000000000000000000000000000000003")
+	stallerAddress  = common.HexToAddress("0x4000000000000000000000000000000000000004")
+)
+
+// newFakeClient returns a client executing its calls against a fake token,
+// a contract reverting with "nope", one running out of gas and one that never
+// answers, along with their caller
+func newFakeClient(opts ...Option) (*Client, *fake.Caller) {
+	caller := fake.NewCaller(map[common.Address]fake.Contract{
+		tokenAddress:    fakeToken,
+		reverterAddress: revertWith("nope"),
+		guzzlerAddress:  outOfGas,
+		stallerAddress:  stall,
+	})
+	client, err := NewClient(caller, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return client, caller
+}
+
+// owner returns the address ending with b, whose balance of the fake token
+// is b
+func owner(b byte) common.Address {
+	return common.BytesToAddress([]byte{b})
+}
+
+// balanceCall returns a balanceOf call of the fake token for owner(b)
+func balanceCall(b byte) Call3 {
+	data, err := tokenABI.Pack("balanceOf", owner(b))
+	if err != nil {
+		panic(err)
+	}
+	return Call3{Target: tokenAddress, CallData: data}
+}
+
+// checkBalances checks that results are the balances of the fake token
+func checkBalances(t *testing.T, results []Result, balances ...int64) {
+	t.Helper()
+	if len(results) != len(balances) {
+		t.Fatalf("got %d results, want %d", len(results), len(balances))
+	}
+	for i, result := range results {
+		checkBalance(t, result, balances[i])
+	}
+}
+
+func checkBalance(t *testing.T, result Result, balance int64) {
+	t.Helper()
+	if !result.Success {
+		t.Errorf("call failed: %s", result.Reason())
+		return
+	}
+	if got := new(big.Int).SetBytes(result.ReturnData); got.Int64() != balance {
+		t.Errorf("got balance %s, want %d", got, balance)
+	}
+}
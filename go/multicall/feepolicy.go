@@ -0,0 +1,159 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// FeePolicy configures the EIP-1559 fees transactions are sent with, see
+// Client.SuggestFees
+type FeePolicy struct {
+	// Blocks is the number of recent blocks the priority fees are sampled
+	// from
+	Blocks int
+	// Percentile, between 0 and 100, selects the priority fee paid in each
+	// block, by gas used: 50 pays as much as the median transaction
+	Percentile float64
+	// BaseFeeMultiplier scales the base fee of the next block in the fee cap,
+	// so that the transaction stays includable while the base fee rises:
+	// 2 covers six full blocks in a row
+	BaseFeeMultiplier float64
+	// MaxFeePerGas and MaxPriorityFeePerGas, if not nil, bound the fee cap
+	// and the priority fee suggested. A fee cap below the base fee keeps the
+	// transaction waiting until the base fee falls.
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+// DefaultFeePolicy pays the median priority fee of the last 20 blocks, with a
+// fee cap of twice the next base fee on top
+var DefaultFeePolicy = FeePolicy{
+	Blocks:            20,
+	Percentile:        50,
+	BaseFeeMultiplier: 2,
+}
+
+// WithFeePolicy sets the policy the fees of the transactions of the client
+// are suggested with, see Client.SuggestFees, instead of DefaultFeePolicy
+func WithFeePolicy(policy FeePolicy) Option {
+	return func(c *Client) {
+		c.feePolicy = &policy
+	}
+}
+
+// FeeSuggestion is the EIP-1559 fees suggested for a transaction
+type FeeSuggestion struct {
+	// BaseFee is the base fee per gas of the next block
+	BaseFee              *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+// errNoBaseFee is returned by SuggestFees on chains without EIP-1559
+var errNoBaseFee = errors.New("multicall: chain has no base fee")
+
+// feeHistory is the result of eth_feeHistory
+type feeHistory struct {
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+	Reward       [][]*hexutil.Big `json:"reward"`
+}
+
+// SuggestFees suggests the EIP-1559 fees of a transaction with the client's
+// fee policy, see WithFeePolicy, from the eth_feeHistory of the latest
+// blocks: the priority fee is the median, over the blocks that are not
+// empty, of the percentile of the policy of the priority fees paid in each,
+// or eth_maxPriorityFeePerGas if they are all empty. The transactions of
+// the client are sent with these fees unless given fees of their own. It
+// needs an RPC client, see WithRPCClient.
+func (c *Client) SuggestFees(ctx context.Context) (*FeeSuggestion, error) {
+	policy := DefaultFeePolicy
+	if c.feePolicy != nil {
+		policy = *c.feePolicy
+	}
+	client, err := c.rpcClient("SuggestFees")
+	if err != nil {
+		return nil, err
+	}
+	var history feeHistory
+	if err := client.CallContext(ctx, &history, "eth_feeHistory", hexutil.Uint64(policy.Blocks), Latest, []float64{policy.Percentile}); err != nil {
+		return nil, fmt.Errorf("multicall: get fee history: %w", classify(err))
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, errors.New("multicall: get fee history: no blocks")
+	}
+	// The last base fee is that of the next block
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+	if baseFee == nil || baseFee.ToInt().Sign() == 0 {
+		return nil, errNoBaseFee
+	}
+	var tips []*big.Int
+	for i, reward := range history.Reward {
+		if i < len(history.GasUsedRatio) && history.GasUsedRatio[i] > 0 && len(reward) > 0 && reward[0] != nil {
+			tips = append(tips, reward[0].ToInt())
+		}
+	}
+	var tip *big.Int
+	if len(tips) > 0 {
+		sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+		tip = tips[len(tips)/2]
+	} else {
+		var suggested hexutil.Big
+		if err := client.CallContext(ctx, &suggested, "eth_maxPriorityFeePerGas"); err != nil {
+			return nil, fmt.Errorf("multicall: get max priority fee: %w", classify(err))
+		}
+		tip = suggested.ToInt()
+	}
+	if policy.MaxPriorityFeePerGas != nil && tip.Cmp(policy.MaxPriorityFeePerGas) > 0 {
+		tip = policy.MaxPriorityFeePerGas
+	}
+	scaled, _ := new(big.Float).Mul(new(big.Float).SetInt(baseFee.ToInt()), big.NewFloat(policy.BaseFeeMultiplier)).Int(nil)
+	feeCap := scaled.Add(scaled, tip)
+	if policy.MaxFeePerGas != nil && feeCap.Cmp(policy.MaxFeePerGas) > 0 {
+		feeCap = new(big.Int).Set(policy.MaxFeePerGas)
+	}
+	if tip.Cmp(feeCap) > 0 {
+		tip = feeCap
+	}
+	return &FeeSuggestion{BaseFee: baseFee.ToInt(), MaxFeePerGas: feeCap, MaxPriorityFeePerGas: new(big.Int).Set(tip)}, nil
+}
+
+// suggestFees fills in the fees opts leaves unset with those suggested by
+// SuggestFees. Options with a legacy gas price are left as they are, as are
+// all of them on chains without EIP-1559, for clients without an RPC client
+// and with nodes without eth_feeHistory, whose transactions are left to the
+// transactor to price.
+func (c *Client) suggestFees(ctx context.Context, opts *bind.TransactOpts) error {
+	if opts.GasPrice != nil || (opts.GasFeeCap != nil && opts.GasTipCap != nil) {
+		return nil
+	}
+	if _, err := c.rpcClient("SuggestFees"); err != nil {
+		return nil
+	}
+	fees, err := c.SuggestFees(ctx)
+	if errors.Is(err, errNoBaseFee) || (err != nil && isMethodNotFound(err)) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if opts.GasTipCap == nil {
+		opts.GasTipCap = fees.MaxPriorityFeePerGas
+		if opts.GasFeeCap != nil && opts.GasTipCap.Cmp(opts.GasFeeCap) > 0 {
+			opts.GasTipCap = opts.GasFeeCap
+		}
+	}
+	if opts.GasFeeCap == nil {
+		opts.GasFeeCap = fees.MaxFeePerGas
+		if opts.GasTipCap.Cmp(opts.GasFeeCap) > 0 {
+			opts.GasFeeCap = new(big.Int).Add(fees.BaseFee, opts.GasTipCap)
+		}
+	}
+	return nil
+}
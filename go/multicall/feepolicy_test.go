@@ -0,0 +1,102 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// feeService serves eth_feeHistory and eth_maxPriorityFeePerGas with the
+// history it is set to
+type feeService struct {
+	history     feeHistory
+	maxPriority *big.Int
+	// blocks and percentiles are those of the last eth_feeHistory
+	blocks      hexutil.Uint64
+	percentiles []float64
+}
+
+func (s *feeService) FeeHistory(blocks hexutil.Uint64, newest string, percentiles []float64) (*feeHistory, error) {
+	s.blocks, s.percentiles = blocks, percentiles
+	return &s.history, nil
+}
+
+func (s *feeService) MaxPriorityFeePerGas() (*hexutil.Big, error) {
+	return (*hexutil.Big)(s.maxPriority), nil
+}
+
+// newFeeClient returns a client suggesting fees from service with policy
+func newFeeClient(t *testing.T, service *feeService, opts ...Option) *Client {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	rpcClient := rpc.DialInProc(server)
+	t.Cleanup(rpcClient.Close)
+	client, _ := newFakeClient(append([]Option{WithRPCClient(rpcClient)}, opts...)...)
+	return client
+}
+
+func gwei(n int64) *hexutil.Big {
+	return (*hexutil.Big)(new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9)))
+}
+
+func TestSuggestFees(t *testing.T) {
+	service := &feeService{history: feeHistory{
+		BaseFee:      []*hexutil.Big{gwei(9), gwei(10), gwei(11), gwei(10)},
+		GasUsedRatio: []float64{0.5, 0, 0.9},
+		// The empty block's reward is left out of the median
+		Reward: [][]*hexutil.Big{{gwei(1)}, {gwei(100)}, {gwei(3)}},
+	}}
+	client := newFeeClient(t, service, WithFeePolicy(FeePolicy{Blocks: 3, Percentile: 60, BaseFeeMultiplier: 2}))
+	fees, err := client.SuggestFees(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.blocks != 3 || len(service.percentiles) != 1 || service.percentiles[0] != 60 {
+		t.Errorf("fee history read for %d blocks at percentiles %v, want 3 at [60]", service.blocks, service.percentiles)
+	}
+	checkFee(t, "base fee", fees.BaseFee, 10)
+	checkFee(t, "priority fee", fees.MaxPriorityFeePerGas, 3)
+	checkFee(t, "fee cap", fees.MaxFeePerGas, 23)
+}
+
+func TestSuggestFeesOfEmptyBlocks(t *testing.T) {
+	service := &feeService{
+		history: feeHistory{
+			BaseFee:      []*hexutil.Big{gwei(10), gwei(10)},
+			GasUsedRatio: []float64{0},
+			Reward:       [][]*hexutil.Big{{gwei(0)}},
+		},
+		maxPriority: gwei(2).ToInt(),
+	}
+	maxFee := gwei(15).ToInt()
+	client := newFeeClient(t, service, WithFeePolicy(FeePolicy{Blocks: 1, Percentile: 50, BaseFeeMultiplier: 2, MaxFeePerGas: maxFee}))
+	fees, err := client.SuggestFees(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFee(t, "priority fee", fees.MaxPriorityFeePerGas, 2)
+	checkFee(t, "capped fee cap", fees.MaxFeePerGas, 15)
+}
+
+func TestSuggestFeesWithoutBaseFee(t *testing.T) {
+	service := &feeService{history: feeHistory{BaseFee: []*hexutil.Big{gwei(0)}}}
+	client := newFeeClient(t, service)
+	if _, err := client.SuggestFees(context.Background()); !errors.Is(err, errNoBaseFee) {
+		t.Errorf("got error %v, want errNoBaseFee", err)
+	}
+}
+
+func checkFee(t *testing.T, name string, fee *big.Int, gweis int64) {
+	t.Helper()
+	if want := gwei(gweis).ToInt(); fee.Cmp(want) != 0 {
+		t.Errorf("got %s %s, want %s", name, fee, want)
+	}
+}
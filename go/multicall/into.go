@@ -0,0 +1,65 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/john-na4/multicall3/go/internal/helper"
+)
+
+// tagName is the struct tag Batch.Into matches against call keys
+const tagName = "multicall"
+
+// Into executes the batch and decodes the results of keyed calls into the
+// fields of the struct out points to. Fields are matched to calls by their
+// `multicall:"key"` tag, so a field tagged `multicall:"symbol"` receives the
+// return value of the call labeled with Key("symbol"). Calls with several
+// outputs must be decoded into struct fields. Untagged fields, and fields
+// tagged "-", are left untouched, like the fields of calls not executed with
+// PartialResults. A call that reverted, which it may with AllowFailure, or
+// whose return data cannot be decoded leaves its field untouched too, without
+// affecting the other fields, and the errors of all such calls are returned,
+// joined with errors.Join, once every other field is set. They wrap
+// ErrExecutionReverted and ErrDecode respectively.
+func (b *Batch) Into(ctx context.Context, out interface{}, opts ...CallOption) error {
+	dst := reflect.ValueOf(out)
+	if dst.Kind() != reflect.Pointer || dst.IsNil() || dst.Elem().Kind() != reflect.Struct {
+		return errors.New("multicall: Into requires a non-nil pointer to a struct")
+	}
+	results, err := b.ExecuteKeyed(ctx, opts...)
+	incompleteErr := incomplete(err)
+	if err != nil && incompleteErr == nil {
+		return err
+	}
+	var errs []error
+	dst = dst.Elem()
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Type().Field(i)
+		key, ok := field.Tag.Lookup(tagName)
+		if !ok || key == "-" {
+			continue
+		}
+		if !field.IsExported() {
+			return fmt.Errorf("multicall: field %s tagged %q is not exported", field.Name, key)
+		}
+		result, ok := results[key]
+		if !ok && incompleteErr != nil && b.hasKey(key) {
+			continue
+		}
+		if !ok {
+			return fmt.Errorf("multicall: no call keyed %q for field %s", key, field.Name)
+		}
+		call := b.calls[b.keys[key]]
+		if !result.Success || result.Err != nil {
+			errs = append(errs, result.Error(call.target, call.method))
+			continue
+		}
+		outputs := call.abi.Methods[call.method].Outputs
+		if err := convertValues(outputs, result.Values, dst.Field(i).Addr().Interface()); err != nil {
+			errs = append(errs, withKind(ErrDecode, fmt.Errorf("multicall: decode %q into field %s: %w", key, field.Name, err)))
+		}
+	}
+	return helper.JoinErrors(append([]error{err}, errs...)...)
+}
@@ -0,0 +1,53 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestBatchInto(t *testing.T) {
+	client, _ := newFakeClient()
+	var out struct {
+		First    *big.Int `multicall:"first"`
+		Second   *big.Int `multicall:"second"`
+		Reverted *big.Int `multicall:"reverted"`
+		Skipped  *big.Int `multicall:"-"`
+	}
+	out.Reverted = big.NewInt(-1)
+	err := client.NewBatch().
+		Add(tokenAddress, tokenABI, "balanceOf", owner(1)).Key("first").
+		Add(tokenAddress, tokenABI, "balanceOf", owner(2)).Key("second").
+		Add(reverterAddress, tokenABI, "balanceOf", owner(3)).Key("reverted").AllowFailure().
+		Into(context.Background(), &out)
+	if !errors.Is(err, ErrExecutionReverted) || errors.Is(err, ErrDecode) {
+		t.Errorf("got error %v, want only ErrExecutionReverted", err)
+	}
+	if out.First.Int64() != 1 || out.Second.Int64() != 2 {
+		t.Errorf("got balances %s and %s, want 1 and 2", out.First, out.Second)
+	}
+	if out.Reverted.Int64() != -1 || out.Skipped != nil {
+		t.Errorf("fields of a reverted call and an ignored one set to %s and %s", out.Reverted, out.Skipped)
+	}
+}
+
+func TestBatchIntoErrors(t *testing.T) {
+	client, _ := newFakeClient()
+	batch := client.NewBatch().Add(tokenAddress, tokenABI, "balanceOf", owner(1)).Key("balance")
+	var missing struct {
+		Other *big.Int `multicall:"other"`
+	}
+	if err := batch.Into(context.Background(), &missing); err == nil {
+		t.Error("field without a call decoded")
+	}
+	var mismatched struct {
+		Balance string `multicall:"balance"`
+	}
+	if err := batch.Into(context.Background(), &mismatched); !errors.Is(err, ErrDecode) {
+		t.Errorf("got error %v decoding a balance into a string, want ErrDecode", err)
+	}
+	if err := batch.Into(context.Background(), missing); err == nil {
+		t.Error("decoded into a struct that is not a pointer")
+	}
+}
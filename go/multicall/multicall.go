@@ -0,0 +1,70 @@
+// Package multicall is a client for the Multicall3 contract: https://github.com/mds1/multicall
+//
+// It packs multiple contract calls into a single eth_call against the Multicall3
+// contract, so a batch of reads costs one round trip and is guaranteed to be
+// executed against the same block.
+package multicall
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/bindings"
+)
+
+// Address is the canonical Multicall3 address, deployed on most EVM chains
+var Address = bindings.Address
+
+// Call represents a single call in the multicall
+type Call struct {
+	Target   common.Address `json:"target"`
+	CallData []byte         `json:"callData"`
+}
+
+// Call3 represents a single call in an aggregate3 multicall. If AllowFailure
+// is false, a revert of this call reverts the whole batch.
+type Call3 struct {
+	Target       common.Address `json:"target"`
+	AllowFailure bool           `json:"allowFailure"`
+	CallData     []byte         `json:"callData"`
+}
+
+// Call3Value represents a single call in an aggregate3Value multicall,
+// forwarding Value wei to the target. A nil Value sends no ETH.
+type Call3Value struct {
+	Target       common.Address `json:"target"`
+	AllowFailure bool           `json:"allowFailure"`
+	Value        *big.Int       `json:"value"`
+	CallData     []byte         `json:"callData"`
+}
+
+// TotalValue returns the sum of the values forwarded by calls, which must be
+// sent as the msg.value of the aggregate3Value call. It fails if any value is
+// negative or the total does not fit in a uint256.
+func TotalValue(calls []Call3Value) (*big.Int, error) {
+	total := new(big.Int)
+	for i, call := range calls {
+		if call.Value == nil {
+			continue
+		}
+		if call.Value.Sign() < 0 {
+			return nil, fmt.Errorf("multicall: call %d has negative value %s", i, call.Value)
+		}
+		total.Add(total, call.Value)
+	}
+	if total.Cmp(maxUint256) > 0 {
+		return nil, fmt.Errorf("multicall: total value %s overflows uint256", total)
+	}
+	return total, nil
+}
+
+// maxUint256 is the largest value representable as a uint256
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// Result represents the outcome of a single call, mirroring the on-chain
+// Result struct. ReturnData holds the revert data when Success is false.
+type Result struct {
+	Success    bool   `json:"success"`
+	ReturnData []byte `json:"returnData"`
+}
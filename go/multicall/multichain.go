@@ -0,0 +1,104 @@
+package multicall
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MultiChainClient executes the same batch on several chains at once, with
+// one Client per chain
+type MultiChainClient struct {
+	clients map[uint64]*Client
+}
+
+// NewMultiChainClient returns a MultiChainClient for clients, whose chain IDs
+// must be known, for example because they were created with
+// NewClientForChain, and distinct.
+func NewMultiChainClient(clients ...*Client) (*MultiChainClient, error) {
+	m := &MultiChainClient{clients: make(map[uint64]*Client, len(clients))}
+	for i, client := range clients {
+		if client.chainID == nil {
+			return nil, fmt.Errorf("multicall: client %d has no chain ID, create it with NewClientForChain", i)
+		}
+		chainID := client.chainID.Uint64()
+		if _, ok := m.clients[chainID]; ok {
+			return nil, fmt.Errorf("multicall: several clients for chain %d", chainID)
+		}
+		m.clients[chainID] = client
+	}
+	return m, nil
+}
+
+// ChainIDs returns the IDs of the chains of the client, in increasing order
+func (m *MultiChainClient) ChainIDs() []uint64 {
+	ids := make([]uint64, 0, len(m.clients))
+	for id := range m.clients {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Client returns the client for the chain with the given ID, or nil
+func (m *MultiChainClient) Client(chainID uint64) *Client {
+	return m.clients[chainID]
+}
+
+// ChainResult is the outcome of executing a batch on one chain. A failure on
+// one chain does not affect the results of the others.
+type ChainResult[T any] struct {
+	Value T
+	Err   error
+}
+
+// FanOut runs fn concurrently with the client of every chain of m and returns
+// the results keyed by chain ID:
+//
+//	balances := multicall.FanOut(ctx, m, func(ctx context.Context, chainID uint64, c *multicall.Client) (*big.Int, error) {
+//		balance := multicall.View[*big.Int](erc20ABI, "balanceOf", owner)
+//		_, err := c.NewBatch().AddCall(usdc[chainID], balance).Execute(ctx)
+//		return balance.Value(), err
+//	})
+func FanOut[T any](ctx context.Context, m *MultiChainClient, fn func(ctx context.Context, chainID uint64, client *Client) (T, error)) map[uint64]ChainResult[T] {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[uint64]ChainResult[T], len(m.clients))
+	)
+	for chainID, client := range m.clients {
+		chainID, client := chainID, client
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := fn(ctx, chainID, client)
+			if err != nil {
+				err = fmt.Errorf("chain %d: %w", chainID, err)
+			}
+			mu.Lock()
+			results[chainID] = ChainResult[T]{Value: value, Err: err}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Aggregate3 executes calls with Aggregate3 on every chain
+func (m *MultiChainClient) Aggregate3(ctx context.Context, calls []Call3, opts ...CallOption) map[uint64]ChainResult[[]Result] {
+	return FanOut(ctx, m, func(ctx context.Context, _ uint64, client *Client) ([]Result, error) {
+		return client.Aggregate3(ctx, calls, opts...)
+	})
+}
+
+// Execute builds a batch for every chain with build, which is called with the
+// chain ID so it can pick the contract addresses of the chain, and executes
+// the batches concurrently like Batch.Execute.
+func (m *MultiChainClient) Execute(ctx context.Context, build func(chainID uint64, batch *Batch), opts ...CallOption) map[uint64]ChainResult[[][]interface{}] {
+	return FanOut(ctx, m, func(ctx context.Context, chainID uint64, client *Client) ([][]interface{}, error) {
+		batch := client.NewBatch()
+		build(chainID, batch)
+		return batch.Execute(ctx, opts...)
+	})
+}
@@ -0,0 +1,200 @@
+package multicall
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// NonceReader reads the nonces of accounts, such as *ethclient.Client
+type NonceReader interface {
+	// NonceAt returns the nonce of account at the given block, or the
+	// latest block if blockNumber is nil
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	// PendingNonceAt returns the nonce of account including the
+	// transactions of the pool
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out the nonces of the transactions an account sends,
+// so that transactions sent in quick succession, or from many goroutines,
+// get consecutive nonces without waiting for each other to reach the
+// transaction pool of the node. It starts from the pending nonce of the
+// account, keeps track of the transactions it has handed out nonces to until
+// they are mined, and reports the nonces no transaction was sent with, which
+// stall every later transaction of the account. A Client uses it for the
+// transactions of the account, see WithNonceManager.
+type NonceManager struct {
+	reader  NonceReader
+	account common.Address
+
+	mu sync.Mutex
+	// synced is set once next has been read from the node
+	synced bool
+	next   uint64
+	// released holds the nonces handed out but given back, to be handed out
+	// again first
+	released []uint64
+	inFlight map[uint64]*types.Transaction
+}
+
+// NewNonceManager returns a NonceManager for the transactions of account,
+// whose nonces are read through reader
+func NewNonceManager(reader NonceReader, account common.Address) *NonceManager {
+	return &NonceManager{reader: reader, account: account, inFlight: make(map[uint64]*types.Transaction)}
+}
+
+// Account returns the account whose nonces m manages
+func (m *NonceManager) Account() common.Address {
+	return m.account
+}
+
+// Next returns the nonce of the next transaction of the account, which the
+// transaction must be sent with, or given back with Release if it is not
+// sent. Nonces given back are handed out again first, lowest first.
+func (m *NonceManager) Next(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.released) > 0 {
+		nonce := m.released[0]
+		m.released = m.released[1:]
+		return nonce, nil
+	}
+	if !m.synced {
+		pending, err := m.reader.PendingNonceAt(ctx, m.account)
+		if err != nil {
+			return 0, fmt.Errorf("multicall: read pending nonce of %s: %w", m.account, err)
+		}
+		m.next, m.synced = pending, true
+	}
+	nonce := m.next
+	m.next++
+	return nonce, nil
+}
+
+// Release gives back a nonce returned by Next that no transaction was sent
+// with, for example because signing or broadcasting it failed
+func (m *NonceManager) Release(nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.inFlight[nonce]; ok || nonce >= m.next {
+		return
+	}
+	if nonce == m.next-1 {
+		m.next--
+		// Given back nonces just below are now past the end
+		for len(m.released) > 0 && m.released[len(m.released)-1] == m.next-1 {
+			m.released = m.released[:len(m.released)-1]
+			m.next--
+		}
+		return
+	}
+	i := sort.Search(len(m.released), func(i int) bool { return m.released[i] >= nonce })
+	if i < len(m.released) && m.released[i] == nonce {
+		return
+	}
+	m.released = append(m.released, 0)
+	copy(m.released[i+1:], m.released[i:])
+	m.released[i] = nonce
+}
+
+// Track records tx as sent by the account, replacing any transaction tracked
+// with the same nonce, as a resubmission does, until it is mined
+func (m *NonceManager) Track(tx *types.Transaction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight[tx.Nonce()] = tx
+	if m.synced && tx.Nonce() >= m.next {
+		m.next = tx.Nonce() + 1
+	}
+}
+
+// forget stops tracking tx, which was not sent after all, and gives back
+// its nonce
+func (m *NonceManager) forget(tx *types.Transaction) {
+	m.mu.Lock()
+	if m.inFlight[tx.Nonce()] == tx {
+		delete(m.inFlight, tx.Nonce())
+	}
+	m.mu.Unlock()
+	m.Release(tx.Nonce())
+}
+
+// InFlight returns the tracked transactions that have not been mined as of the
+// last Sync, by nonce
+func (m *NonceManager) InFlight() []*types.Transaction {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	txs := make([]*types.Transaction, 0, len(m.inFlight))
+	for _, tx := range m.inFlight {
+		txs = append(txs, tx)
+	}
+	sort.Slice(txs, func(i, j int) bool { return txs[i].Nonce() < txs[j].Nonce() })
+	return txs
+}
+
+// NonceStatus is the state of the nonces of an account, see NonceManager.Sync
+type NonceStatus struct {
+	// Mined is the nonce of the next transaction of the account to be mined
+	Mined uint64
+	// Pending is the next nonce the node expects, past those of the
+	// transactions of its pool that can be mined in a row
+	Pending uint64
+	// Next is the next nonce the manager hands out
+	Next uint64
+	// Gaps are the nonces between Pending and Next that no tracked
+	// transaction has, in order. None of the transactions with a higher nonce
+	// can be mined until each is used; an empty SendAggregate sent with a
+	// gap's nonce fills it, and Next hands out given back nonces first.
+	Gaps []uint64
+	// Dropped is the tracked transaction with the nonce Pending, if any,
+	// which the node does not have in its pool, as after it was evicted, or
+	// nil. It can be sent again, with higher fees, with Client.Replace.
+	Dropped *types.Transaction
+}
+
+// Sync reads the nonces of the account from the node, forgets the
+// transactions that have been mined and reports the nonces that stall the
+// transactions of the account. Transactions of the account sent without the
+// manager, as by another process, move the next nonce past theirs. Pending
+// nonces are those of the node, so behind a load balancer they may lag
+// behind the transactions just sent through another node.
+func (m *NonceManager) Sync(ctx context.Context) (*NonceStatus, error) {
+	mined, err := m.reader.NonceAt(ctx, m.account, nil)
+	if err != nil {
+		return nil, fmt.Errorf("multicall: read nonce of %s: %w", m.account, err)
+	}
+	pending, err := m.reader.PendingNonceAt(ctx, m.account)
+	if err != nil {
+		return nil, fmt.Errorf("multicall: read pending nonce of %s: %w", m.account, err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for nonce := range m.inFlight {
+		if nonce < mined {
+			delete(m.inFlight, nonce)
+		}
+	}
+	released := m.released[:0]
+	for _, nonce := range m.released {
+		if nonce >= pending {
+			released = append(released, nonce)
+		}
+	}
+	m.released = released
+	if !m.synced || pending > m.next {
+		m.next, m.synced = pending, true
+	}
+	status := &NonceStatus{Mined: mined, Pending: pending, Next: m.next, Dropped: m.inFlight[pending]}
+	for nonce := pending; nonce < m.next; nonce++ {
+		if _, ok := m.inFlight[nonce]; !ok {
+			status.Gaps = append(status.Gaps, nonce)
+		}
+	}
+	return status, nil
+}
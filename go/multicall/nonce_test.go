@@ -0,0 +1,123 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// fakeNonces reads the mined and pending nonces it is set to
+type fakeNonces struct {
+	mined, pending uint64
+}
+
+func (f *fakeNonces) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return f.mined, nil
+}
+
+func (f *fakeNonces) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return f.pending, nil
+}
+
+// next hands out n nonces of m
+func next(t *testing.T, m *NonceManager, n int) []uint64 {
+	t.Helper()
+	nonces := make([]uint64, n)
+	for i := range nonces {
+		nonce, err := m.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonces[i] = nonce
+	}
+	return nonces
+}
+
+func nonceTx(nonce uint64) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{Nonce: nonce})
+}
+
+func TestNonceManagerReserve(t *testing.T) {
+	m := NewNonceManager(&fakeNonces{pending: 5}, common.Address{1})
+	if got, want := next(t, m, 3), []uint64{5, 6, 7}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got nonces %v, want %v", got, want)
+	}
+	// Given back nonces are handed out again first, lowest first, and the
+	// last one handed out moves the next nonce back
+	m.Release(6)
+	m.Release(5)
+	m.Release(7)
+	if got, want := next(t, m, 3), []uint64{5, 6, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got nonces %v after release, want %v", got, want)
+	}
+	m.Track(nonceTx(6))
+	m.Release(6)
+	if got, want := next(t, m, 1), []uint64{8}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got nonces %v after releasing a tracked nonce, want %v", got, want)
+	}
+}
+
+func TestNonceManagerForget(t *testing.T) {
+	m := NewNonceManager(&fakeNonces{pending: 0}, common.Address{1})
+	next(t, m, 2)
+	tx := nonceTx(0)
+	m.Track(tx)
+	m.Track(nonceTx(1))
+	m.forget(tx)
+	if inFlight := m.InFlight(); len(inFlight) != 1 || inFlight[0].Nonce() != 1 {
+		t.Errorf("got %d transactions in flight, want the one with nonce 1", len(inFlight))
+	}
+	if got, want := next(t, m, 2), []uint64{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got nonces %v after forgetting, want %v", got, want)
+	}
+}
+
+func TestNonceManagerSync(t *testing.T) {
+	nonces := &fakeNonces{mined: 3, pending: 3}
+	m := NewNonceManager(nonces, common.Address{1})
+	next(t, m, 4)
+	m.Track(nonceTx(3))
+	m.Track(nonceTx(5))
+
+	// Nonce 3 was mined, 4 was never sent and 5 is stuck behind it
+	nonces.mined, nonces.pending = 4, 4
+	status, err := m.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &NonceStatus{Mined: 4, Pending: 4, Next: 7, Gaps: []uint64{4, 6}}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("got status %+v, want %+v", status, want)
+	}
+	if inFlight := m.InFlight(); len(inFlight) != 1 || inFlight[0].Nonce() != 5 {
+		t.Errorf("got %d transactions in flight, want the one with nonce 5", len(inFlight))
+	}
+
+	// Transactions sent by another process move the next nonce past theirs
+	nonces.mined, nonces.pending = 5, 10
+	if status, err = m.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if status.Next != 10 || status.Dropped != nil {
+		t.Errorf("got next nonce %d and dropped %v, want 10 and none", status.Next, status.Dropped)
+	}
+}
+
+func TestNonceManagerSyncReportsDropped(t *testing.T) {
+	nonces := &fakeNonces{}
+	m := NewNonceManager(nonces, common.Address{1})
+	next(t, m, 1)
+	tx := nonceTx(0)
+	m.Track(tx)
+	status, err := m.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Dropped != tx {
+		t.Errorf("got dropped %v, want the transaction with nonce 0", status.Dropped)
+	}
+}
@@ -0,0 +1,291 @@
+package multicall
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithAddress overrides the Multicall3 address used by the client, on any
+// chain
+func WithAddress(address common.Address) Option {
+	return func(c *Client) {
+		c.address = address
+		c.addressSet = true
+	}
+}
+
+// WithDeployless makes the client execute multicalls without relying on a
+// deployed Multicall3: each eth_call is sent as a contract creation whose
+// init code deploys Multicall3 and calls it, so the client works on any EVM
+// chain. Calls cost the gas of the deployment on top of the multicall, and
+// msg.sender for the calls is the temporary contract's address.
+func WithDeployless() Option {
+	return func(c *Client) {
+		c.deployless = true
+	}
+}
+
+// WithCodeOverride makes the client inject the Multicall3 runtime code at its
+// address for the duration of every call, using an eth_call state override,
+// so that it works on chains where Multicall3 is not deployed. The calls are
+// sent through caller, typically a *gethclient.Client connected to the same
+// node, instead of the client's contract caller. The node must support state
+// overrides, and gas estimation, and so WithGasCap, is not available.
+func WithCodeOverride(caller OverrideCaller) Option {
+	return func(c *Client) {
+		c.overrider = caller
+		c.injectCode = true
+	}
+}
+
+// WithOverrideCaller sets the caller used for calls with state overrides,
+// see OverrideState, typically a *gethclient.Client connected to the same
+// node as the client's contract caller. The node must support state
+// overrides.
+func WithOverrideCaller(caller OverrideCaller) Option {
+	return func(c *Client) {
+		c.overrider = caller
+	}
+}
+
+// WithRPCClient sets the RPC client used for the JSON-RPC methods the
+// client's contract caller does not expose, such as eth_feeHistory. It
+// defaults to the RPC client of the contract caller when it has one, as an
+// *ethclient.Client does.
+func WithRPCClient(client *rpc.Client) Option {
+	return func(c *Client) {
+		c.rpc = client
+	}
+}
+
+// WithTransactor sets the transactor SendAggregate completes transactions
+// with and broadcasts them through, such as an *ethclient.Client
+// connected to a private transaction RPC. It defaults to the client's contract
+// caller when it can send transactions, as an *ethclient.Client does.
+func WithTransactor(transactor bind.ContractTransactor) Option {
+	return func(c *Client) {
+		c.transactor = transactor
+	}
+}
+
+// WithNonceManager makes the transactions the client sends from the account
+// of nonces take their nonce from it, instead of the pending nonce the node
+// reports for each of them, so that transactions sent in quick succession do
+// not reuse each other's nonce. Transactions sent with a nonce of their own
+// are tracked by it all the same.
+func WithNonceManager(nonces *NonceManager) Option {
+	return func(c *Client) {
+		c.nonces = nonces
+	}
+}
+
+// WithDryRun makes the client simulate every transaction it sends with an
+// eth_call of the same calldata, sender, value and gas limit before signing
+// it, and abort unless the simulation succeeds. When a call that may not fail
+// would revert, the transaction fails with a *DryRunError giving the reason
+// of every such call, instead of being broadcast only to revert on chain.
+func WithDryRun() Option {
+	return func(c *Client) {
+		c.dryRun = true
+	}
+}
+
+// WithResultCache makes the client reuse the results cache holds for calls of
+// effectively immutable methods, such as decimals, instead of executing them
+// again, and cache those it executes, see NewResultCache. Results are reused
+// whatever the block the calls are made at, except by calls with state or
+// block overrides or another sender. Clients of different chains must not
+// share a cache.
+func WithResultCache(cache *ResultCache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithMaxRPCBatch limits the number of requests the client sends in a single
+// JSON-RPC batch, such as the requests of a Plan, to what the provider
+// accepts. Zero, the default, means no limit.
+func WithMaxRPCBatch(n int) Option {
+	return func(c *Client) {
+		c.maxRPCBatch = n
+	}
+}
+
+// WithCCIPRead makes Aggregate3, and so batches, follow EIP-3668 offchain
+// lookups: when a call reverts with OffchainLookup, the data it asks for is
+// fetched from the gateways it lists with client, http.DefaultClient if nil,
+// and the result of the call is the result of its callback. This is needed
+// by ENS wildcard resolution and other CCIP-Read contracts. Calls are then
+// all sent allowed to fail, and those not allowed to fail are checked once
+// their lookups are resolved. Older contract versions are not supported.
+func WithCCIPRead(client *http.Client) Option {
+	return func(c *Client) {
+		if client == nil {
+			client = http.DefaultClient
+		}
+		c.ccip = &ccipReader{client: client}
+	}
+}
+
+// WithSimulate makes the client execute aggregate3 and aggregate3Value
+// multicalls, and so batches, with eth_simulateV1 instead of an eth_call of
+// Multicall3, on nodes that support it, so that it works whether or not
+// Multicall3 is deployed. Each call is simulated as a transaction of its own,
+// from the client's address like the calls of Multicall3, in a block on top
+// of the selected one, whose Multicall3 helpers thus report the next block
+// number. Batches are split into simulations of 500 calls unless WithMaxCalls
+// says otherwise. The first time the node rejects eth_simulateV1 as unknown,
+// the client goes on for good to the next backend, see WithCallMany, or to
+// Multicall3. It needs an RPC client, see WithRPCClient.
+func WithSimulate() Option {
+	return func(c *Client) {
+		c.backends = append(c.backends, &nodeBackend{method: "eth_simulateV1", execute: simulate})
+	}
+}
+
+// WithCallMany makes the client execute multicalls like WithSimulate, but
+// with the eth_callMany method of Erigon and Reth, or trace_callMany on
+// nodes without it, on the state at the end of the selected block, whose
+// Multicall3 helpers report its own number as with eth_call. Calls with
+// overrides the method does not take go through the next backend. Given
+// along with WithSimulate, the client uses the first method the node
+// supports, in the order of the options.
+func WithCallMany() Option {
+	return func(c *Client) {
+		c.backends = append(c.backends,
+			&nodeBackend{method: "eth_callMany", execute: callMany},
+			&nodeBackend{method: "trace_callMany", execute: traceCallMany},
+		)
+	}
+}
+
+// WithChainID sets the ID of the chain the client is meant for. The client
+// then refuses to work with a node on another chain, such as a testnet RPC in
+// a mainnet configuration: NewClientForChain and Client.VerifyChain fail if
+// the node reports a different chain ID.
+func WithChainID(chainID uint64) Option {
+	return func(c *Client) {
+		c.expectedChainID = chainID
+	}
+}
+
+// WithChainAddress sets the Multicall3 address used by NewClientForChain on
+// the chain with the given ID, taking precedence over the known deployments.
+// It is meant for private networks and forks where Multicall3 is deployed at
+// a non-canonical address, and can be given once per chain.
+func WithChainAddress(chainID uint64, address common.Address) Option {
+	return func(c *Client) {
+		if c.chainAddresses == nil {
+			c.chainAddresses = make(map[uint64]common.Address)
+		}
+		c.chainAddresses[chainID] = address
+	}
+}
+
+// WithMaxCalls limits the number of calls sent in a single multicall. Larger
+// batches are split into several multicalls whose results are stitched back
+// together in order. Zero, the default, means no limit.
+func WithMaxCalls(n int) Option {
+	return func(c *Client) {
+		c.maxCalls = n
+	}
+}
+
+// WithMaxCalldataSize limits the size in bytes of the calldata sent in a
+// single multicall, splitting larger batches like WithMaxCalls. A call whose
+// own calldata exceeds the limit is sent in a multicall of its own. Zero, the
+// default, means no limit.
+func WithMaxCalldataSize(n int) Option {
+	return func(c *Client) {
+		c.maxCalldataSize = n
+	}
+}
+
+// WithAdaptiveChunking makes the client adapt the number of calls sent in a
+// single multicall to what the provider accepts. Batches start out split
+// into chunks of maxCalls calls; whenever a chunk fails because the request
+// or response is too large or times out, the chunk size is halved, down to
+// minCalls, and the chunk is retried. Every successful chunk grows the size
+// back by a quarter, up to maxCalls. The size is shared by all calls made
+// through the client.
+func WithAdaptiveChunking(minCalls, maxCalls int) Option {
+	return func(c *Client) {
+		c.adaptive = &adaptiveLimit{min: minCalls, max: maxCalls, current: maxCalls}
+	}
+}
+
+// WithConcurrency lets the client execute up to n chunks of a split batch at
+// the same time. Results are still returned in the order of the calls. The
+// default of 1 executes chunks one after the other.
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		c.concurrency = n
+	}
+}
+
+// WithChunkTimeout limits the time each chunk of a split batch may take,
+// including its retries, so that a slow chunk fails on its own instead of
+// using up the deadline of the whole batch. Zero, the default, means no
+// limit other than the context's.
+func WithChunkTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.chunkTimeout = timeout
+	}
+}
+
+// WithDeadlineBudget divides the time left before the deadline of a batch's
+// context evenly between the chunks that remain to be executed, taking the
+// concurrency into account, so that one slow chunk cannot consume the budget
+// of the others. Time a chunk does not use goes to the chunks after it. The
+// share of a chunk is further limited by WithChunkTimeout, if set.
+func WithDeadlineBudget() Option {
+	return func(c *Client) {
+		c.budgetDeadline = true
+	}
+}
+
+// DefaultGasCap is the eth_call gas cap most nodes enforce by default
+const DefaultGasCap = 50_000_000
+
+// WithGasCap makes the client estimate the gas of every multicall before
+// sending it, splitting multicalls estimated to need more than gasCap in half
+// until each part fits. Use DefaultGasCap unless the provider is known to
+// enforce a different cap. The contract caller must also implement
+// ethereum.GasEstimator. Zero, the default, disables estimation, except on
+// chains whose profile has a gas cap of its own, see NewClientForChain.
+func WithGasCap(gasCap uint64) Option {
+	return func(c *Client) {
+		c.gasCap = gasCap
+		c.gasCapSet = true
+	}
+}
+
+// WithPooledBuffers makes the client encode multicalls into buffers that are
+// reused across calls, so the calldata of a multicall is not allocated anew
+// each time. This cuts the bytes allocated when issuing many multicalls, but
+// not the number of allocations, most of which come from decoding the
+// results. The contract caller must not retain the calldata of a call after
+// CallContract or EstimateGas returns; ethclient.Client does not.
+func WithPooledBuffers() Option {
+	return func(c *Client) {
+		c.pooled = true
+	}
+}
+
+// WithVersion sets the version of the multicall contract at the client's
+// address, for contracts older than Multicall3. Aggregate3 and the other
+// methods the version lacks are emulated with the methods it has: calls
+// allowed to fail need Multicall2's tryAggregate and calls sending value are
+// not supported. Use Client.DetectVersion to find out the version instead.
+func WithVersion(version Version) Option {
+	return func(c *Client) {
+		c.version = version
+	}
+}
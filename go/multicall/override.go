@@ -0,0 +1,85 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+)
+
+// OverrideCaller is implemented by contract callers that can execute calls
+// with state overrides, such as *gethclient.Client
+type OverrideCaller interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int, overrides *map[common.Address]gethclient.OverrideAccount) ([]byte, error)
+}
+
+// runtimeCode is the Multicall3 runtime bytecode, which its creation code
+// copies from offset 0x20 of itself
+var runtimeCode = creationCode[0x20:]
+
+// blockOverrideCaller is implemented by override callers that can also
+// override the block context of calls, such as *gethclient.Client
+type blockOverrideCaller interface {
+	CallContractWithBlockOverrides(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int, overrides *map[common.Address]gethclient.OverrideAccount, blockOverrides gethclient.BlockOverrides) ([]byte, error)
+}
+
+// blockHashCaller is implemented by contract callers that can execute calls
+// against a block given by its hash, such as *ethclient.Client
+type blockHashCaller interface {
+	CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error)
+}
+
+// callContract executes msg with opts through the client's contract caller,
+// or through its override caller when the call has state or block overrides. The
+// Multicall3 runtime code is injected at the client's address if the client
+// was configured with WithCodeOverride, and at the sender of a call made with
+// From, unless the overrides replace it.
+func (c *Client) callContract(ctx context.Context, msg ethereum.CallMsg, opts *callOptions) ([]byte, error) {
+	overridden := c.injectCode || len(opts.overrides) > 0 || opts.blockOverrides != nil || opts.from != nil
+	if opts.blockHash != nil {
+		caller, ok := c.caller.(blockHashCaller)
+		if !ok || overridden {
+			return nil, errors.New("contract caller cannot execute calls at a block hash")
+		}
+		return caller.CallContractAtHash(ctx, msg, *opts.blockHash)
+	}
+	if !overridden {
+		return c.caller.CallContract(ctx, msg, opts.block)
+	}
+	if c.overrider == nil {
+		return nil, errors.New("state overrides require an override caller, see WithOverrideCaller")
+	}
+	if opts.blockOverrides != nil {
+		caller, ok := c.overrider.(blockOverrideCaller)
+		if !ok {
+			return nil, errors.New("override caller cannot override the block context")
+		}
+		return caller.CallContractWithBlockOverrides(ctx, msg, opts.block, c.stateOverride(opts), *opts.blockOverrides)
+	}
+	return c.overrider.CallContract(ctx, msg, opts.block, c.stateOverride(opts))
+}
+
+// stateOverride returns the state overrides of a call with opts
+func (c *Client) stateOverride(opts *callOptions) *StateOverride {
+	overrides := make(StateOverride, len(opts.overrides)+1)
+	for account, override := range opts.overrides {
+		overrides[account] = override
+	}
+	inject := func(account common.Address) {
+		override := overrides[account]
+		if override.Code == nil {
+			override.Code = runtimeCode
+		}
+		overrides[account] = override
+	}
+	if c.injectCode {
+		inject(c.address)
+	}
+	if opts.from != nil {
+		inject(*opts.from)
+	}
+	return &overrides
+}
@@ -0,0 +1,93 @@
+package multicall
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/john-na4/multicall3/go/internal/fake"
+)
+
+// evmCaller executes calls with state overrides in an EVM, against a state
+// in which only funded has a balance, of 1 ETH
+type evmCaller struct {
+	state *state.StateDB
+	// overrides are those of the last call
+	overrides map[common.Address]gethclient.OverrideAccount
+}
+
+var funded = common.Address{0x77}
+
+func newEVMCaller(t *testing.T) *evmCaller {
+	t.Helper()
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statedb.SetBalance(funded, big.NewInt(1e18))
+	return &evmCaller{state: statedb}
+}
+
+func (c *evmCaller) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int, overrides *map[common.Address]gethclient.OverrideAccount) ([]byte, error) {
+	statedb := c.state.Copy()
+	if overrides != nil {
+		c.overrides = *overrides
+		for account, override := range *overrides {
+			if override.Code != nil {
+				statedb.SetCode(account, override.Code)
+			}
+		}
+	}
+	output, _, err := runtime.Call(*msg.To, msg.Data, &runtime.Config{State: statedb, Origin: msg.From, GasLimit: 30_000_000})
+	return output, err
+}
+
+func TestCodeOverride(t *testing.T) {
+	evm := newEVMCaller(t)
+	client, err := NewClient(fake.NewCaller(nil), WithCodeOverride(evm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	balanceOf, err := ABI.Pack("getEthBalance", funded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Multicall3 calls itself at the address its code is injected at
+	results, err := client.Aggregate3(context.Background(), []Call3{{Target: Address, CallData: balanceOf}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := new(big.Int).SetBytes(results[0].ReturnData); !results[0].Success || got.Int64() != 1e18 {
+		t.Errorf("got balance %s, want 1e18", got)
+	}
+	if code := evm.overrides[Address].Code; !bytes.Equal(code, runtimeCode) {
+		t.Errorf("injected %d bytes of code, want the %d of the runtime code", len(code), len(runtimeCode))
+	}
+
+	// Code given with the overrides of a call is not replaced
+	own := []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+	_, err = client.Aggregate3(context.Background(), []Call3{{Target: Address, CallData: balanceOf}}, OverrideState(StateOverride{Address: {Code: own}}))
+	if err == nil {
+		t.Error("multicall executed against code returning nothing")
+	}
+	if code := evm.overrides[Address].Code; !bytes.Equal(code, own) {
+		t.Errorf("got code %x at the client's address, want %x", code, own)
+	}
+}
+
+func TestCodeOverrideOptions(t *testing.T) {
+	if _, err := NewClient(fake.NewCaller(nil), WithCodeOverride(nil)); err == nil {
+		t.Error("client created with a nil override caller")
+	}
+	if _, err := NewClient(fake.NewCaller(nil), WithCodeOverride(newEVMCaller(t)), WithDeployless()); err == nil {
+		t.Error("client created with both a code override and deployless mode")
+	}
+}
@@ -0,0 +1,48 @@
+package multicall
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// IncompleteError is returned by batches executed with PartialResults when
+// their deadline expires before every call was executed. The results of the
+// calls that were executed are returned along with it, while the results of
+// the missing calls are left as zero values.
+type IncompleteError struct {
+	// Missing holds the indexes of the calls that were not executed, in
+	// increasing order
+	Missing []int
+	// Err is the error the first missing chunk failed with
+	Err error
+}
+
+func (e *IncompleteError) Error() string {
+	return fmt.Sprintf("multicall: %d calls not executed: %v", len(e.Missing), e.Err)
+}
+
+func (e *IncompleteError) Unwrap() error {
+	return e.Err
+}
+
+// Missed reports whether the call with index i was not executed
+func (e *IncompleteError) Missed(i int) bool {
+	j := sort.SearchInts(e.Missing, i)
+	return j < len(e.Missing) && e.Missing[j] == i
+}
+
+// incomplete returns err as an *IncompleteError, or nil if it is not one
+func incomplete(err error) *IncompleteError {
+	var incompleteErr *IncompleteError
+	if errors.As(err, &incompleteErr) {
+		return incompleteErr
+	}
+	return nil
+}
+
+// missed reports whether the call with index i was not executed according to
+// incompleteErr, which may be nil
+func missed(incompleteErr *IncompleteError, i int) bool {
+	return incompleteErr != nil && incompleteErr.Missed(i)
+}
@@ -0,0 +1,257 @@
+package multicall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/john-na4/multicall3/go/internal/helper"
+)
+
+// Plan collects contract calls, aggregated into multicalls like the calls of
+// a batch, along with the data Multicall3 cannot read, such as nonces, code
+// and receipts, which are fetched with a JSON-RPC batch sent at the same
+// time. Each value is declared once and read from its handle once the plan
+// has been executed:
+//
+//	plan := client.NewPlan()
+//	supply := multicall.View[*big.Int](erc20ABI, "totalSupply")
+//	plan.Batch().AddCall(token, supply)
+//	nonce, balance := plan.Nonce(wallet), plan.Balance(wallet)
+//	receipt := plan.Receipt(txHash)
+//	err := plan.Execute(ctx)
+//
+// The JSON-RPC requests need an RPC client, see WithRPCClient.
+type Plan struct {
+	batch    *Batch
+	requests []planRequest
+	// resolvers complete the handles of values read by the batch once it
+	// has been executed
+	resolvers []func(err error)
+}
+
+// planRequest is a JSON-RPC request of a plan
+type planRequest struct {
+	method string
+	args   []interface{}
+	// atBlock requests take the block of the plan as their last argument
+	atBlock bool
+	result  json.RawMessage
+	decode  func(result json.RawMessage) error
+	fail    func(err error)
+}
+
+// Planned is a value of a plan, available once the plan has been executed
+type Planned[T any] struct {
+	value T
+	err   error
+}
+
+// Get returns the value, or the error that prevented it from being read
+func (p *Planned[T]) Get() (T, error) {
+	return p.value, p.err
+}
+
+// Value returns the value, or the zero value of T if it could not be read
+func (p *Planned[T]) Value() T {
+	return p.value
+}
+
+// NewPlan returns an empty Plan executed through c
+func (c *Client) NewPlan() *Plan {
+	return &Plan{batch: c.NewBatch()}
+}
+
+// Batch returns the batch of the contract calls of the plan, to which calls
+// are added as to any batch
+func (p *Plan) Batch() *Batch {
+	return p.batch
+}
+
+// AddRequest appends a JSON-RPC request of method with args to p, whose result
+// is decoded as T with encoding/json. Requests that take a block should be
+// given it explicitly, or made with one of the methods of Plan, which use
+// the block of the plan.
+func AddRequest[T any](p *Plan, method string, args ...interface{}) *Planned[T] {
+	return addRequest(p, method, args, false, func(result json.RawMessage) (T, error) {
+		var value T
+		err := json.Unmarshal(result, &value)
+		return value, err
+	})
+}
+
+// addRequest appends a request whose result is converted to T with convert
+func addRequest[T any](p *Plan, method string, args []interface{}, atBlock bool, convert func(json.RawMessage) (T, error)) *Planned[T] {
+	planned := &Planned[T]{err: errNotExecuted}
+	p.requests = append(p.requests, planRequest{
+		method:  method,
+		args:    args,
+		atBlock: atBlock,
+		decode: func(result json.RawMessage) error {
+			value, err := convert(result)
+			if err != nil {
+				return err
+			}
+			planned.value, planned.err = value, nil
+			return nil
+		},
+		fail: func(err error) {
+			var zero T
+			planned.value, planned.err = zero, err
+		},
+	})
+	return planned
+}
+
+// Nonce appends a request of the nonce of account at the block of the plan,
+// the number of transactions it has sent
+func (p *Plan) Nonce(account common.Address) *Planned[uint64] {
+	return addRequest(p, "eth_getTransactionCount", []interface{}{account}, true, func(result json.RawMessage) (uint64, error) {
+		var nonce hexutil.Uint64
+		err := json.Unmarshal(result, &nonce)
+		return uint64(nonce), err
+	})
+}
+
+// Code appends a request of the code of account at the block of the plan
+func (p *Plan) Code(account common.Address) *Planned[[]byte] {
+	return addRequest(p, "eth_getCode", []interface{}{account}, true, func(result json.RawMessage) ([]byte, error) {
+		var code hexutil.Bytes
+		err := json.Unmarshal(result, &code)
+		return code, err
+	})
+}
+
+// Receipt appends a request of the receipt of the transaction with the given
+// hash, whose error is ethereum.NotFound while it is pending or unknown
+func (p *Plan) Receipt(hash common.Hash) *Planned[*types.Receipt] {
+	return addRequest(p, "eth_getTransactionReceipt", []interface{}{hash}, false, func(result json.RawMessage) (*types.Receipt, error) {
+		var receipt *types.Receipt
+		if err := json.Unmarshal(result, &receipt); err != nil {
+			return nil, err
+		}
+		if receipt == nil {
+			return nil, ethereum.NotFound
+		}
+		return receipt, nil
+	})
+}
+
+// Balance appends a read of the native balance of account, in wei, at the
+// block of the plan. It is a call of getEthBalance, see Batch.AddEthBalance,
+// or an eth_getBalance request in deployless mode, where getEthBalance is not
+// available.
+func (p *Plan) Balance(account common.Address) *Planned[*big.Int] {
+	if p.batch.client.deployless {
+		return addRequest(p, "eth_getBalance", []interface{}{account}, true, func(result json.RawMessage) (*big.Int, error) {
+			var balance hexutil.Big
+			err := json.Unmarshal(result, &balance)
+			return balance.ToInt(), err
+		})
+	}
+	call := View[*big.Int](ABI, "getEthBalance", account)
+	p.batch.AddSelf(call)
+	planned := &Planned[*big.Int]{err: errNotExecuted}
+	p.resolvers = append(p.resolvers, func(err error) {
+		planned.value, planned.err = call.Get()
+		if err != nil && planned.err == errNotExecuted {
+			planned.err = err
+		}
+	})
+	return planned
+}
+
+// Execute executes the contract calls of the plan with opts and sends its
+// JSON-RPC requests at the same time. When the plan has both contract calls
+// and requests that take a block, the block selected by opts is resolved to
+// a number first, so that they all read the same block. State and block
+// overrides only apply to the contract calls.
+//
+// The error is set if the multicalls of the contract calls failed, or if the
+// JSON-RPC batch could not be sent. The error of a single call or request
+// only sets the error of its value.
+func (p *Plan) Execute(ctx context.Context, opts ...CallOption) error {
+	c := p.batch.client
+	o := newCallOptions(opts)
+	if o.err != nil {
+		return o.err
+	}
+	var client *rpc.Client
+	if len(p.requests) > 0 {
+		var err error
+		if client, err = c.rpcClient("Plan"); err != nil {
+			return err
+		}
+	}
+	if p.batch.Len() > 0 && p.hasBlockRequests() {
+		if err := c.pinBlock(ctx, o); err != nil {
+			return err
+		}
+		if o.blockHash == nil && o.block != nil {
+			opts = append(opts[:len(opts):len(opts)], AtBlock(o.block))
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.send(ctx, client, o)
+	}()
+	_, err := p.batch.ExecuteResults(ctx, opts...)
+	for _, resolve := range p.resolvers {
+		resolve(err)
+	}
+	return helper.JoinErrors(err, <-done)
+}
+
+func (p *Plan) hasBlockRequests() bool {
+	for _, request := range p.requests {
+		if request.atBlock {
+			return true
+		}
+	}
+	return false
+}
+
+// send sends the JSON-RPC requests of the plan and decodes their results
+func (p *Plan) send(ctx context.Context, client *rpc.Client, opts *callOptions) error {
+	if len(p.requests) == 0 {
+		return nil
+	}
+	block := blockArg(opts)
+	batch := make([]rpc.BatchElem, len(p.requests))
+	for i := range p.requests {
+		request := &p.requests[i]
+		args := request.args
+		if request.atBlock {
+			args = append(args[:len(args):len(args)], block)
+		}
+		batch[i] = rpc.BatchElem{Method: request.method, Args: args, Result: &request.result}
+	}
+	if err := p.batch.client.batchCall(ctx, client, batch); err != nil {
+		err = fmt.Errorf("multicall: send JSON-RPC batch: %w", err)
+		for _, request := range p.requests {
+			request.fail(err)
+		}
+		return err
+	}
+	for i, request := range p.requests {
+		if batch[i].Error != nil {
+			request.fail(fmt.Errorf("multicall: %s: %w", request.method, classify(batch[i].Error)))
+			continue
+		}
+		switch err := request.decode(request.result); {
+		case errors.Is(err, ethereum.NotFound):
+			request.fail(fmt.Errorf("multicall: %s: %w", request.method, err))
+		case err != nil:
+			request.fail(withKind(ErrDecode, fmt.Errorf("multicall: decode %s result: %w", request.method, err)))
+		}
+	}
+	return nil
+}
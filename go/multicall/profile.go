@@ -0,0 +1,189 @@
+package multicall
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// GasProfile is the gas used by the calls of a batch, see Batch.Profile
+type GasProfile struct {
+	// GasUsed is the gas used by the multicalls of the batch, including the
+	// gas of their transactions and of Multicall3 itself
+	GasUsed uint64
+	// Calls are the calls of the batch, in the order they were added
+	Calls []CallGas
+}
+
+// CallGas is the gas used by a call of a batch
+type CallGas struct {
+	// Index is the position of the call in the batch
+	Index  int
+	Target common.Address
+	Method string
+	Key    string
+	// GasUsed is the gas used by the call, including the calls it made
+	GasUsed uint64
+	// Success is false if the call reverted
+	Success bool
+}
+
+// Top returns the n calls that used the most gas, most expensive first, or
+// all of them if there are fewer
+func (p *GasProfile) Top(n int) []CallGas {
+	calls := append([]CallGas(nil), p.Calls...)
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].GasUsed > calls[j].GasUsed
+	})
+	if n < len(calls) {
+		calls = calls[:n]
+	}
+	return calls
+}
+
+// Profile executes the batch with debug_traceCall and its callTracer instead
+// of eth_call, in the multicalls it would be split into, and attributes the
+// gas they used to each call, to find the calls that dominate the cost of a
+// batch. Calls are not deduplicated, and their results are not decoded. It
+// needs a node that exposes the debug namespace, through the RPC client of
+// the client, see WithRPCClient.
+func (b *Batch) Profile(ctx context.Context, opts ...CallOption) (*GasProfile, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	c := b.client
+	if err := c.checkVersion("aggregate3"); err != nil {
+		return nil, err
+	}
+	calls := make([]profileCall, len(b.calls))
+	for i, call := range b.calls {
+		calls[i] = profileCall{index: i, call: Call3{Target: call.target, AllowFailure: call.allowFailure, CallData: call.callData}}
+	}
+	var gasUsed atomic.Uint64
+	profiled, err := chunked(ctx, c, calls, opts, methodSize, func(call profileCall) int { return call3Size(call.call) }, func(ctx context.Context, opts *callOptions, chunk []profileCall) ([]CallGas, error) {
+		frames, used, err := c.traceAggregate3(ctx, opts, chunk)
+		if err != nil {
+			return nil, err
+		}
+		gasUsed.Add(used)
+		gas := make([]CallGas, len(chunk))
+		for i, sub := range frames {
+			call := b.calls[chunk[i].index]
+			gas[i] = CallGas{
+				Index:   chunk[i].index,
+				Target:  call.target,
+				Method:  call.method,
+				Key:     call.key,
+				GasUsed: uint64(sub.GasUsed),
+				Success: sub.Error == "",
+			}
+		}
+		return gas, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &GasProfile{GasUsed: gasUsed.Load(), Calls: profiled}, nil
+}
+
+// profileCall is a call of Batch.Profile, along with its position in the
+// batch
+type profileCall struct {
+	index int
+	call  Call3
+}
+
+// callFrame is a call traced by the callTracer of debug_traceCall and
+// debug_traceTransaction
+type callFrame struct {
+	Type         string          `json:"type"`
+	To           *common.Address `json:"to"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Error        string          `json:"error"`
+	RevertReason string          `json:"revertReason"`
+	Calls        []callFrame     `json:"calls"`
+}
+
+// traceCallArgs are the transaction arguments of debug_traceCall
+type traceCallArgs struct {
+	From                 *common.Address `json:"from,omitempty"`
+	To                   *common.Address `json:"to"`
+	Data                 hexutil.Bytes   `json:"data"`
+	Gas                  *hexutil.Uint64 `json:"gas,omitempty"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+}
+
+// traceCallConfig is the configuration of debug_traceCall
+type traceCallConfig struct {
+	Tracer         string         `json:"tracer"`
+	StateOverrides *StateOverride `json:"stateOverrides,omitempty"`
+	BlockOverrides *BlockOverride `json:"blockOverrides,omitempty"`
+}
+
+// traceAggregate3 traces an aggregate3 multicall of chunk with the
+// callTracer and returns the frames of the calls of chunk, along with the
+// gas used by the whole multicall
+func (c *Client) traceAggregate3(ctx context.Context, opts *callOptions, chunk []profileCall) ([]callFrame, uint64, error) {
+	client, err := c.rpcClient("Profile")
+	if err != nil {
+		return nil, 0, err
+	}
+	calls := make([]Call3, len(chunk))
+	for i, call := range chunk {
+		calls[i] = call.call
+	}
+	args := traceCallArgs{
+		To:                   &c.address,
+		Data:                 AppendAggregate3(nil, calls),
+		GasPrice:             (*hexutil.Big)(opts.gasPrice),
+		MaxFeePerGas:         (*hexutil.Big)(opts.gasFeeCap),
+		MaxPriorityFeePerGas: (*hexutil.Big)(opts.gasTipCap),
+	}
+	if opts.gas != 0 {
+		args.Gas = (*hexutil.Uint64)(&opts.gas)
+	}
+	if opts.from != nil {
+		// The multicall runs at the sender, see From
+		args.From, args.To = opts.from, opts.from
+	}
+	if c.deployless {
+		args.To, args.Data = nil, deploylessData(args.Data)
+	}
+	config := traceCallConfig{Tracer: "callTracer", BlockOverrides: opts.blockOverrides}
+	if c.injectCode || len(opts.overrides) > 0 || opts.from != nil {
+		config.StateOverrides = c.stateOverride(opts)
+	}
+	var frame callFrame
+	if err := client.CallContext(ctx, &frame, "debug_traceCall", args, blockArg(opts), config); err != nil {
+		return nil, 0, fmt.Errorf("multicall: trace aggregate3: %w", classify(err))
+	}
+	top := &frame
+	if c.deployless {
+		// The init code deploys Multicall3, then calls it
+		top = nil
+		for i := range frame.Calls {
+			if frame.Calls[i].Type == "CALL" {
+				top = &frame.Calls[i]
+			}
+		}
+		if top == nil {
+			return nil, 0, fmt.Errorf("multicall: trace aggregate3: deployless multicall failed: %s", frame.Error)
+		}
+	}
+	if top.Error != "" {
+		return nil, 0, withKind(ErrExecutionReverted, fmt.Errorf("multicall: trace aggregate3: %s", top.Error))
+	}
+	if len(top.Calls) != len(chunk) {
+		return nil, 0, fmt.Errorf("multicall: trace aggregate3: got %d calls for %d", len(top.Calls), len(chunk))
+	}
+	// The gas of the transaction is only reported by the outermost frame
+	return top.Calls, uint64(frame.GasUsed), nil
+}
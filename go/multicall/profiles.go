@@ -0,0 +1,39 @@
+package multicall
+
+import "github.com/ethereum/go-ethereum/common"
+
+// ChainProfile describes how the client should talk to Multicall3 on a chain
+type ChainProfile struct {
+	// Address is where Multicall3 is deployed on the chain
+	Address common.Address
+	// GasCap is the gas cap applied by NewClientForChain on chains whose gas
+	// accounting differs from Ethereum's, or zero if the chain needs none
+	GasCap uint64
+}
+
+// zkStackGasCap is the maximum gas of a transaction, and of an eth_call, on
+// zkSync Era and other ZK Stack chains. Gas there also pays for publishing
+// state diffs to L1, so estimates are much higher than on other chains and
+// more gas is allowed per call.
+const zkStackGasCap = 80_000_000
+
+// chainGasCaps are the gas caps of chains whose gas semantics differ from
+// Ethereum's
+var chainGasCaps = map[uint64]uint64{
+	324:     zkStackGasCap, // zkSync Era
+	280:     zkStackGasCap, // zkSync Era Goerli Testnet
+	300:     zkStackGasCap, // zkSync Era Sepolia Testnet
+	2741:    zkStackGasCap, // Abstract
+	11124:   zkStackGasCap, // Abstract Testnet
+	1612127: zkStackGasCap, // PlayFi Albireo Testnet
+}
+
+// ProfileForChain returns the profile of the chain with the given ID. It
+// reports false if no Multicall3 deployment is known on the chain.
+func ProfileForChain(chainID uint64) (ChainProfile, bool) {
+	address, ok := AddressForChain(chainID)
+	if !ok {
+		return ChainProfile{}, false
+	}
+	return ChainProfile{Address: address, GasCap: chainGasCaps[chainID]}, true
+}
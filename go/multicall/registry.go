@@ -0,0 +1,137 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//go:generate go run ../internal/cmd/gendeployments -in ../../deployments.json -out deployments.go
+
+// Deployment is a Multicall3 deployment listed in deployments.json, at the
+// root of the repository
+type Deployment struct {
+	Name    string
+	ChainID uint64
+	Address common.Address
+	// URL links to the contract, or its deployment, on a block explorer
+	URL string
+}
+
+// Deployments returns all known Multicall3 deployments, in the order they are
+// listed in deployments.json
+func Deployments() []Deployment {
+	return append([]Deployment(nil), deployments...)
+}
+
+// DeploymentsForChain returns the known Multicall3 deployments on the chain
+// with the given ID. A few chain IDs are shared by several chains, so there
+// may be more than one.
+func DeploymentsForChain(chainID uint64) []Deployment {
+	var found []Deployment
+	for _, deployment := range deployments {
+		if deployment.ChainID == chainID {
+			found = append(found, deployment)
+		}
+	}
+	return found
+}
+
+// chainAddresses indexes the addresses of deployments by chain ID. Chains
+// sharing an ID all have Multicall3 at the same address.
+var chainAddresses = func() map[uint64]common.Address {
+	addresses := make(map[uint64]common.Address, len(deployments))
+	for _, deployment := range deployments {
+		if _, ok := addresses[deployment.ChainID]; !ok {
+			addresses[deployment.ChainID] = deployment.Address
+		}
+	}
+	return addresses
+}()
+
+// AddressForChain returns the address Multicall3 is deployed at on the chain
+// with the given ID. It reports false if no deployment is known on the chain.
+func AddressForChain(chainID uint64) (common.Address, bool) {
+	address, ok := chainAddresses[chainID]
+	return address, ok
+}
+
+// ChainIDReader is implemented by contract callers that can report the ID of
+// the chain they are connected to, such as *ethclient.Client
+type ChainIDReader interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// NewClientForChain returns a Client like NewClient, configured for the chain
+// caller is connected to. The chain ID is queried with eth_chainId and, if
+// WithChainID is given, verified. It is then used to pick the Multicall3
+// address: an address given with WithAddress is used on any chain, then
+// addresses given with WithChainAddress, and otherwise the deployment found
+// by AddressForChain. Clients that do not need a deployment, see
+// WithDeployless and WithCodeOverride, also work on any other chain. Unless
+// WithGasCap is given, the gas cap of the chain's profile is applied if it
+// has one and caller can estimate gas.
+func NewClientForChain(ctx context.Context, caller ethereum.ContractCaller, opts ...Option) (*Client, error) {
+	c, err := NewClient(caller, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.VerifyChain(ctx); err != nil {
+		return nil, err
+	}
+	chainID := c.chainID.Uint64()
+	profile, known := ProfileForChain(chainID)
+	if !c.gasCapSet && !c.injectCode && profile.GasCap > 0 {
+		if estimator, ok := caller.(ethereum.GasEstimator); ok {
+			c.gasCap, c.estimator = profile.GasCap, estimator
+		}
+	}
+	if c.addressSet {
+		return c, nil
+	}
+	if address, ok := c.chainAddresses[chainID]; ok {
+		c.address = address
+		return c, nil
+	}
+	if known {
+		c.address = profile.Address
+		return c, nil
+	}
+	if !c.deployless && !c.injectCode {
+		return nil, fmt.Errorf("%w: no known deployment on chain %d", ErrMulticallNotDeployed, chainID)
+	}
+	return c, nil
+}
+
+// VerifyChain queries the ID of the chain the client's contract caller is
+// connected to, failing if it differs from the chain given with WithChainID,
+// and records it as the client's chain ID. NewClientForChain calls it, so it
+// is only needed for clients created with NewClient.
+func (c *Client) VerifyChain(ctx context.Context) error {
+	reader, ok := c.caller.(ChainIDReader)
+	if !ok {
+		return errors.New("multicall: contract caller cannot report its chain ID")
+	}
+	chainID, err := reader.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("multicall: query chain ID: %w", err)
+	}
+	if !chainID.IsUint64() {
+		return fmt.Errorf("multicall: invalid chain ID %s", chainID)
+	}
+	if c.expectedChainID != 0 && chainID.Uint64() != c.expectedChainID {
+		return fmt.Errorf("multicall: connected to chain %s, expected chain %d", chainID, c.expectedChainID)
+	}
+	c.chainID = chainID
+	return nil
+}
+
+// ChainID returns the ID of the chain the client was configured for by
+// NewClientForChain or VerifyChain, or nil if it is unknown
+func (c *Client) ChainID() *big.Int {
+	return c.chainID
+}
@@ -0,0 +1,122 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RetryPolicy configures how the client retries a multicall that failed with
+// a transient error
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a multicall is sent, including the
+	// first attempt
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff bounds the delay between attempts
+	MaxBackoff time.Duration
+	// Multiplier scales the delay after every retry. Values below 1 are
+	// treated as 1, keeping the delay constant.
+	Multiplier float64
+	// Jitter is the fraction of each delay, between 0 and 1, that is
+	// randomized, so that clients which failed together do not retry in lockstep
+	Jitter float64
+	// Retryable reports whether a multicall that failed with err should be
+	// retried. Nil means IsRetryable.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy makes up to 4 attempts, backing off from 200ms up to 5s
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.5,
+}
+
+// WithRetry makes the client retry every chunk of a batch that fails with a
+// transient error according to policy, for example DefaultRetryPolicy. Only
+// the failed chunk is retried, the others keep their results.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = &policy
+	}
+}
+
+// rateLimitedCode is the JSON-RPC error code providers such as Infura use to
+// report rate limiting
+const rateLimitedCode = -32005
+
+// IsRetryable reports whether err is likely transient, so that the same
+// multicall may succeed if sent again: timeouts and connection errors, rate
+// limiting (HTTP 429 or JSON-RPC error -32005) and server errors (HTTP 5xx).
+// Reverts and other errors from executing the calls are not retryable.
+func IsRetryable(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == rateLimitedCode
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, errGasCapExceeded) {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// backoff returns the delay before the retry following attempt, counted from 1
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		delay *= max(p.Multiplier, 1)
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 {
+		delay = min(delay, float64(p.MaxBackoff))
+	}
+	jitter := min(max(p.Jitter, 0), 1)
+	return time.Duration(delay * (1 - jitter*rand.Float64()))
+}
+
+// withRetry runs execute until it succeeds, fails with an error that is not
+// retryable, or runs out of attempts
+func withRetry[R any](ctx context.Context, policy *RetryPolicy, execute func() (R, error)) (R, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := execute()
+		if err == nil || policy == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil || !policy.retryable(err) {
+			return result, err
+		}
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/john-na4/multicall3/go/internal/fake"
+)
+
+// rpcError is a JSON-RPC error with a code
+type rpcError struct {
+	code int
+}
+
+func (e rpcError) Error() string  { return fmt.Sprintf("error %d", e.code) }
+func (e rpcError) ErrorCode() int { return e.code }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{rpc.HTTPError{StatusCode: 429}, true},
+		{rpc.HTTPError{StatusCode: 502}, true},
+		{rpc.HTTPError{StatusCode: 403}, false},
+		{rpcError{rateLimitedCode}, true},
+		{rpcError{3}, false},
+		{&net.OpError{Op: "read", Err: errors.New("i/o timeout")}, true},
+		{fmt.Errorf("post: %w", syscall.ECONNRESET), true},
+		{io.EOF, true},
+		{errors.New("execution reverted"), false},
+	}
+	for _, test := range tests {
+		if got := IsRetryable(test.err); got != test.want {
+			t.Errorf("IsRetryable(%v) = %t, want %t", test.err, got, test.want)
+		}
+	}
+
+	policy := RetryPolicy{Retryable: func(error) bool { return true }}
+	if !policy.retryable(io.ErrNoProgress) || policy.retryable(errGasCapExceeded) {
+		t.Error("policy does not retry according to Retryable, or retries a multicall over the gas cap")
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 3}
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond, time.Second, time.Second} {
+		if got := policy.backoff(attempt + 1); got != want {
+			t.Errorf("got backoff %s after attempt %d, want %s", got, attempt+1, want)
+		}
+	}
+
+	policy.Multiplier = 0.5
+	if got := policy.backoff(3); got != 100*time.Millisecond {
+		t.Errorf("got backoff %s with a multiplier below 1, want the initial backoff", got)
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("got backoff %s with half of it jittered, want between 50ms and 100ms", got)
+		}
+	}
+}
+
+// flaky fails the first failures calls with an HTTP 503, then answers with
+// the balance of owner(7)
+func flaky(failures int32) (fake.Contract, *atomic.Int32) {
+	var calls atomic.Int32
+	return func(ctx context.Context, data []byte) ([]byte, error) {
+		if calls.Add(1) <= failures {
+			return nil, rpc.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}
+		}
+		return common.LeftPadBytes([]byte{7}, 32), nil
+	}, &calls
+}
+
+func TestWithRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	contract, calls := flaky(2)
+	client, err := NewClient(fake.NewCaller(map[common.Address]fake.Contract{tokenAddress: contract}), WithRetry(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := client.Aggregate3(context.Background(), []Call3{{Target: tokenAddress}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBalances(t, results, 7)
+	if n := calls.Load(); n != 3 {
+		t.Errorf("multicall sent %d times, want 3", n)
+	}
+
+	contract, calls = flaky(3)
+	client, err = NewClient(fake.NewCaller(map[common.Address]fake.Contract{tokenAddress: contract}), WithRetry(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var httpErr rpc.HTTPError
+	if _, err := client.Aggregate3(context.Background(), []Call3{{Target: tokenAddress}}); !errors.As(err, &httpErr) {
+		t.Errorf("got error %v after the last attempt, want its HTTP error", err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("multicall sent %d times, want MaxAttempts", n)
+	}
+}
+
+func TestWithRetryStops(t *testing.T) {
+	client, caller := newFakeClient(WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	// A revert would revert again
+	if _, err := client.Aggregate3(context.Background(), []Call3{{Target: reverterAddress}}); err == nil {
+		t.Fatal("multicall of a reverting call succeeded")
+	}
+	if executed := caller.Executed(); len(executed) != 1 {
+		t.Errorf("reverted multicall sent %d times, want once", len(executed))
+	}
+
+	contract, calls := flaky(1)
+	client, err := NewClient(fake.NewCaller(map[common.Address]fake.Contract{tokenAddress: contract}), WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.Aggregate3(ctx, []Call3{{Target: tokenAddress}}); err == nil {
+		t.Error("multicall succeeded without waiting out the backoff")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("multicall sent %d times before the context was done, want once", n)
+	}
+}
@@ -0,0 +1,116 @@
+package multicall
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Selectors of the errors the Solidity compiler reverts with
+var (
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+)
+
+// panicReasons describe the codes Solidity panics with
+var panicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop from empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
+
+// Revert is the decoded revert data of a failed call
+type Revert struct {
+	// Message is the reason given to require or revert, for Error(string)
+	Message string
+	// PanicCode is the code of a Panic(uint256) from a failed assertion,
+	// overflow and the like, nil for other reverts
+	PanicCode *big.Int
+	// Data is the raw revert data, which holds the selector and arguments of
+	// a custom error when it is neither an Error nor a Panic
+	Data []byte
+	// CustomError is the definition of the custom error the call reverted
+	// with, and Args its arguments, when it was decoded by an ErrorRegistry
+	CustomError *abi.Error
+	Args        []interface{}
+}
+
+// DecodeRevert decodes the revert data of a failed call, as found in the
+// ReturnData of a Result whose Success is false
+func DecodeRevert(data []byte) *Revert {
+	r := &Revert{Data: data}
+	switch {
+	case bytes.HasPrefix(data, errorSelector):
+		if values, err := (abi.Arguments{{Type: stringType}}).Unpack(data[4:]); err == nil {
+			r.Message = values[0].(string)
+		}
+	case bytes.HasPrefix(data, panicSelector) && len(data) == 4+32:
+		r.PanicCode = new(big.Int).SetBytes(data[4:])
+	}
+	return r
+}
+
+var stringType, _ = abi.NewType("string", "", nil)
+
+// IsPanic reports whether the call failed with a Panic(uint256)
+func (r *Revert) IsPanic() bool {
+	return r.PanicCode != nil
+}
+
+// String returns a human-readable reason for the revert
+func (r *Revert) String() string {
+	switch {
+	case r.CustomError != nil:
+		args := make([]string, len(r.Args))
+		for i, arg := range r.Args {
+			args[i] = fmt.Sprint(arg)
+			// abi.NewError names unnamed inputs argN, which is no help
+			if name := r.CustomError.Inputs[i].Name; name != "" && name != fmt.Sprintf("arg%d", i) {
+				args[i] = name + ": " + args[i]
+			}
+		}
+		return fmt.Sprintf("%s(%s)", r.CustomError.Name, strings.Join(args, ", "))
+	case r.PanicCode != nil:
+		reason, ok := panicReasons[r.PanicCode.Uint64()]
+		if !ok || !r.PanicCode.IsUint64() {
+			reason = "unknown panic"
+		}
+		return fmt.Sprintf("panic: %s (0x%x)", reason, r.PanicCode)
+	case r.Message != "":
+		return r.Message
+	case len(r.Data) == 0:
+		return "reverted without a reason"
+	case len(r.Data) < 4:
+		return fmt.Sprintf("reverted with data %s", hexutil.Encode(r.Data))
+	}
+	return fmt.Sprintf("custom error %s, data %s", hexutil.Encode(r.Data[:4]), hexutil.Encode(r.Data[4:]))
+}
+
+// Revert decodes the revert data of the call if it failed, and returns nil
+// if it succeeded
+func (r Result) Revert() *Revert {
+	if r.Success {
+		return nil
+	}
+	return DecodeRevert(r.ReturnData)
+}
+
+// Reason returns a human-readable reason for the failure of the call, such as
+// the message given to require, or an empty string if it succeeded
+func (r Result) Reason() string {
+	if r.Success {
+		return ""
+	}
+	return r.Revert().String()
+}
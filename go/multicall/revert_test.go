@@ -0,0 +1,45 @@
+package multicall
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestDecodeRevert(t *testing.T) {
+	panicData := append(append([]byte(nil), panicSelector...), common.LeftPadBytes([]byte{0x11}, 32)...)
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"empty", nil, "reverted without a reason"},
+		{"short", []byte{0x01, 0x02}, "reverted with data 0x0102"},
+		{"panic", panicData, "panic: arithmetic underflow or overflow (0x11)"},
+		{"custom error", hexutil.MustDecode("0xdeadbeef0000000000000000000000000000000000000000000000000000000000000001"), "custom error 0xdeadbeef, data 0x0000000000000000000000000000000000000000000000000000000000000001"},
+	}
+	for _, test := range tests {
+		if got := DecodeRevert(test.data).String(); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+	if revert := DecodeRevert(panicData); !revert.IsPanic() || revert.PanicCode.Int64() != 0x11 {
+		t.Errorf("got panic code %v, want 0x11", revert.PanicCode)
+	}
+}
+
+func TestResultReason(t *testing.T) {
+	client, _ := newFakeClient()
+	results, err := client.Aggregate3(context.Background(), []Call3{balanceCall(1), {Target: reverterAddress, AllowFailure: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason := results[0].Reason(); reason != "" {
+		t.Errorf("successful call has reason %q", reason)
+	}
+	if revert := results[1].Revert(); revert == nil || revert.Message != "nope" {
+		t.Errorf("got revert %+v, want message nope", revert)
+	}
+}
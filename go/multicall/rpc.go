@@ -0,0 +1,47 @@
+package multicall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcProvider is implemented by contract callers backed by an RPC client,
+// such as *ethclient.Client
+type rpcProvider interface {
+	Client() *rpc.Client
+}
+
+// rpcClient returns the RPC client set with WithRPCClient, or that of the
+// client's contract caller, for the JSON-RPC method named by use
+func (c *Client) rpcClient(use string) (*rpc.Client, error) {
+	if c.rpc != nil {
+		return c.rpc, nil
+	}
+	if provider, ok := c.caller.(rpcProvider); ok {
+		if client := provider.Client(); client != nil {
+			return client, nil
+		}
+	}
+	return nil, fmt.Errorf("multicall: %s needs an RPC client, see WithRPCClient", use)
+}
+
+// batchCall sends batch through client in JSON-RPC batches of at most the
+// client's maximum RPC batch size, see WithMaxRPCBatch
+func (c *Client) batchCall(ctx context.Context, client *rpc.Client, batch []rpc.BatchElem) error {
+	size := c.maxRPCBatch
+	if size == 0 {
+		size = len(batch)
+	}
+	for start := 0; start < len(batch); start += size {
+		end := start + size
+		if end > len(batch) {
+			end = len(batch)
+		}
+		if err := client.BatchCallContext(ctx, batch[start:end]); err != nil {
+			return classify(err)
+		}
+	}
+	return nil
+}
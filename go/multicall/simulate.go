@@ -0,0 +1,86 @@
+package multicall
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// simulateBlockOverrides are the block overrides of eth_simulateV1, whose
+// fields are named unlike those of eth_call
+type simulateBlockOverrides struct {
+	Number        *hexutil.Big    `json:"number,omitempty"`
+	Time          *hexutil.Uint64 `json:"time,omitempty"`
+	GasLimit      *hexutil.Uint64 `json:"gasLimit,omitempty"`
+	FeeRecipient  *common.Address `json:"feeRecipient,omitempty"`
+	PrevRandao    *common.Hash    `json:"prevRandao,omitempty"`
+	BaseFeePerGas *hexutil.Big    `json:"baseFeePerGas,omitempty"`
+}
+
+// simulateBlock is a block of calls of eth_simulateV1
+type simulateBlock struct {
+	BlockOverrides *simulateBlockOverrides `json:"blockOverrides,omitempty"`
+	StateOverrides *StateOverride          `json:"stateOverrides,omitempty"`
+	Calls          []backendCall           `json:"calls"`
+}
+
+// simulateRequest is the first parameter of eth_simulateV1
+type simulateRequest struct {
+	BlockStateCalls []simulateBlock `json:"blockStateCalls"`
+	Validation      bool            `json:"validation"`
+}
+
+// simulatedBlock is a block of the result of eth_simulateV1
+type simulatedBlock struct {
+	Calls []struct {
+		Status     hexutil.Uint64 `json:"status"`
+		ReturnData hexutil.Bytes  `json:"returnData"`
+	} `json:"calls"`
+}
+
+// simulate executes calls as the transactions of a single block with
+// eth_simulateV1, on top of the selected block
+func simulate(ctx context.Context, c *Client, client *rpc.Client, opts *callOptions, calls []Call3, values []*big.Int) ([]Result, error) {
+	block := simulateBlock{Calls: backendCalls(c, opts, calls, values), StateOverrides: c.backendOverrides(opts)}
+	if opts.blockOverrides != nil {
+		block.BlockOverrides = blockOverridesOf(opts.blockOverrides)
+	}
+	var simulated []simulatedBlock
+	request := simulateRequest{BlockStateCalls: []simulateBlock{block}}
+	if err := client.CallContext(ctx, &simulated, "eth_simulateV1", request, blockArg(opts)); err != nil {
+		return nil, err
+	}
+	if len(simulated) != 1 {
+		return nil, fmt.Errorf("got %d blocks for 1", len(simulated))
+	}
+	results := make([]Result, len(simulated[0].Calls))
+	for i, call := range simulated[0].Calls {
+		results[i] = Result{Success: call.Status == 1, ReturnData: call.ReturnData}
+	}
+	return results, nil
+}
+
+// blockOverridesOf converts block overrides to those of eth_simulateV1
+func blockOverridesOf(o *BlockOverride) *simulateBlockOverrides {
+	overrides := &simulateBlockOverrides{
+		Number:        (*hexutil.Big)(o.Number),
+		BaseFeePerGas: (*hexutil.Big)(o.BaseFee),
+	}
+	if o.Time != 0 {
+		overrides.Time = (*hexutil.Uint64)(&o.Time)
+	}
+	if o.GasLimit != 0 {
+		overrides.GasLimit = (*hexutil.Uint64)(&o.GasLimit)
+	}
+	if o.Coinbase != (common.Address{}) {
+		overrides.FeeRecipient = &o.Coinbase
+	}
+	if o.Random != (common.Hash{}) {
+		overrides.PrevRandao = &o.Random
+	}
+	return overrides
+}
@@ -0,0 +1,190 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// contractTransactor returns the transactor set with WithTransactor, or the
+// client's contract caller if it can send transactions
+func (c *Client) contractTransactor() (bind.ContractTransactor, error) {
+	if c.transactor != nil {
+		return c.transactor, nil
+	}
+	if transactor, ok := c.caller.(bind.ContractTransactor); ok {
+		return transactor, nil
+	}
+	return nil, errors.New("multicall: contract caller cannot send transactions, see WithTransactor")
+}
+
+// SendAggregate signs calls into an aggregate3 transaction to the Multicall3
+// contract with opts and broadcasts it, to batch state-changing calls. The
+// fees left unset in opts are those suggested by SuggestFees, on chains with
+// EIP-1559 and for clients with an RPC client, and the nonce, gas limit and
+// any other fees are filled in by the transactor, as by a go-ethereum
+// binding: the gas limit is estimated, which fails if a call that may not
+// fail would revert. Transactions are sent
+// through the transactor set with WithTransactor, or the contract caller of
+// the client if it can send them, such as an *ethclient.Client.
+//
+// The calls are made by Multicall3, not by the sender of the transaction, so
+// they can only change state that does not depend on msg.sender, such as
+// permissionless keeper functions. Clients for older contracts, see
+// WithVersion, send an aggregate transaction, whose calls may not fail.
+// Deployless clients cannot send transactions. The transaction is returned
+// as soon as it is broadcast; use bind.WaitMined to wait for its receipt.
+func (c *Client) SendAggregate(ctx context.Context, opts *bind.TransactOpts, calls []Call3) (*types.Transaction, error) {
+	method, data, err := c.aggregateData(calls)
+	if err != nil {
+		return nil, err
+	}
+	return c.transact(ctx, opts, method, data)
+}
+
+// SendAggregate3Value signs calls into an aggregate3Value transaction, each
+// forwarding its value, and broadcasts it like SendAggregate. The value of
+// the transaction must be the sum of the values of the calls, which it is set
+// to if opts leaves it nil; any other value fails with ErrValueMismatch
+// before anything is sent, since Multicall3 reverts unless the values add up.
+func (c *Client) SendAggregate3Value(ctx context.Context, opts *bind.TransactOpts, calls []Call3Value) (*types.Transaction, error) {
+	if opts == nil {
+		return nil, errors.New("multicall: nil transact options")
+	}
+	if err := c.checkVersion("aggregate3Value"); err != nil {
+		return nil, err
+	}
+	total, err := TotalValue(calls)
+	if err != nil {
+		return nil, err
+	}
+	o := *opts
+	switch {
+	case o.Value == nil:
+		o.Value = total
+	case o.Value.Cmp(total) != 0:
+		return nil, fmt.Errorf("%w: transaction sends %s wei, calls forward %s", ErrValueMismatch, o.Value, total)
+	}
+	return c.transact(ctx, &o, "aggregate3Value", AppendAggregate3Value(nil, calls))
+}
+
+// transact signs a transaction of method, encoded as data, to the Multicall3
+// contract with opts and broadcasts it
+func (c *Client) transact(ctx context.Context, opts *bind.TransactOpts, method string, data []byte) (*types.Transaction, error) {
+	if opts == nil {
+		return nil, errors.New("multicall: nil transact options")
+	}
+	if c.deployless {
+		return nil, errors.New("multicall: transactions cannot be sent in deployless mode")
+	}
+	transactor, err := c.contractTransactor()
+	if err != nil {
+		return nil, err
+	}
+	o := *opts
+	o.Context = ctx
+	if err := c.suggestFees(ctx, &o); err != nil {
+		return nil, err
+	}
+	nonces := c.nonces
+	if nonces != nil && o.From != nonces.Account() {
+		nonces = nil
+	}
+	reserved := nonces != nil && o.Nonce == nil
+	if reserved {
+		nonce, err := nonces.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		o.Nonce = new(big.Int).SetUint64(nonce)
+	}
+	if c.dryRun {
+		if err := c.dryRunTransaction(ctx, &o, method, data); err != nil {
+			if reserved {
+				nonces.Release(o.Nonce.Uint64())
+			}
+			return nil, err
+		}
+	}
+	tx, err := bind.NewBoundContract(c.address, ABI, nil, transactor, nil).RawTransact(&o, data)
+	if err != nil {
+		if reserved {
+			nonces.Release(o.Nonce.Uint64())
+		}
+		return nil, fmt.Errorf("multicall: send %s: %w", method, classify(err))
+	}
+	if nonces != nil {
+		nonces.Track(tx)
+	}
+	return tx, nil
+}
+
+// ReplacementBump is the percentage by which Replace raises the fees of the
+// transaction it replaces, the minimum nodes such as geth accept
+const ReplacementBump = 10
+
+// Replace sends tx, a transaction sent to the Multicall3 contract, again with
+// the same nonce, gas limit, value and calldata, but fees ReplacementBump
+// percent higher, or those of opts if they are higher still, so that it
+// replaces tx in the transaction pool: to speed up a transaction stuck behind
+// a rising base fee, or to send one a node dropped again. The replacement is
+// tracked by the client's nonce manager in place of tx. Only the signer and
+// sender of opts are used, along with its fees.
+func (c *Client) Replace(ctx context.Context, opts *bind.TransactOpts, tx *types.Transaction) (*types.Transaction, error) {
+	if opts == nil {
+		return nil, errors.New("multicall: nil transact options")
+	}
+	if tx.To() == nil || *tx.To() != c.address {
+		return nil, fmt.Errorf("multicall: transaction %s is not a transaction to %s", tx.Hash(), c.address)
+	}
+	o := bind.TransactOpts{
+		From:     opts.From,
+		Signer:   opts.Signer,
+		Nonce:    new(big.Int).SetUint64(tx.Nonce()),
+		Value:    tx.Value(),
+		GasLimit: tx.Gas(),
+	}
+	if tx.Type() == types.LegacyTxType {
+		o.GasPrice = maxBig(bumpFee(tx.GasPrice()), opts.GasPrice)
+	} else {
+		o.GasFeeCap = maxBig(bumpFee(tx.GasFeeCap()), opts.GasFeeCap)
+		o.GasTipCap = maxBig(bumpFee(tx.GasTipCap()), opts.GasTipCap)
+	}
+	return c.transact(ctx, &o, "replacement", tx.Data())
+}
+
+// bumpFee raises fee by ReplacementBump percent, rounding up
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+ReplacementBump))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// maxBig returns the larger of a and b, either of which may be nil
+func maxBig(a, b *big.Int) *big.Int {
+	if a == nil || (b != nil && b.Cmp(a) > 0) {
+		return b
+	}
+	return a
+}
+
+// aggregateData returns the method and calldata of a transaction of calls:
+// aggregate3, or aggregate for contracts older than Multicall3, which have no
+// way of letting a single call fail
+func (c *Client) aggregateData(calls []Call3) (string, []byte, error) {
+	if c.version >= Version3 {
+		return "aggregate3", AppendAggregate3(nil, calls), nil
+	}
+	plain := make([]Call, len(calls))
+	for i, call := range calls {
+		if call.AllowFailure {
+			return "", nil, fmt.Errorf("multicall: call %d allowed to fail needs Multicall3 in a transaction", i)
+		}
+		plain[i] = Call{Target: call.Target, CallData: call.CallData}
+	}
+	return "aggregate", appendAggregate(nil, "aggregate", plain), nil
+}
@@ -0,0 +1,91 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TransactionResults returns the result of every call of the mined multicall
+// transaction with the given hash, such as one sent with SendAggregate.
+// Multicall3 emits no events, so the results are recovered by tracing the
+// transaction with debug_traceTransaction and the callTracer, which reports
+// the return data of the multicall: the success and return data of each
+// call, for aggregate3, aggregate3Value and tryAggregate, or the return data
+// of each call for aggregate, whose calls all succeeded. A transaction that
+// reverted fails with ErrExecutionReverted, and one that is not sent to the
+// client's Multicall3 contract fails. It needs a node that exposes the
+// debug namespace, through the RPC client of the client, see WithRPCClient.
+func (c *Client) TransactionResults(ctx context.Context, hash common.Hash) ([]Result, error) {
+	client, err := c.rpcClient("TransactionResults")
+	if err != nil {
+		return nil, err
+	}
+	config := map[string]interface{}{
+		"tracer":       "callTracer",
+		"tracerConfig": map[string]bool{"onlyTopCall": true},
+	}
+	var frame callFrame
+	if err := client.CallContext(ctx, &frame, "debug_traceTransaction", hash, config); err != nil {
+		return nil, fmt.Errorf("multicall: trace transaction %s: %w", hash, classify(err))
+	}
+	if frame.To == nil || *frame.To != c.address {
+		return nil, fmt.Errorf("multicall: transaction %s is not a multicall to %s", hash, c.address)
+	}
+	if len(frame.Input) < 4 {
+		return nil, fmt.Errorf("multicall: transaction %s is not a multicall", hash)
+	}
+	method, err := ABI.MethodById(frame.Input[:4])
+	if err != nil {
+		return nil, fmt.Errorf("multicall: transaction %s is not a multicall: %w", hash, err)
+	}
+	if frame.Error != "" {
+		reason := frame.Error
+		if frame.RevertReason != "" {
+			reason += ": " + frame.RevertReason
+		}
+		return nil, withKind(ErrExecutionReverted, fmt.Errorf("multicall: transaction %s reverted: %s", hash, reason))
+	}
+	switch method.Name {
+	case "aggregate3", "aggregate3Value", "tryAggregate":
+		return unpackResults(method.Name, frame.Output)
+	case "blockAndAggregate", "tryBlockAndAggregate":
+		result, err := unpackBlockResult(method.Name, frame.Output)
+		if err != nil {
+			return nil, err
+		}
+		return result.Results, nil
+	case "aggregate":
+		var result AggregateResult
+		if err := ABI.UnpackIntoInterface(&result, "aggregate", frame.Output); err != nil {
+			return nil, withKind(ErrDecode, fmt.Errorf("multicall: unpack aggregate result: %w", err))
+		}
+		return result.Results(), nil
+	}
+	return nil, fmt.Errorf("multicall: transaction %s calls %s, not a multicall", hash, method.Name)
+}
+
+// WaitResults waits for tx, a multicall transaction, to be mined and returns
+// its receipt along with the result of each of its calls, see
+// TransactionResults. Receipts are polled through the client's contract
+// caller, which must be able to read them, as an *ethclient.Client can. A
+// transaction that reverted returns its receipt along with the error.
+func (c *Client) WaitResults(ctx context.Context, tx *types.Transaction) (*types.Receipt, []Result, error) {
+	backend, ok := c.caller.(bind.DeployBackend)
+	if !ok {
+		return nil, nil, errors.New("multicall: contract caller cannot read receipts")
+	}
+	receipt, err := bind.WaitMined(ctx, backend, tx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("multicall: wait for transaction %s: %w", tx.Hash(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, nil, withKind(ErrExecutionReverted, fmt.Errorf("multicall: transaction %s reverted", tx.Hash()))
+	}
+	results, err := c.TransactionResults(ctx, tx.Hash())
+	return receipt, results, err
+}
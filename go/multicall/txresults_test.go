@@ -0,0 +1,67 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// traceService serves debug_traceTransaction with the top call frames it is
+// set to, by transaction hash
+type traceService map[common.Hash]*callFrame
+
+func (s traceService) TraceTransaction(hash common.Hash, config map[string]interface{}) (*callFrame, error) {
+	frame, ok := s[hash]
+	if !ok {
+		return nil, errors.New("transaction not found")
+	}
+	return frame, nil
+}
+
+func TestTransactionResults(t *testing.T) {
+	want := []Result{{Success: true, ReturnData: []byte{1}}, {Success: false, ReturnData: []byte{}}}
+	output, err := ABI.Methods["aggregate3"].Outputs.Pack(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := AppendAggregate3(nil, []Call3{{Target: tokenAddress}, {Target: reverterAddress, AllowFailure: true}})
+	multicall, other := Address, common.Address{0x42}
+	service := traceService{
+		{1}: {To: &multicall, Input: input, Output: output},
+		{2}: {To: &other, Input: input, Output: output},
+		{3}: {To: &multicall, Input: input, Error: "execution reverted", RevertReason: "no"},
+		{4}: {Input: input},
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("debug", service); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	rpcClient := rpc.DialInProc(server)
+	t.Cleanup(rpcClient.Close)
+	client, _ := newFakeClient(WithRPCClient(rpcClient))
+
+	results, err := client.TransactionResults(context.Background(), common.Hash{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("got results %+v, want %+v", results, want)
+	}
+	// Multicalls to another contract, such as a fork of Multicall3 at
+	// another address, and contract creations are not multicalls of the
+	// client
+	for _, hash := range []common.Hash{{2}, {4}} {
+		if _, err := client.TransactionResults(context.Background(), hash); err == nil || !strings.Contains(err.Error(), "is not a multicall to "+Address.Hex()) {
+			t.Errorf("got error %v for transaction %s, want it not to be a multicall", err, hash)
+		}
+	}
+	if _, err := client.TransactionResults(context.Background(), common.Hash{3}); !errors.Is(err, ErrExecutionReverted) || !strings.Contains(err.Error(), "execution reverted: no") {
+		t.Errorf("got error %v, want the revert of the transaction", err)
+	}
+}
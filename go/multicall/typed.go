@@ -0,0 +1,88 @@
+package multicall
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TypedCall is a call that decodes its own return data once its batch has
+// been executed. It is implemented by *CallOf.
+type TypedCall interface {
+	spec() (contractABI abi.ABI, method string, args []interface{})
+	decode(values []interface{}) error
+	fail(err error)
+}
+
+// errNotExecuted is returned by CallOf.Get before its batch has been executed
+var errNotExecuted = errors.New("multicall: call has not been executed")
+
+// CallOf is a call whose return value is decoded as T. Methods with a single
+// output are converted directly to T, methods with several outputs are copied
+// into the fields of T, which must then be a struct.
+type CallOf[T any] struct {
+	abi    abi.ABI
+	method string
+	args   []interface{}
+
+	value T
+	err   error
+}
+
+// View returns a typed call of method on a contract described by contractABI,
+// to be added to a batch with Batch.AddCall:
+//
+//	decimals := multicall.View[uint8](erc20ABI, "decimals")
+//	_, err := client.NewBatch().AddCall(token, decimals).Execute(ctx)
+//	value, err := decimals.Get()
+func View[T any](contractABI abi.ABI, method string, args ...interface{}) *CallOf[T] {
+	return &CallOf[T]{
+		abi:    contractABI,
+		method: method,
+		args:   args,
+		err:    errNotExecuted,
+	}
+}
+
+// Get returns the decoded return value, or the error that prevented the call
+// from being decoded.
+func (c *CallOf[T]) Get() (T, error) {
+	return c.value, c.err
+}
+
+// Value returns the decoded return value, or the zero value of T if the call
+// has not been decoded. It is convenient once Execute has returned no error.
+func (c *CallOf[T]) Value() T {
+	return c.value
+}
+
+func (c *CallOf[T]) spec() (abi.ABI, string, []interface{}) {
+	return c.abi, c.method, c.args
+}
+
+func (c *CallOf[T]) decode(values []interface{}) error {
+	var value T
+	if err := convertValues(c.abi.Methods[c.method].Outputs, values, &value); err != nil {
+		c.err = err
+		return err
+	}
+	c.value, c.err = value, nil
+	return nil
+}
+
+func (c *CallOf[T]) fail(err error) {
+	var zero T
+	c.value, c.err = zero, err
+}
+
+// AddCall appends a typed call on the contract at target. Its decoded value is
+// available through the typed call once the batch has been executed.
+func (b *Batch) AddCall(target common.Address, call TypedCall) *Batch {
+	contractABI, method, args := call.spec()
+	b.Add(target, contractABI, method, args...)
+	if b.err == nil {
+		b.calls[len(b.calls)-1].typed = call
+	}
+	return b
+}
@@ -0,0 +1,166 @@
+package multicall
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Version is a generation of the multicall contract. Each version supports
+// the methods of the previous ones and adds its own.
+type Version int
+
+const (
+	// VersionUnknown is reported when no multicall contract could be detected
+	VersionUnknown Version = iota
+	// Version1 is the original Multicall contract, with aggregate
+	Version1
+	// Version2 is Multicall2, which adds tryAggregate, blockAndAggregate and
+	// tryBlockAndAggregate
+	Version2
+	// Version3 is Multicall3, which adds aggregate3 and aggregate3Value
+	Version3
+)
+
+func (v Version) String() string {
+	switch v {
+	case Version1:
+		return "Multicall"
+	case Version2:
+		return "Multicall2"
+	case Version3:
+		return "Multicall3"
+	}
+	return "unknown multicall version"
+}
+
+// versionMethods are the methods of the Multicall3 ABI introduced by each
+// earlier version. Methods missing from every entry are Multicall3 only.
+var versionMethods = map[string]Version{
+	"aggregate":                 Version1,
+	"getBlockHash":              Version1,
+	"getCurrentBlockCoinbase":   Version1,
+	"getCurrentBlockDifficulty": Version1,
+	"getCurrentBlockGasLimit":   Version1,
+	"getCurrentBlockTimestamp":  Version1,
+	"getEthBalance":             Version1,
+	"getLastBlockHash":          Version1,
+	"blockAndAggregate":         Version2,
+	"getBlockNumber":            Version2,
+	"tryAggregate":              Version2,
+	"tryBlockAndAggregate":      Version2,
+}
+
+// Supports reports whether method, named as in the Multicall3 ABI, is
+// available on contracts of version v
+func (v Version) Supports(method string) bool {
+	since, ok := versionMethods[method]
+	if !ok {
+		since = Version3
+	}
+	return v >= since
+}
+
+// codeReader is implemented by contract callers that can fetch the code of an
+// account, such as *ethclient.Client
+type codeReader interface {
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// DetectVersion probes the contract at address for the methods introduced by
+// each multicall version, newest first, and returns the newest version it
+// responds to. It returns VersionUnknown with an error if the contract does
+// not behave like any multicall version, or if there is no code at address
+// when caller can fetch code.
+func DetectVersion(ctx context.Context, caller ethereum.ContractCaller, address common.Address) (Version, error) {
+	// Calls to an address without code succeed with empty output, so rule
+	// that out first when the caller can tell
+	if reader, ok := caller.(codeReader); ok {
+		code, err := reader.CodeAt(ctx, address, nil)
+		if err != nil {
+			return VersionUnknown, fmt.Errorf("multicall: get code at %s: %w", address, err)
+		}
+		if len(code) == 0 {
+			return VersionUnknown, fmt.Errorf("%w: no contract at %s", ErrMulticallNotDeployed, address)
+		}
+	}
+	probes := []struct {
+		version Version
+		method  string
+		args    []interface{}
+	}{
+		{Version3, "getChainId", nil},
+		{Version2, "tryAggregate", []interface{}{false, []Call{}}},
+		{Version1, "aggregate", []interface{}{[]Call{}}},
+	}
+	for _, probe := range probes {
+		data, err := ABI.Pack(probe.method, probe.args...)
+		if err != nil {
+			return VersionUnknown, fmt.Errorf("multicall: pack %s: %w", probe.method, err)
+		}
+		output, err := caller.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return VersionUnknown, ctx.Err()
+			}
+			continue
+		}
+		// A contract with a fallback function may accept any selector, so
+		// only trust probes whose output decodes as expected
+		if _, err := ABI.Unpack(probe.method, output); err == nil {
+			return probe.version, nil
+		}
+	}
+	return VersionUnknown, fmt.Errorf("multicall: contract at %s is not a known multicall version", address)
+}
+
+// DetectVersion detects the version of the multicall contract the client
+// sends its calls to and adapts the client to it, see WithVersion. Clients
+// assume Multicall3 until DetectVersion or WithVersion says otherwise.
+func (c *Client) DetectVersion(ctx context.Context) (Version, error) {
+	version, err := DetectVersion(ctx, c.caller, c.address)
+	if err != nil {
+		return VersionUnknown, err
+	}
+	c.version = version
+	return version, nil
+}
+
+// Version returns the version of the multicall contract the client assumes
+func (c *Client) Version() Version {
+	return c.version
+}
+
+// checkVersion fails if method is not available on the client's contract
+func (c *Client) checkVersion(method string) error {
+	if !c.version.Supports(method) {
+		return fmt.Errorf("multicall: %s is not supported by %s", method, c.version)
+	}
+	return nil
+}
+
+// blockNumber returns the current block number according to the contract.
+// The original Multicall has no getBlockNumber, but reports the block number
+// of an empty aggregate. Clients that execute their multicalls with a node
+// backend need no contract and read it from the block header.
+func (c *Client) blockNumber(ctx context.Context, opts *callOptions) (*big.Int, error) {
+	if c.nodeBackend() != nil {
+		return c.headNumber(ctx, opts)
+	}
+	method, args := "getBlockNumber", []interface{}(nil)
+	if c.version == Version1 {
+		method, args = "aggregate", []interface{}{[]Call{}}
+	}
+	output, err := c.call(ctx, opts, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	values, err := ABI.Unpack(method, output)
+	if err != nil {
+		return nil, withKind(ErrDecode, fmt.Errorf("multicall: unpack %s result: %w", method, err))
+	}
+	return values[0].(*big.Int), nil
+}
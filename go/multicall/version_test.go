@@ -0,0 +1,98 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/fake"
+)
+
+// versionCaller executes multicalls like a contract of version would,
+// reverting the methods it lacks
+type versionCaller struct {
+	*fake.Caller
+	version Version
+}
+
+func (c *versionCaller) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if method, err := ABI.MethodById(msg.Data); err == nil && !c.version.Supports(method.Name) {
+		return nil, errors.New("execution reverted")
+	}
+	return c.Caller.CallContract(ctx, msg, blockNumber)
+}
+
+// newVersionClient returns a client of a fake contract of version, along
+// with its caller
+func newVersionClient(version Version, opts ...Option) (*Client, *versionCaller) {
+	client, fakeCaller := newFakeClient()
+	caller := &versionCaller{Caller: fakeCaller, version: version}
+	client, err := NewClient(caller, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return client, caller
+}
+
+// noCode has no code at any address
+type noCode struct {
+	*fake.Caller
+}
+
+func (noCode) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+// fallback answers every call with no data, like a contract whose fallback
+// function accepts any selector
+type fallback struct {
+	*fake.Caller
+}
+
+func (fallback) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func TestDetectVersion(t *testing.T) {
+	for _, version := range []Version{Version1, Version2, Version3} {
+		client, _ := newVersionClient(version)
+		got, err := client.DetectVersion(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+		if got != version || client.Version() != version {
+			t.Errorf("detected %s, client assumes %s, want %s", got, client.Version(), version)
+		}
+	}
+
+	_, caller := newFakeClient()
+	if _, err := DetectVersion(context.Background(), noCode{caller}, Address); !errors.Is(err, ErrMulticallNotDeployed) {
+		t.Errorf("got error %v without code, want ErrMulticallNotDeployed", err)
+	}
+	if version, err := DetectVersion(context.Background(), fallback{caller}, Address); err == nil || version != VersionUnknown {
+		t.Errorf("detected %s on a contract accepting any call", version)
+	}
+}
+
+func TestVersionSupports(t *testing.T) {
+	tests := []struct {
+		version Version
+		method  string
+		want    bool
+	}{
+		{Version1, "aggregate", true},
+		{Version1, "tryAggregate", false},
+		{Version2, "tryBlockAndAggregate", true},
+		{Version2, "aggregate3", false},
+		{Version3, "aggregate3Value", true},
+		{VersionUnknown, "aggregate", false},
+	}
+	for _, test := range tests {
+		if got := test.version.Supports(test.method); got != test.want {
+			t.Errorf("%s supports %s: got %t, want %t", test.version, test.method, got, test.want)
+		}
+	}
+}
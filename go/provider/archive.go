@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// DefaultArchiveThreshold is the number of recent blocks whose state full
+// nodes keep, as geth does by default
+const DefaultArchiveThreshold = 128
+
+// WithArchiveThreshold sets how many blocks behind the head a request must be
+// for the pool to only send it to endpoints marked as Archive. It defaults to
+// DefaultArchiveThreshold.
+func WithArchiveThreshold(blocks uint64) Option {
+	return func(p *Pool) {
+		p.archiveThreshold = blocks
+	}
+}
+
+// NoArchiveEndpointError is returned for requests at a block old enough to
+// need an archive node when none of the pool's endpoints is marked as Archive
+type NoArchiveEndpointError struct {
+	// Block is the block of the request and Head the latest known block
+	Block, Head uint64
+}
+
+func (e *NoArchiveEndpointError) Error() string {
+	return fmt.Sprintf("provider: block %d is %d blocks behind head %d and no archive endpoint is configured", e.Block, e.Head-e.Block, e.Head)
+}
+
+// route returns the endpoints to try for a request at block, keeping only
+// the archive endpoints if the block is old
+func (p *Pool) route(ctx context.Context, block *big.Int) ([]*endpoint, error) {
+	candidates := p.candidates()
+	if block == nil || block.Sign() < 0 || !block.IsUint64() {
+		return candidates, nil
+	}
+	p.mu.Lock()
+	head := p.bestHead
+	p.mu.Unlock()
+	if head == 0 {
+		var err error
+		if head, err = p.BlockNumber(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if block.Uint64()+p.archiveThreshold >= head {
+		return candidates, nil
+	}
+	archive := candidates[:0:0]
+	for _, e := range candidates {
+		if e.Archive {
+			archive = append(archive, e)
+		}
+	}
+	if len(archive) == 0 {
+		return nil, &NoArchiveEndpointError{Block: block.Uint64(), Head: head}
+	}
+	return archive, nil
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestArchiveRouting(t *testing.T) {
+	full, archive := newNode(t, 1, 1000), newNode(t, 2, 1000)
+	pool, err := DialEndpoints(context.Background(), []Endpoint{{URL: full.URL()}, {URL: archive.URL(), Archive: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	tests := []struct {
+		block *big.Int
+		want  byte
+	}{
+		{nil, 1},
+		{big.NewInt(1000 - DefaultArchiveThreshold), 1},
+		{big.NewInt(1000 - DefaultArchiveThreshold - 1), 2},
+		{big.NewInt(10), 2},
+		// Tags such as "pending" are recent
+		{big.NewInt(-1), 1},
+	}
+	for _, test := range tests {
+		got, err := call(pool, test.block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("call at block %v answered by node %d, want %d", test.block, got, test.want)
+		}
+	}
+}
+
+func TestNoArchiveEndpoint(t *testing.T) {
+	full := newNode(t, 1, 1000)
+	pool := dial(t, []string{full.URL()})
+	_, err := call(pool, big.NewInt(10))
+	var noArchive *NoArchiveEndpointError
+	if !errors.As(err, &noArchive) || noArchive.Block != 10 || noArchive.Head != 1000 {
+		t.Fatalf("got error %v, want a NoArchiveEndpointError for block 10 at head 1000", err)
+	}
+	if n := full.requests.Load(); n != 1 {
+		t.Errorf("full node got %d requests, want only the one for the head", n)
+	}
+
+	pool = dial(t, []string{full.URL()}, WithArchiveThreshold(1000))
+	if _, err := call(pool, big.NewInt(10)); err != nil {
+		t.Errorf("call within the archive threshold failed: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+// dial connects to the endpoint with its transport options
+func (e *Endpoint) dial(ctx context.Context) (*rpc.Client, error) {
+	var opts []rpc.ClientOption
+	if len(e.Headers) > 0 {
+		opts = append(opts, rpc.WithHeaders(e.Headers))
+	}
+	if e.Auth != nil {
+		opts = append(opts, rpc.WithHTTPAuth(e.Auth))
+	}
+	if e.Proxy != nil || e.TLSConfig != nil {
+		proxy := http.ProxyFromEnvironment
+		if e.Proxy != nil {
+			proxy = http.ProxyURL(e.Proxy)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = proxy
+		transport.TLSClientConfig = e.TLSConfig
+		opts = append(opts,
+			rpc.WithHTTPClient(&http.Client{Transport: transport}),
+			rpc.WithWebsocketDialer(websocket.Dialer{
+				Proxy:            proxy,
+				TLSClientConfig:  e.TLSConfig,
+				HandshakeTimeout: websocket.DefaultDialer.HandshakeTimeout,
+			}),
+		)
+	}
+	return rpc.DialOptions(ctx, e.URL, opts...)
+}
+
+// LoadClientTLS returns a TLS configuration for mutual TLS, presenting the
+// client certificate and key in the PEM files certFile and keyFile. If caFile
+// is not empty, server certificates are verified against the certificate
+// authorities it contains instead of the system roots.
+func LoadClientTLS(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("provider: load client certificate: %w", err)
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("provider: read certificate authorities: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("provider: no certificates found in " + caFile)
+		}
+	}
+	return config, nil
+}
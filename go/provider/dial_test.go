@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// headerRecorder serves a node, keeping the headers of the last request
+type headerRecorder struct {
+	n *node
+
+	mu     sync.Mutex
+	header http.Header
+}
+
+func (r *headerRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	r.header = req.Header.Clone()
+	r.mu.Unlock()
+	r.n.ServeHTTP(w, req)
+}
+
+func (r *headerRecorder) get(key string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.header.Get(key)
+}
+
+func TestEndpointHeaders(t *testing.T) {
+	recorder := &headerRecorder{n: newNode(t, 1, 100)}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+	token := "first"
+	pool, err := DialEndpoints(context.Background(), []Endpoint{{
+		URL:     server.URL,
+		Headers: http.Header{"X-Api-Key": {"secret"}},
+		Auth: func(h http.Header) error {
+			h.Set("Authorization", "Bearer "+token)
+			return nil
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	checkAnswered(t, pool, 1)
+	if got := recorder.get("X-Api-Key"); got != "secret" {
+		t.Errorf("got X-Api-Key %q, want secret", got)
+	}
+	if got := recorder.get("Authorization"); got != "Bearer first" {
+		t.Errorf("got Authorization %q, want the first token", got)
+	}
+
+	// Auth is called again for every request
+	token = "second"
+	checkAnswered(t, pool, 1)
+	if got := recorder.get("Authorization"); got != "Bearer second" {
+		t.Errorf("got Authorization %q, want the refreshed token", got)
+	}
+}
+
+func TestEndpointAuthError(t *testing.T) {
+	up := newNode(t, 1, 100)
+	pool, err := DialEndpoints(context.Background(), []Endpoint{{
+		URL:  up.URL(),
+		Auth: func(http.Header) error { return errors.New("token expired") },
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	if _, err := call(pool, nil); err == nil || !strings.Contains(err.Error(), "token expired") {
+		t.Errorf("got error %v, want the error of Auth", err)
+	}
+	if n := up.requests.Load(); n != 0 {
+		t.Errorf("endpoint got %d requests without authentication", n)
+	}
+}
+
+func TestEndpointProxy(t *testing.T) {
+	up := newNode(t, 1, 100)
+	var proxied atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests for a proxy name their target in full
+		if "http://"+r.URL.Host != up.URL() {
+			http.Error(w, "unknown target", http.StatusBadGateway)
+			return
+		}
+		proxied.Add(1)
+		up.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := DialEndpoints(context.Background(), []Endpoint{{URL: up.URL(), Proxy: proxyURL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	checkAnswered(t, pool, 1)
+	if n := proxied.Load(); n != 1 {
+		t.Errorf("proxy got %d requests, want 1", n)
+	}
+}
+
+// writeCertificate writes a self-signed client certificate and its key to
+// PEM files in dir
+func writeCertificate(t *testing.T, dir string) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert, err = x509.ParseCertificate(der); err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile, cert
+}
+
+func writePEM(t *testing.T, file, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEndpointMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, cert := writeCertificate(t, dir)
+	server := httptest.NewUnstartedServer(newNode(t, 1, 100))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+	caFile := filepath.Join(dir, "ca.pem")
+	writePEM(t, caFile, "CERTIFICATE", server.Certificate().Raw)
+
+	config, err := LoadClientTLS(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := DialEndpoints(context.Background(), []Endpoint{{URL: server.URL, TLSConfig: config}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	checkAnswered(t, pool, 1)
+
+	// The server rejects clients without a certificate
+	pool, err = DialEndpoints(context.Background(), []Endpoint{{URL: server.URL, TLSConfig: &tls.Config{RootCAs: config.RootCAs}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	if _, err := call(pool, nil); err == nil {
+		t.Error("call succeeded without a client certificate")
+	}
+}
+
+func TestLoadClientTLSErrors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeCertificate(t, dir)
+	if _, err := LoadClientTLS(certFile, filepath.Join(dir, "missing.key"), ""); err == nil {
+		t.Error("loaded a certificate without its key")
+	}
+	empty := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadClientTLS(certFile, keyFile, empty); err == nil || !strings.Contains(err.Error(), "no certificates") {
+		t.Errorf("got error %v for certificate authorities without certificates", err)
+	}
+	config, err := LoadClientTLS(certFile, keyFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Certificates) != 1 || config.RootCAs != nil {
+		t.Error("configuration does not present the certificate against the system roots")
+	}
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// FailoverPolicy decides when a Pool moves on to its next endpoint
+type FailoverPolicy struct {
+	// ShouldFailover reports whether a request that failed with err should be
+	// retried on the next endpoint. Nil means IsFailoverError.
+	ShouldFailover func(err error) bool
+	// Cooldown is how long an endpoint that failed is moved behind the others
+	Cooldown time.Duration
+	// MaxAttempts bounds the number of endpoints tried for a request. Zero
+	// means every endpoint is tried.
+	MaxAttempts int
+}
+
+// DefaultFailoverPolicy fails over on the errors recognized by
+// IsFailoverError and gives a failed endpoint 30 seconds to recover
+var DefaultFailoverPolicy = FailoverPolicy{Cooldown: 30 * time.Second}
+
+// WithFailoverPolicy sets the failover policy of the pool
+func WithFailoverPolicy(policy FailoverPolicy) Option {
+	return func(p *Pool) {
+		p.policy = policy
+	}
+}
+
+func (policy FailoverPolicy) shouldFailover(err error) bool {
+	if policy.ShouldFailover != nil {
+		return policy.ShouldFailover(err)
+	}
+	return IsFailoverError(err)
+}
+
+// limitExceededCode is the JSON-RPC error code providers such as Infura use
+// to report rate limiting
+const limitExceededCode = -32005
+
+// IsFailoverError reports whether err means the endpoint could not serve the
+// request, rather than that the request itself failed: connection errors,
+// rate limiting (HTTP 429) and server errors (HTTP 5xx). Reverts and other
+// JSON-RPC errors would fail on every endpoint, so they are not failed over.
+func IsFailoverError(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == limitExceededCode
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, rpc.ErrClientQuit)
+}
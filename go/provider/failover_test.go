@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcError is a JSON-RPC error with a code
+type rpcError struct {
+	code int
+}
+
+func (e rpcError) Error() string  { return fmt.Sprintf("error %d", e.code) }
+func (e rpcError) ErrorCode() int { return e.code }
+
+func TestIsFailoverError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{rpc.HTTPError{StatusCode: 429}, true},
+		{rpc.HTTPError{StatusCode: 503}, true},
+		{rpc.HTTPError{StatusCode: 401}, false},
+		{rpcError{limitExceededCode}, true},
+		{rpcError{-32000}, false},
+		{&net.OpError{Op: "dial", Err: errors.New("no route to host")}, true},
+		{fmt.Errorf("post: %w", syscall.ECONNREFUSED), true},
+		{io.ErrUnexpectedEOF, true},
+		{rpc.ErrClientQuit, true},
+		{errors.New("execution reverted"), false},
+	}
+	for _, test := range tests {
+		if got := IsFailoverError(test.err); got != test.want {
+			t.Errorf("IsFailoverError(%v) = %t, want %t", test.err, got, test.want)
+		}
+	}
+
+	policy := FailoverPolicy{ShouldFailover: func(err error) bool { return err.Error() == "execution reverted" }}
+	if !policy.shouldFailover(errors.New("execution reverted")) || policy.shouldFailover(io.EOF) {
+		t.Error("policy does not fail over according to ShouldFailover")
+	}
+}
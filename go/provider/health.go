@@ -0,0 +1,254 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// HealthPolicy configures how a Pool monitors its endpoints
+type HealthPolicy struct {
+	// Interval between health checks of every endpoint, which query its
+	// latest block number. Zero disables health checks, leaving the
+	// statistics to be gathered from requests alone.
+	Interval time.Duration
+	// Timeout of a health check
+	Timeout time.Duration
+	// An endpoint that goes from healthy to failing FlapThreshold times
+	// within FlapWindow is quarantined for Quarantine: it is only used once
+	// every other endpoint has failed. A FlapThreshold of zero disables
+	// quarantine.
+	FlapThreshold int
+	FlapWindow    time.Duration
+	Quarantine    time.Duration
+}
+
+// DefaultHealthPolicy checks endpoints every 15 seconds and quarantines an
+// endpoint for 5 minutes if it fails 3 times in 5 minutes
+var DefaultHealthPolicy = HealthPolicy{
+	Interval:      15 * time.Second,
+	Timeout:       5 * time.Second,
+	FlapThreshold: 3,
+	FlapWindow:    5 * time.Minute,
+	Quarantine:    5 * time.Minute,
+}
+
+// WithHealthChecks sets the health policy of the pool, for example
+// DefaultHealthPolicy. Without it, endpoints are not health checked, unless
+// WithMaxBlockLag is set, but are still quarantined according to
+// DefaultHealthPolicy.
+func WithHealthChecks(policy HealthPolicy) Option {
+	return func(p *Pool) {
+		p.health = policy
+		p.healthSet = true
+	}
+}
+
+// WithMaxBlockLag makes the pool avoid endpoints whose latest block is more
+// than blocks behind the best head reported by any endpoint, so that results
+// are not silently stale. Such endpoints are only used once every up to date
+// endpoint has failed. Heads are learned from health checks and from
+// BlockNumber requests, so unless WithHealthChecks sets another policy, the
+// endpoints are health checked at the interval of DefaultHealthPolicy. A
+// policy with a zero interval leaves only BlockNumber requests to notice
+// stale endpoints.
+func WithMaxBlockLag(blocks uint64) Option {
+	return func(p *Pool) {
+		p.maxBlockLag = blocks
+	}
+}
+
+// WithLatencyRouting makes the pool send requests to the healthiest endpoint,
+// the one with the lowest latency weighted by its error rate, rather than in
+// the order the endpoints were given. Endpoints that have not been measured
+// yet are tried first.
+func WithLatencyRouting() Option {
+	return func(p *Pool) {
+		p.latencyRouting = true
+	}
+}
+
+// ewmaWeight is the weight of a new sample in the rolling statistics
+const ewmaWeight = 0.2
+
+// endpointHealth holds the rolling statistics of an endpoint, guarded by the
+// pool's mutex
+type endpointHealth struct {
+	samples   int
+	latency   float64
+	errorRate float64
+	healthy   bool
+
+	// failedUntil is when the endpoint comes out of its failover cooldown
+	failedUntil      time.Time
+	flaps            []time.Time
+	quarantinedUntil time.Time
+
+	// head is the latest block number the endpoint reported
+	head uint64
+}
+
+// score ranks endpoints for latency routing, lower is better
+func (h *endpointHealth) score() float64 {
+	return h.latency * (1 + 10*h.errorRate)
+}
+
+// EndpointStats are the statistics a Pool keeps about an endpoint
+type EndpointStats struct {
+	URL string
+	// Latency is the rolling average latency of the endpoint
+	Latency time.Duration
+	// ErrorRate is the rolling fraction of requests that failed
+	ErrorRate float64
+	// Healthy reports whether the last request or health check succeeded
+	Healthy bool
+	// Quarantined reports whether the endpoint is quarantined for flapping
+	Quarantined bool
+	// Head is the latest block number the endpoint reported
+	Head uint64
+	// Stale reports whether the endpoint lags behind the best known head by
+	// more than the pool allows
+	Stale bool
+}
+
+// Stats returns the statistics of the endpoints, in the order they were given
+func (p *Pool) Stats() []EndpointStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	stats := make([]EndpointStats, len(p.endpoints))
+	for i, e := range p.endpoints {
+		stats[i] = EndpointStats{
+			URL:         e.URL,
+			Latency:     time.Duration(e.health.latency),
+			ErrorRate:   e.health.errorRate,
+			Healthy:     e.health.healthy,
+			Quarantined: now.Before(e.health.quarantinedUntil),
+			Head:        e.health.head,
+			Stale:       p.stale(e),
+		}
+	}
+	return stats
+}
+
+// record updates the statistics of e after a request that took latency and
+// failed if failed is set
+func (p *Pool) record(e *endpoint, latency time.Duration, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h := &e.health
+	errorSample := 0.0
+	if failed {
+		errorSample = 1
+	}
+	if h.samples == 0 {
+		h.latency, h.errorRate = float64(latency), errorSample
+	} else {
+		h.latency += ewmaWeight * (float64(latency) - h.latency)
+		h.errorRate += ewmaWeight * (errorSample - h.errorRate)
+	}
+	h.samples++
+
+	wasHealthy := h.healthy
+	h.healthy = !failed
+	if !failed {
+		return
+	}
+	now := time.Now()
+	h.failedUntil = now.Add(p.policy.Cooldown)
+	if !wasHealthy || p.health.FlapThreshold == 0 {
+		return
+	}
+	// A failure after a success is a flap
+	recent := h.flaps[:0]
+	for _, t := range h.flaps {
+		if now.Sub(t) < p.health.FlapWindow {
+			recent = append(recent, t)
+		}
+	}
+	h.flaps = append(recent, now)
+	if len(h.flaps) >= p.health.FlapThreshold {
+		h.quarantinedUntil = now.Add(p.health.Quarantine)
+		h.flaps = nil
+	}
+}
+
+// recordHead updates the latest block number reported by e, and the best
+// known head of the chain
+func (p *Pool) recordHead(e *endpoint, head uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e.health.head = head
+	p.bestHead = max(p.bestHead, head)
+}
+
+// stale reports whether e lags behind the best known head by more than the
+// maximum block lag. Endpoints that have not reported a head are not stale.
+func (p *Pool) stale(e *endpoint) bool {
+	return p.maxBlockLag > 0 && e.health.head > 0 && e.health.head+p.maxBlockLag < p.bestHead
+}
+
+// candidates returns the endpoints to try for a request, in order: the
+// available endpoints first, then those lagging behind the chain, those in
+// failover cooldown in case they recovered, and the quarantined ones last
+func (p *Pool) candidates() []*endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	ready := make([]*endpoint, 0, len(p.endpoints))
+	var stale, cooling, quarantined []*endpoint
+	for _, e := range p.endpoints {
+		switch {
+		case now.Before(e.health.quarantinedUntil):
+			quarantined = append(quarantined, e)
+		case now.Before(e.health.failedUntil):
+			cooling = append(cooling, e)
+		case p.stale(e):
+			stale = append(stale, e)
+		default:
+			ready = append(ready, e)
+		}
+	}
+	if p.latencyRouting {
+		sort.SliceStable(ready, func(i, j int) bool {
+			return ready[i].health.score() < ready[j].health.score()
+		})
+	}
+	return append(append(append(ready, stale...), cooling...), quarantined...)
+}
+
+// checkHealth queries the latest block of every endpoint every interval until
+// the pool is closed
+func (p *Pool) checkHealth(ctx context.Context) {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.health.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		for _, e := range p.endpoints {
+			p.check(ctx, e)
+		}
+	}
+}
+
+func (p *Pool) check(ctx context.Context, e *endpoint) {
+	if p.health.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.health.Timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	head, err := e.client.BlockNumber(ctx)
+	if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+		return
+	}
+	p.record(e, time.Since(start), err != nil)
+	if err == nil {
+		p.recordHead(e, head)
+	}
+}
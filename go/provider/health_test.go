@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestLatencyRouting(t *testing.T) {
+	slow, fast := newNode(t, 1, 100), newNode(t, 2, 100)
+	slow.delay.Store(int64(20 * time.Millisecond))
+	pool := dial(t, []string{slow.URL(), fast.URL()}, WithLatencyRouting())
+	// Endpoints that were not measured yet come first, in order
+	checkAnswered(t, pool, 1)
+	checkAnswered(t, pool, 2)
+	checkAnswered(t, pool, 2)
+	stats := pool.Stats()
+	if stats[0].Latency < 20*time.Millisecond || stats[1].Latency >= stats[0].Latency {
+		t.Errorf("got latencies %s and %s, want the first endpoint slower", stats[0].Latency, stats[1].Latency)
+	}
+
+	// Without latency routing, endpoints are tried in order
+	pool = dial(t, []string{slow.URL(), fast.URL()})
+	for i := 0; i < 2; i++ {
+		checkAnswered(t, pool, 1)
+	}
+}
+
+func TestQuarantine(t *testing.T) {
+	flapping, steady := newNode(t, 1, 100), newNode(t, 2, 100)
+	pool := dial(t, []string{flapping.URL(), steady.URL()},
+		WithFailoverPolicy(FailoverPolicy{}),
+		WithHealthChecks(HealthPolicy{FlapThreshold: 2, FlapWindow: time.Minute, Quarantine: time.Hour}))
+	for i := 0; i < 2; i++ {
+		flapping.status.Store(0)
+		checkAnswered(t, pool, 1)
+		flapping.status.Store(http.StatusServiceUnavailable)
+		checkAnswered(t, pool, 2)
+	}
+	// The endpoint recovered, but it flapped twice
+	flapping.status.Store(0)
+	checkAnswered(t, pool, 2)
+	if stats := pool.Stats(); !stats[0].Quarantined || stats[1].Quarantined {
+		t.Errorf("got stats %+v, want the first endpoint quarantined", stats)
+	}
+
+	// Quarantined endpoints are still used once every other one failed
+	steady.status.Store(http.StatusServiceUnavailable)
+	checkAnswered(t, pool, 1)
+}
+
+func TestHealthChecks(t *testing.T) {
+	up, down := newNode(t, 1, 100), newNode(t, 2, 90)
+	down.status.Store(http.StatusServiceUnavailable)
+	policy := DefaultHealthPolicy
+	policy.Interval = 5 * time.Millisecond
+	pool := dial(t, []string{up.URL(), down.URL()}, WithHealthChecks(policy))
+	waitFor(t, func() bool {
+		stats := pool.Stats()
+		return stats[0].Healthy && stats[0].Head == 100 && stats[1].ErrorRate > 0
+	})
+
+	// A health check that succeeds brings the endpoint back
+	down.status.Store(0)
+	waitFor(t, func() bool {
+		stats := pool.Stats()
+		return stats[1].Healthy && stats[1].Head == 90
+	})
+
+	// Without health checks, the pool only learns from requests
+	pool = dial(t, []string{up.URL(), down.URL()})
+	time.Sleep(20 * time.Millisecond)
+	if stats := pool.Stats(); stats[0].Head != 0 || stats[0].Healthy {
+		t.Errorf("got stats %+v without health checks or requests", stats[0])
+	}
+}
+
+// waitFor waits up to a second for condition to hold
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !condition(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met after a second")
+		}
+	}
+}
+
+func TestMaxBlockLag(t *testing.T) {
+	behind, synced := newNode(t, 1, 50), newNode(t, 2, 100)
+	policy := DefaultHealthPolicy
+	policy.Interval = 5 * time.Millisecond
+	pool := dial(t, []string{behind.URL(), synced.URL()}, WithMaxBlockLag(10), WithHealthChecks(policy))
+	waitFor(t, func() bool {
+		return pool.Stats()[0].Stale
+	})
+	checkAnswered(t, pool, 2)
+
+	// Stale endpoints are used once every up to date endpoint failed
+	synced.status.Store(http.StatusServiceUnavailable)
+	checkAnswered(t, pool, 1)
+
+	// Catching up makes the endpoint available again
+	behind.head.Store(100)
+	waitFor(t, func() bool {
+		return !pool.Stats()[0].Stale
+	})
+}
+
+func TestMaxBlockLagChecksHealth(t *testing.T) {
+	up := newNode(t, 1, 100)
+	pool := dial(t, []string{up.URL()}, WithMaxBlockLag(10))
+	if pool.health.Interval != DefaultHealthPolicy.Interval {
+		t.Errorf("got health checks every %s with a maximum block lag, want %s", pool.health.Interval, DefaultHealthPolicy.Interval)
+	}
+	pool = dial(t, []string{up.URL()}, WithMaxBlockLag(10), WithHealthChecks(HealthPolicy{}))
+	if pool.health.Interval != 0 {
+		t.Errorf("got health checks every %s after disabling them", pool.health.Interval)
+	}
+	pool = dial(t, []string{up.URL()})
+	if pool.health.Interval != 0 {
+		t.Errorf("got health checks every %s by default", pool.health.Interval)
+	}
+}
@@ -0,0 +1,24 @@
+package provider
+
+import "time"
+
+// hedgeConfig configures hedged requests
+type hedgeConfig struct {
+	// Delay is how long the first endpoint is given before the request is
+	// also sent to the second
+	Delay time.Duration
+}
+
+// WithHedging makes the pool race every request against its first two
+// available endpoints and use the first successful response, cancelling the
+// other request. The second endpoint only gets the request if the first has
+// not answered within delay, or as soon as the first fails with an error that
+// is failed over, so a delay around the usual p95 latency cuts tail latency at
+// the cost of a few extra requests; a delay of zero sends every request to
+// both endpoints at once. Hedging trades request count, and so rate limit and
+// billing, for latency.
+func WithHedging(delay time.Duration) Option {
+	return func(p *Pool) {
+		p.hedge = &hedgeConfig{Delay: delay}
+	}
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHedgingSlowEndpoint(t *testing.T) {
+	slow, fast := newNode(t, 1, 100), newNode(t, 2, 100)
+	slow.delay.Store(int64(time.Second))
+	pool := dial(t, []string{slow.URL(), fast.URL()}, WithHedging(20*time.Millisecond))
+	start := time.Now()
+	checkAnswered(t, pool, 2)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("hedged call took %s, want the latency of the fast endpoint", elapsed)
+	}
+	if n := fast.requests.Load(); n != 1 {
+		t.Errorf("fast endpoint got %d requests, want 1", n)
+	}
+}
+
+func TestHedgingWaitsForDelay(t *testing.T) {
+	first, second := newNode(t, 1, 100), newNode(t, 2, 100)
+	pool := dial(t, []string{first.URL(), second.URL()}, WithHedging(time.Second))
+	checkAnswered(t, pool, 1)
+	if n := second.requests.Load(); n != 0 {
+		t.Errorf("second endpoint got %d requests before the hedging delay", n)
+	}
+
+	// Without a delay, both endpoints get every request
+	pool = dial(t, []string{first.URL(), second.URL()}, WithHedging(0))
+	second.delay.Store(int64(10 * time.Millisecond))
+	checkAnswered(t, pool, 1)
+	if n := second.requests.Load(); n != 1 {
+		t.Errorf("second endpoint got %d requests without a hedging delay, want 1", n)
+	}
+}
+
+func TestHedgingFailsFast(t *testing.T) {
+	down, up := newNode(t, 1, 100), newNode(t, 2, 100)
+	down.status.Store(http.StatusServiceUnavailable)
+	for _, urls := range [][]string{{down.URL(), up.URL()}, {refusedURL(t), up.URL()}} {
+		pool := dial(t, urls, WithHedging(time.Hour))
+		start := time.Now()
+		checkAnswered(t, pool, 2)
+		// A failed endpoint does not hold the next one back for the delay
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("call took %s after the first endpoint failed", elapsed)
+		}
+	}
+}
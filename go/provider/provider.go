@@ -0,0 +1,351 @@
+// Package provider spreads the requests of a multicall client over several
+// RPC endpoints of the same chain.
+//
+// A Pool implements the interfaces the multicall package needs from a node,
+// so it can be passed to multicall.NewClient or multicall.NewClientForChain in
+// place of a single ethclient.Client:
+//
+//	pool, err := provider.Dial(ctx, []string{primaryURL, backupURL})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer pool.Close()
+//	mc, err := multicall.NewClientForChain(ctx, pool)
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Endpoint configures one RPC endpoint of a Pool
+type Endpoint struct {
+	// URL of the endpoint
+	URL string
+
+	// Headers are added to every request to the endpoint, for providers that
+	// take their API key in a header rather than in the URL
+	Headers http.Header
+	// Auth, if set, is called before every request to add authentication
+	// headers, such as an Authorization header with a token that is refreshed
+	// as it expires
+	Auth rpc.HTTPAuth
+	// Proxy is the URL of the HTTP, HTTPS or SOCKS5 proxy requests are sent
+	// through. Nil means the proxy configured by the environment, as with
+	// http.ProxyFromEnvironment.
+	Proxy *url.URL
+	// TLSConfig configures TLS for https:// and wss:// endpoints, such as the
+	// client certificates of mutual TLS or a private certificate authority,
+	// see LoadClientTLS. Nil means the default configuration.
+	TLSConfig *tls.Config
+
+	// RateLimit is the number of requests per second the pool sends to the
+	// endpoint, or of compute units if ComputeUnits is set. Requests over the
+	// limit wait for their turn. Zero means no limit.
+	RateLimit float64
+	// Burst is the number of requests, or compute units, that may be sent at
+	// once before RateLimit applies. Zero means RateLimit, or 1 if RateLimit
+	// is lower.
+	Burst float64
+	// Archive marks archive nodes, which serve the state of any block. Other
+	// endpoints are assumed to be full nodes, only keeping recent state, see
+	// WithArchiveThreshold.
+	Archive bool
+
+	// ComputeUnits is the cost of each JSON-RPC method, such as "eth_call",
+	// for providers that meter requests by compute units rather than count.
+	// Methods not listed cost one unit.
+	ComputeUnits map[string]float64
+}
+
+// cost returns the number of rate limit tokens a call of method takes
+func (e *Endpoint) cost(method string) float64 {
+	if units, ok := e.ComputeUnits[method]; ok {
+		return units
+	}
+	return 1
+}
+
+// endpoint is a connected Endpoint along with its state in the pool
+type endpoint struct {
+	Endpoint
+	client  *ethclient.Client
+	limiter *tokenBucket
+	health  endpointHealth
+}
+
+// Pool sends requests to the first available of several endpoints serving
+// the same chain, failing over to the next one when an endpoint is down or
+// rejects a request, as decided by the FailoverPolicy.
+type Pool struct {
+	policy           FailoverPolicy
+	hedge            *hedgeConfig
+	health           HealthPolicy
+	healthSet        bool
+	latencyRouting   bool
+	maxBlockLag      uint64
+	archiveThreshold uint64
+
+	mu        sync.Mutex
+	endpoints []*endpoint
+	bestHead  uint64
+
+	// stop ends the health checks, which wg waits for
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// Option configures a Pool
+type Option func(*Pool)
+
+// Dial connects to the endpoints at urls, which are tried in order
+func Dial(ctx context.Context, urls []string, opts ...Option) (*Pool, error) {
+	endpoints := make([]Endpoint, len(urls))
+	for i, url := range urls {
+		endpoints[i] = Endpoint{URL: url}
+	}
+	return DialEndpoints(ctx, endpoints, opts...)
+}
+
+// DialEndpoints connects to endpoints, which are tried in order
+func DialEndpoints(ctx context.Context, endpoints []Endpoint, opts ...Option) (*Pool, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("provider: no endpoints")
+	}
+	p := &Pool{
+		policy:           DefaultFailoverPolicy,
+		health:           DefaultHealthPolicy,
+		archiveThreshold: DefaultArchiveThreshold,
+	}
+	p.health.Interval = 0
+	for _, opt := range opts {
+		opt(p)
+	}
+	// Heads are only kept current for every endpoint by health checks
+	if p.maxBlockLag > 0 && !p.healthSet {
+		p.health.Interval = DefaultHealthPolicy.Interval
+	}
+	for _, e := range endpoints {
+		rpcClient, err := e.dial(ctx)
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("provider: dial %s: %w", e.URL, err)
+		}
+		if e.RateLimit < 0 {
+			p.Close()
+			return nil, fmt.Errorf("provider: negative rate limit for %s", e.URL)
+		}
+		connected := &endpoint{Endpoint: e, client: ethclient.NewClient(rpcClient)}
+		if e.RateLimit > 0 {
+			connected.limiter = newTokenBucket(e.RateLimit, e.Burst)
+		}
+		p.endpoints = append(p.endpoints, connected)
+	}
+	if p.health.Interval > 0 {
+		var healthCtx context.Context
+		healthCtx, p.stop = context.WithCancel(context.Background())
+		p.wg.Add(1)
+		go p.checkHealth(healthCtx)
+	}
+	return p, nil
+}
+
+// Close stops the health checks and closes the connections to every endpoint
+func (p *Pool) Close() {
+	if p.stop != nil {
+		p.stop()
+		p.wg.Wait()
+	}
+	for _, e := range p.endpoints {
+		e.client.Close()
+	}
+}
+
+// do runs request, a call of the JSON-RPC method at block, nil for the
+// latest block, against the first endpoint that serves it, failing over
+// according to the policy. With hedging, the request is raced against the
+// first two endpoints.
+func do[R any](ctx context.Context, p *Pool, method string, block *big.Int, request func(ctx context.Context, e *endpoint) (R, error)) (R, error) {
+	candidates, err := p.route(ctx, block)
+	if err != nil {
+		var zero R
+		return zero, err
+	}
+	if p.policy.MaxAttempts > 0 && len(candidates) > p.policy.MaxAttempts {
+		candidates = candidates[:p.policy.MaxAttempts]
+	}
+	var errs []error
+	for len(candidates) > 0 {
+		n := 1
+		if p.hedge != nil && len(candidates) > 1 {
+			n = 2
+		}
+		result, failed, err := race(ctx, p, method, candidates[:n], request)
+		if err == nil || failed == nil {
+			return result, err
+		}
+		errs = append(errs, failed...)
+		candidates = candidates[n:]
+	}
+	var zero R
+	return zero, fmt.Errorf("provider: every endpoint failed: %w", errors.Join(errs...))
+}
+
+// attempt sends request to e once its rate limit allows, and records how it
+// went in the statistics of e. Only failures that are failed over count as
+// errors, as the others are not the endpoint's fault.
+func attempt[R any](ctx context.Context, p *Pool, e *endpoint, method string, request func(ctx context.Context, e *endpoint) (R, error)) (R, error) {
+	if e.limiter != nil {
+		if err := e.limiter.wait(ctx, e.cost(method)); err != nil {
+			var zero R
+			return zero, err
+		}
+	}
+	start := time.Now()
+	result, err := request(ctx, e)
+	if ctx.Err() == nil {
+		p.record(e, time.Since(start), err != nil && p.policy.shouldFailover(err))
+	}
+	return result, err
+}
+
+// race sends request to the endpoints, starting each one a hedging delay
+// after the previous, or as soon as the previous fails, and returns the first
+// success. The requests still in flight are then cancelled. If the request
+// fails on an endpoint with an error that is not failed over, that error is
+// returned; otherwise, once every endpoint failed, race returns their errors
+// as failed.
+func race[R any](ctx context.Context, p *Pool, method string, endpoints []*endpoint, request func(ctx context.Context, e *endpoint) (R, error)) (result R, failed []error, err error) {
+	if len(endpoints) == 1 {
+		result, err = attempt(ctx, p, endpoints[0], method, request)
+		if err == nil || ctx.Err() != nil || !p.policy.shouldFailover(err) {
+			return result, nil, err
+		}
+		return result, []error{fmt.Errorf("%s: %w", endpoints[0].URL, err)}, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type outcome struct {
+		e      *endpoint
+		result R
+		err    error
+	}
+	outcomes := make(chan outcome, len(endpoints))
+	started := 0
+	start := func() {
+		e := endpoints[started]
+		started++
+		go func() {
+			result, err := attempt(ctx, p, e, method, request)
+			outcomes <- outcome{e, result, err}
+		}()
+	}
+	start()
+	timer := time.NewTimer(p.hedge.Delay)
+	defer timer.Stop()
+	for pending := 1; pending > 0; {
+		// The timer only hedges requests that are slow to answer
+		var hedge <-chan time.Time
+		if started < len(endpoints) {
+			hedge = timer.C
+		}
+		select {
+		case <-hedge:
+			start()
+			pending++
+			timer.Reset(p.hedge.Delay)
+		case o := <-outcomes:
+			pending--
+			if o.err == nil {
+				return o.result, nil, nil
+			}
+			if ctx.Err() != nil || !p.policy.shouldFailover(o.err) {
+				return o.result, nil, o.err
+			}
+			failed = append(failed, fmt.Errorf("%s: %w", o.e.URL, o.err))
+			err = o.err
+			if started < len(endpoints) {
+				start()
+				pending++
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(p.hedge.Delay)
+			}
+		}
+	}
+	return result, failed, err
+}
+
+// CallContract executes an eth_call on the first available endpoint
+func (p *Pool) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return do(ctx, p, "eth_call", blockNumber, func(ctx context.Context, e *endpoint) ([]byte, error) {
+		return e.client.CallContract(ctx, msg, blockNumber)
+	})
+}
+
+// CallContractAtHash executes an eth_call at the block with the given hash on
+// the first available endpoint. As the age of the block is unknown, the
+// request is not routed to archive endpoints.
+func (p *Pool) CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	return do(ctx, p, "eth_call", nil, func(ctx context.Context, e *endpoint) ([]byte, error) {
+		return e.client.CallContractAtHash(ctx, msg, blockHash)
+	})
+}
+
+// EstimateGas executes an eth_estimateGas on the first available endpoint
+func (p *Pool) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return do(ctx, p, "eth_estimateGas", nil, func(ctx context.Context, e *endpoint) (uint64, error) {
+		return e.client.EstimateGas(ctx, msg)
+	})
+}
+
+// CodeAt returns the code of account from the first available endpoint
+func (p *Pool) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return do(ctx, p, "eth_getCode", blockNumber, func(ctx context.Context, e *endpoint) ([]byte, error) {
+		return e.client.CodeAt(ctx, account, blockNumber)
+	})
+}
+
+// ChainID returns the chain ID reported by the first available endpoint
+func (p *Pool) ChainID(ctx context.Context) (*big.Int, error) {
+	return do(ctx, p, "eth_chainId", nil, func(ctx context.Context, e *endpoint) (*big.Int, error) {
+		return e.client.ChainID(ctx)
+	})
+}
+
+// BlockNumber returns the latest block number of the first available endpoint
+func (p *Pool) BlockNumber(ctx context.Context) (uint64, error) {
+	return do(ctx, p, "eth_blockNumber", nil, func(ctx context.Context, e *endpoint) (uint64, error) {
+		head, err := e.client.BlockNumber(ctx)
+		if err == nil {
+			p.recordHead(e, head)
+		}
+		return head, err
+	})
+}
+
+// HeaderByNumber returns the header of the block with the given number, or
+// of the latest block if number is nil, from the first available endpoint
+func (p *Pool) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return do(ctx, p, "eth_getBlockByNumber", number, func(ctx context.Context, e *endpoint) (*types.Header, error) {
+		return e.client.HeaderByNumber(ctx, number)
+	})
+}
@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// node is a JSON-RPC endpoint served over HTTP, answering eth_call with its
+// id so that tests can tell which endpoint of a pool served a request
+type node struct {
+	id     byte
+	head   atomic.Uint64
+	delay  atomic.Int64
+	status atomic.Int32
+	revert atomic.Bool
+	// requests counts the HTTP requests the node received
+	requests atomic.Int32
+
+	rpc    *rpc.Server
+	server *httptest.Server
+}
+
+// newNode starts a node whose latest block is head
+func newNode(t *testing.T, id byte, head uint64) *node {
+	t.Helper()
+	n := &node{id: id, rpc: rpc.NewServer()}
+	n.head.Store(head)
+	if err := n.rpc.RegisterName("eth", &ethAPI{n}); err != nil {
+		t.Fatal(err)
+	}
+	n.server = httptest.NewServer(n)
+	t.Cleanup(func() {
+		n.server.Close()
+		n.rpc.Stop()
+	})
+	return n
+}
+
+// URL returns the URL of the node
+func (n *node) URL() string {
+	return n.server.URL
+}
+
+// ServeHTTP answers after the node's delay, failing with its HTTP status if
+// it has one
+func (n *node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.requests.Add(1)
+	if delay := time.Duration(n.delay.Load()); delay > 0 {
+		// Reading the body lets the server notice a canceled request
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if status := n.status.Load(); status != 0 {
+		http.Error(w, http.StatusText(int(status)), int(status))
+		return
+	}
+	n.rpc.ServeHTTP(w, r)
+}
+
+// ethAPI is the eth namespace of a node
+type ethAPI struct {
+	n *node
+}
+
+func (api *ethAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1))
+}
+
+func (api *ethAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(api.n.head.Load())
+}
+
+func (api *ethAPI) Call(args, block json.RawMessage) (hexutil.Bytes, error) {
+	if api.n.revert.Load() {
+		return nil, errors.New("execution reverted")
+	}
+	return hexutil.Bytes{api.n.id}, nil
+}
+
+// refusedURL returns the URL of an endpoint that refuses connections
+func refusedURL(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	return server.URL
+}
+
+func dial(t *testing.T, urls []string, opts ...Option) *Pool {
+	t.Helper()
+	pool, err := Dial(context.Background(), urls, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// call executes an eth_call through pool at block and returns the id of the
+// node that answered it
+func call(pool *Pool, block *big.Int) (byte, error) {
+	output, err := pool.CallContract(context.Background(), ethereum.CallMsg{To: &common.Address{1}}, block)
+	if err != nil {
+		return 0, err
+	}
+	if len(output) != 1 {
+		return 0, errors.New("unexpected output")
+	}
+	return output[0], nil
+}
+
+func checkAnswered(t *testing.T, pool *Pool, id byte) {
+	t.Helper()
+	got, err := call(pool, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != id {
+		t.Errorf("call answered by node %d, want %d", got, id)
+	}
+}
+
+func TestPoolFailsOver(t *testing.T) {
+	down, up := newNode(t, 1, 100), newNode(t, 2, 100)
+	down.status.Store(http.StatusServiceUnavailable)
+	pool := dial(t, []string{down.URL(), up.URL()})
+	checkAnswered(t, pool, 2)
+	// The failed endpoint is left alone during its cooldown
+	checkAnswered(t, pool, 2)
+	if n := down.requests.Load(); n != 1 {
+		t.Errorf("failed endpoint got %d requests, want 1", n)
+	}
+	stats := pool.Stats()
+	if stats[0].Healthy || stats[0].ErrorRate != 1 || !stats[1].Healthy {
+		t.Errorf("got stats %+v, want the first endpoint unhealthy", stats)
+	}
+}
+
+func TestPoolFailsOverRefusedConnections(t *testing.T) {
+	up := newNode(t, 2, 100)
+	pool := dial(t, []string{refusedURL(t), up.URL()})
+	checkAnswered(t, pool, 2)
+}
+
+func TestPoolDoesNotFailOverReverts(t *testing.T) {
+	first, second := newNode(t, 1, 100), newNode(t, 2, 100)
+	first.revert.Store(true)
+	pool := dial(t, []string{first.URL(), second.URL()})
+	if _, err := call(pool, nil); err == nil || !strings.Contains(err.Error(), "execution reverted") {
+		t.Errorf("got error %v, want the revert", err)
+	}
+	if n := second.requests.Load(); n != 0 {
+		t.Errorf("revert retried on the second endpoint, which got %d requests", n)
+	}
+	// A revert is not the endpoint's fault
+	if stats := pool.Stats(); !stats[0].Healthy {
+		t.Error("endpoint unhealthy after a revert")
+	}
+}
+
+func TestPoolEveryEndpointFailed(t *testing.T) {
+	first, second := newNode(t, 1, 100), newNode(t, 2, 100)
+	first.status.Store(http.StatusBadGateway)
+	second.status.Store(http.StatusTooManyRequests)
+	pool := dial(t, []string{first.URL(), second.URL()})
+	_, err := call(pool, nil)
+	if err == nil || !strings.Contains(err.Error(), first.URL()) || !strings.Contains(err.Error(), second.URL()) {
+		t.Errorf("got error %v, want the errors of both endpoints", err)
+	}
+	var httpErr rpc.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Errorf("error %v does not wrap the HTTP errors", err)
+	}
+
+	limited := dial(t, []string{first.URL(), second.URL()}, WithFailoverPolicy(FailoverPolicy{MaxAttempts: 1}))
+	second.requests.Store(0)
+	if _, err := call(limited, nil); err == nil {
+		t.Error("call succeeded on failing endpoints")
+	}
+	if n := second.requests.Load(); n != 0 {
+		t.Errorf("second endpoint got %d requests with a single attempt allowed", n)
+	}
+}
+
+func TestDialEndpoints(t *testing.T) {
+	if _, err := Dial(context.Background(), nil); err == nil {
+		t.Error("pool dialed without endpoints")
+	}
+	up := newNode(t, 1, 100)
+	if _, err := DialEndpoints(context.Background(), []Endpoint{{URL: up.URL(), RateLimit: -1}}); err == nil {
+		t.Error("pool dialed with a negative rate limit")
+	}
+	pool := dial(t, []string{up.URL()})
+	chainID, err := pool.ChainID(context.Background())
+	if err != nil || chainID.Int64() != 1 {
+		t.Errorf("got chain ID %v, %v, want 1", chainID, err)
+	}
+}
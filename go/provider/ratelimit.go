@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a token bucket rate limiter: tokens accumulate at rate per
+// second up to burst, and a request costing n tokens waits until they are
+// available
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate, burst float64) *tokenBucket {
+	if burst <= 0 {
+		burst = max(1, rate)
+	}
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait takes n tokens from the bucket, blocking until they are available or
+// ctx is done. Requests costing more than the burst wait for a full bucket.
+func (b *tokenBucket) wait(ctx context.Context, n float64) error {
+	n = min(n, b.burst)
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	// Take the tokens up front, going into debt, so that waiting requests are
+	// served in order
+	b.tokens -= n
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens += n
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	bucket := newTokenBucket(100, 2)
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := bucket.wait(context.Background(), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Millisecond {
+		t.Errorf("burst waited %s", elapsed)
+	}
+	// The next token accumulates in 10ms
+	if err := bucket.wait(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 8*time.Millisecond {
+		t.Errorf("request over the burst waited %s, want about 10ms", elapsed)
+	}
+
+	// A request costing more than the burst waits for a full bucket only
+	bucket = newTokenBucket(1000, 2)
+	start = time.Now()
+	if err := bucket.wait(context.Background(), 50); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Millisecond {
+		t.Errorf("request costing more than the burst waited %s with a full bucket", elapsed)
+	}
+}
+
+func TestTokenBucketCancel(t *testing.T) {
+	bucket := newTokenBucket(1, 1)
+	if err := bucket.wait(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := bucket.wait(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want the context's", err)
+	}
+	// The tokens taken by the canceled request are given back
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+	if bucket.tokens < -0.1 {
+		t.Errorf("bucket holds %f tokens after a canceled request, want about 0", bucket.tokens)
+	}
+}
+
+func TestEndpointCost(t *testing.T) {
+	e := Endpoint{ComputeUnits: map[string]float64{"eth_call": 26}}
+	if got := e.cost("eth_call"); got != 26 {
+		t.Errorf("eth_call costs %f, want 26", got)
+	}
+	if got := e.cost("eth_chainId"); got != 1 {
+		t.Errorf("unlisted method costs %f, want 1", got)
+	}
+}
+
+func TestPoolRateLimit(t *testing.T) {
+	up := newNode(t, 1, 100)
+	pool, err := DialEndpoints(context.Background(), []Endpoint{{
+		URL:          up.URL(),
+		RateLimit:    100,
+		Burst:        20,
+		ComputeUnits: map[string]float64{"eth_call": 10},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		checkAnswered(t, pool, 1)
+	}
+	// The third call waits for 10 units, at 100 per second
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("3 calls of 10 units took %s with a burst of 20 units, want about 100ms", elapsed)
+	}
+}
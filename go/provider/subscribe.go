@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Subscriptions need a ws:// or wss:// endpoint, or an IPC endpoint given by
+// the path of its socket. The connection of such endpoints is re-established
+// by the next request after it drops, and the subscriptions below are
+// re-established along with it.
+
+// maxResubscribeBackoff bounds the delay between attempts to re-establish a
+// subscription
+const maxResubscribeBackoff = 10 * time.Second
+
+// SubscribeNewHead subscribes to the headers of new blocks on the first
+// available endpoint that supports subscriptions. When the subscription
+// fails, for example because the connection dropped, it is re-established,
+// on another endpoint if need be, so ch keeps receiving headers until the
+// subscription is unsubscribed. Blocks may be missed or repeated around a
+// resubscription. The headers also keep the heads used by WithMaxBlockLag
+// up to date.
+func (p *Pool) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return resubscribe(ctx, p, ch, func(ctx context.Context, e *endpoint, events chan<- *types.Header) (ethereum.Subscription, error) {
+		return e.client.SubscribeNewHead(ctx, events)
+	}, func(e *endpoint, header *types.Header) {
+		p.recordHead(e, header.Number.Uint64())
+	})
+}
+
+// SubscribeFilterLogs subscribes to the logs matching q like
+// SubscribeNewHead, re-establishing the subscription when it fails
+func (p *Pool) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return resubscribe(ctx, p, ch, func(ctx context.Context, e *endpoint, events chan<- types.Log) (ethereum.Subscription, error) {
+		return e.client.SubscribeFilterLogs(ctx, q, events)
+	}, nil)
+}
+
+// resubscribe subscribes to events with subscribe on the first endpoint that
+// supports it and forwards them to ch, calling observe on every event, until
+// the returned subscription is unsubscribed. The first subscription is made
+// with ctx; later ones are retried with backoff.
+func resubscribe[T any](ctx context.Context, p *Pool, ch chan<- T, subscribe func(ctx context.Context, e *endpoint, events chan<- T) (ethereum.Subscription, error), observe func(e *endpoint, event T)) (ethereum.Subscription, error) {
+	connect := func(ctx context.Context) (ethereum.Subscription, error) {
+		var errs []error
+		for _, e := range p.candidates() {
+			events := make(chan T)
+			sub, err := subscribe(ctx, e, events)
+			if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+				continue
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", e.URL, err))
+				continue
+			}
+			return forward(e, sub, events, ch, observe), nil
+		}
+		if len(errs) == 0 {
+			return nil, errors.New("provider: no endpoint supports subscriptions, use a ws:// or IPC endpoint")
+		}
+		return nil, fmt.Errorf("provider: subscribe: %w", errors.Join(errs...))
+	}
+
+	first, err := connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return event.ResubscribeErr(maxResubscribeBackoff, func(ctx context.Context, _ error) (event.Subscription, error) {
+		if first != nil {
+			sub := first
+			first = nil
+			return sub, nil
+		}
+		return connect(ctx)
+	}), nil
+}
+
+// forward relays the events of sub, received on events from e, to ch
+func forward[T any](e *endpoint, sub ethereum.Subscription, events <-chan T, ch chan<- T, observe func(e *endpoint, event T)) ethereum.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case ev := <-events:
+				if observe != nil {
+					observe(e, ev)
+				}
+				select {
+				case ch <- ev:
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
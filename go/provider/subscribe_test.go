@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// headsAPI is the eth namespace of a node serving subscriptions
+type headsAPI struct {
+	n *node
+}
+
+// NewHeads sends a new header every few milliseconds, advancing the head of
+// the node
+func (api *headsAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				head := api.n.head.Add(1)
+				header := &types.Header{Number: new(big.Int).SetUint64(head), Difficulty: new(big.Int)}
+				if err := notifier.Notify(sub.ID, header); err != nil {
+					return
+				}
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// websocketURL serves n over a websocket and returns its ws:// URL
+func websocketURL(t *testing.T, n *node) string {
+	t.Helper()
+	if err := n.rpc.RegisterName("eth", &headsAPI{n}); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(n.rpc.WebsocketHandler([]string{"*"}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// receive waits for the next header on ch
+func receive(t *testing.T, ch <-chan *types.Header) *types.Header {
+	t.Helper()
+	select {
+	case header := <-ch:
+		return header
+	case <-time.After(5 * time.Second):
+		t.Fatal("no header received")
+		return nil
+	}
+}
+
+func TestSubscribeNewHead(t *testing.T) {
+	first, second := newNode(t, 1, 100), newNode(t, 2, 1000)
+	pool := dial(t, []string{websocketURL(t, first), websocketURL(t, second)})
+	ch := make(chan *types.Header)
+	sub, err := pool.SubscribeNewHead(context.Background(), ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	header := receive(t, ch)
+	if n := header.Number.Uint64(); n <= 100 || n >= 1000 {
+		t.Fatalf("got header %d, want one from the first endpoint", n)
+	}
+	if head := pool.Stats()[0].Head; head < 101 {
+		t.Errorf("got head %d for the first endpoint, want the headers to update it", head)
+	}
+
+	// Stopping the first node drops its connection, and the subscription
+	// moves on to the second
+	first.rpc.Stop()
+	deadline := time.Now().Add(5 * time.Second)
+	for receive(t, ch).Number.Uint64() < 1000 {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription not re-established on the second endpoint")
+		}
+	}
+}
+
+func TestSubscribeNewHeadUnsupported(t *testing.T) {
+	up := newNode(t, 1, 100)
+	pool := dial(t, []string{up.URL()})
+	_, err := pool.SubscribeNewHead(context.Background(), make(chan *types.Header))
+	if err == nil || !strings.Contains(err.Error(), "no endpoint supports subscriptions") {
+		t.Errorf("got error %v subscribing over HTTP, want one asking for a websocket endpoint", err)
+	}
+}
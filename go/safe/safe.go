@@ -0,0 +1,92 @@
+// Package safe reads the configuration of Safe smart accounts, formerly
+// Gnosis Safe, in bulk through Multicall3, for treasury monitors and Safe
+// indexers:
+//
+//	safes, err := safe.Safes(ctx, client, treasuries)
+//	for _, s := range safes {
+//		fmt.Printf("%s: %d of %d, nonce %s, v%s\n", s.Address, s.Threshold, len(s.Owners), s.Nonce, s.Version)
+//	}
+package safe
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ABI is the part of the Safe ABI used by the helpers
+var ABI = helper.MustParseABI("safe", safeABI)
+
+const safeABI = `[
+	{"type":"function","name":"getOwners","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address[]"}]},
+	{"type":"function","name":"getThreshold","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"nonce","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"VERSION","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]}
+]`
+
+// Safe is the configuration of a Safe. Fields the Safe failed to return are
+// left as zero values, and Err reports why.
+type Safe struct {
+	Address common.Address
+	Owners  []common.Address
+	// Threshold is the number of owners that must confirm a transaction
+	Threshold uint64
+	// Nonce is the nonce of the next transaction of the Safe
+	Nonce *big.Int
+	// Version is the version of the Safe's singleton, such as 1.3.0
+	Version string
+	// Err joins the errors of the owners, threshold, nonce and version, as
+	// read from contracts that are not Safes
+	Err error
+}
+
+// IsOwner reports whether account is an owner of the Safe
+func (s Safe) IsOwner(account common.Address) bool {
+	for _, owner := range s.Owners {
+		if owner == account {
+			return true
+		}
+	}
+	return false
+}
+
+// Safes fetches the owners, threshold, nonce and version of every Safe in a
+// single batch, returning them in the order of the addresses. The error is
+// only set if the batch itself failed, the failures of a Safe, such as an
+// address that is not a Safe, are reported by its Safe.Err.
+func Safes(ctx context.Context, client *multicall.Client, safes []common.Address, opts ...multicall.CallOption) ([]Safe, error) {
+	batch := client.NewBatch()
+	for _, safe := range safes {
+		batch.Add(safe, ABI, "getOwners").AllowFailure().
+			Add(safe, ABI, "getThreshold").AllowFailure().
+			Add(safe, ABI, "nonce").AllowFailure().
+			Add(safe, ABI, "VERSION").AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	state := make([]Safe, len(safes))
+	for i, safe := range safes {
+		var ownersErr, thresholdErr, nonceErr, versionErr error
+		state[i] = Safe{Address: safe}
+		if ownersErr = results[4*i].Error(safe, "getOwners"); ownersErr == nil {
+			state[i].Owners = results[4*i].Values[0].([]common.Address)
+		}
+		if thresholdErr = results[4*i+1].Error(safe, "getThreshold"); thresholdErr == nil {
+			state[i].Threshold = results[4*i+1].Values[0].(*big.Int).Uint64()
+		}
+		if nonceErr = results[4*i+2].Error(safe, "nonce"); nonceErr == nil {
+			state[i].Nonce = results[4*i+2].Values[0].(*big.Int)
+		}
+		if versionErr = results[4*i+3].Error(safe, "VERSION"); versionErr == nil {
+			state[i].Version = results[4*i+3].Values[0].(string)
+		}
+		state[i].Err = errors.Join(ownersErr, thresholdErr, nonceErr, versionErr)
+	}
+	return state, nil
+}
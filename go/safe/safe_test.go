@@ -0,0 +1,58 @@
+package safe_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+	"github.com/john-na4/multicall3/go/safe"
+)
+
+var (
+	treasury = common.HexToAddress("0xFEB4acf3df3cDEA7399794D0869ef76A6EfAff52")
+	legacy   = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	account  = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	owners   = []common.Address{{19: 1}, {19: 2}, {19: 3}}
+)
+
+func TestSafes(t *testing.T) {
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		treasury: fake.Methods(safe.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getOwners":    fake.Returns(owners),
+			"getThreshold": fake.Returns(big.NewInt(2)),
+			"nonce":        fake.Returns(big.NewInt(41)),
+			"VERSION":      fake.Returns("1.3.0"),
+		}),
+		// A Safe without VERSION
+		legacy: fake.Methods(safe.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getOwners":    fake.Returns(owners[:1]),
+			"getThreshold": fake.Returns(big.NewInt(1)),
+			"nonce":        fake.Returns(big.NewInt(0)),
+		}),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	safes, err := safe.Safes(context.Background(), client, []common.Address{treasury, legacy, account})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := safes[0]; got.Err != nil || !reflect.DeepEqual(got.Owners, owners) || got.Threshold != 2 || got.Nonce.Int64() != 41 || got.Version != "1.3.0" {
+		t.Errorf("got Safe %+v, want 2 of 3 owners at nonce 41", got)
+	}
+	if got := safes[0]; !got.IsOwner(owners[1]) || got.IsOwner(account) {
+		t.Errorf("got owners %v, want %s and not %s", got.Owners, owners[1], account)
+	}
+	if got := safes[1]; got.Threshold != 1 || got.Nonce.Sign() != 0 || got.Version != "" || !errors.Is(got.Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got Safe %+v, want its configuration and the revert of VERSION", got)
+	}
+	// Accounts without code return no data, which does not decode
+	if got := safes[2]; got.Owners != nil || got.Nonce != nil || !errors.Is(got.Err, multicall.ErrDecode) {
+		t.Errorf("got Safe %+v, want the errors of an account", got)
+	}
+}
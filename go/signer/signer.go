@@ -0,0 +1,225 @@
+// Package signer signs the transactions of a multicall client, such as those
+// of multicall.Client.SendAggregate, with keys that are not handed to the
+// program in plain text: go-ethereum keystore files, a clef instance, or a
+// Ledger or Trezor hardware wallet.
+//
+//	s, err := signer.Keystore(keystoreDir, account, passphrase, chainID)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer s.Close()
+//	tx, err := mc.SendAggregate(ctx, s.TransactOpts(), calls)
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Signer signs transactions for a single account on a single chain
+type Signer struct {
+	account accounts.Account
+	sign    func(tx *types.Transaction) (*types.Transaction, error)
+	close   func() error
+}
+
+// Address returns the address of the account of the signer
+func (s *Signer) Address() common.Address {
+	return s.account.Address
+}
+
+// TransactOpts returns transact options that sign transactions from the
+// account of the signer, to be passed to the transaction methods of a
+// multicall client or to go-ethereum bindings. Each call returns new options,
+// whose nonce, gas and fees may be set without affecting the others.
+func (s *Signer) TransactOpts() *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: s.account.Address,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != s.account.Address {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.sign(tx)
+		},
+		Context: context.Background(),
+	}
+}
+
+// Close releases the keystore, connection or device of the signer
+func (s *Signer) Close() error {
+	if s.close == nil {
+		return nil
+	}
+	return s.close()
+}
+
+// walletSigner returns a signer for account of wallet, closed by close
+func walletSigner(wallet accounts.Wallet, account accounts.Account, chainID *big.Int, close func() error) *Signer {
+	return &Signer{
+		account: account,
+		sign: func(tx *types.Transaction) (*types.Transaction, error) {
+			return wallet.SignTx(account, tx, chainID)
+		},
+		close: close,
+	}
+}
+
+// Keystore returns a signer for the account with the given address in the
+// go-ethereum keystore directory dir, such as the keystore of a geth data
+// directory, unlocked with passphrase until the signer is closed
+func Keystore(dir string, address common.Address, passphrase string, chainID *big.Int) (*Signer, error) {
+	if chainID == nil {
+		return nil, bind.ErrNoChainID
+	}
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+	account, err := ks.Find(accounts.Account{Address: address})
+	if err != nil {
+		return nil, fmt.Errorf("signer: find %s in keystore %s: %w", address, dir, err)
+	}
+	if err := ks.Unlock(account, passphrase); err != nil {
+		return nil, fmt.Errorf("signer: unlock %s: %w", address, err)
+	}
+	return &Signer{
+		account: account,
+		sign: func(tx *types.Transaction) (*types.Transaction, error) {
+			return ks.SignTx(account, tx, chainID)
+		},
+		close: func() error {
+			return ks.Lock(account.Address)
+		},
+	}, nil
+}
+
+// KeyFile returns a signer for the key stored in the encrypted go-ethereum
+// key file at path, decrypted with passphrase
+func KeyFile(path, passphrase string, chainID *big.Int) (*Signer, error) {
+	if chainID == nil {
+		return nil, bind.ErrNoChainID
+	}
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("signer: read key file: %w", err)
+	}
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("signer: decrypt key file %s: %w", path, err)
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	return &Signer{
+		account: accounts.Account{Address: key.Address},
+		sign: func(tx *types.Transaction) (*types.Transaction, error) {
+			return types.SignTx(tx, signer, key.PrivateKey)
+		},
+	}, nil
+}
+
+// Clef returns a signer that sends transactions from the account with the
+// given address to the clef instance, or other external signer implementing
+// its API, listening at endpoint, such as the path of its IPC socket or an
+// HTTP URL, to be approved according to its rules or by its operator
+func Clef(endpoint string, address common.Address, chainID *big.Int) (*Signer, error) {
+	if chainID == nil {
+		return nil, bind.ErrNoChainID
+	}
+	clef, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("signer: connect to clef: %w", err)
+	}
+	// The external signer does not support being closed
+	return walletSigner(clef, accounts.Account{Address: address}, chainID, nil), nil
+}
+
+// Ledger returns a signer for the account at the derivation path of the first
+// Ledger device connected, such as accounts.DefaultBaseDerivationPath, whose
+// Ethereum app must be open. Each transaction is confirmed on the device.
+func Ledger(path accounts.DerivationPath, chainID *big.Int) (*Signer, error) {
+	if chainID == nil {
+		return nil, bind.ErrNoChainID
+	}
+	hub, err := usbwallet.NewLedgerHub()
+	if err != nil {
+		return nil, fmt.Errorf("signer: Ledger: %w", err)
+	}
+	return hardwareSigner("Ledger", []*usbwallet.Hub{hub}, path, nil, chainID)
+}
+
+// Trezor returns a signer for the account at the derivation path of the first
+// Trezor device connected, like Ledger. Devices protected with a PIN ask for
+// it with the matrix they display: prompt is called with "pin" for the
+// positions of its digits in the matrix, and with "passphrase" for devices
+// with a passphrase. A nil prompt fails for such devices.
+func Trezor(path accounts.DerivationPath, prompt func(request string) (string, error), chainID *big.Int) (*Signer, error) {
+	if chainID == nil {
+		return nil, bind.ErrNoChainID
+	}
+	// Older firmware talks HID, newer WebUSB, and hosts may only support one
+	var hubs []*usbwallet.Hub
+	var errs []error
+	for _, newHub := range []func() (*usbwallet.Hub, error){usbwallet.NewTrezorHubWithHID, usbwallet.NewTrezorHubWithWebUSB} {
+		hub, err := newHub()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		hubs = append(hubs, hub)
+	}
+	if len(hubs) == 0 {
+		return nil, fmt.Errorf("signer: Trezor: %w", errors.Join(errs...))
+	}
+	return hardwareSigner("Trezor", hubs, path, prompt, chainID)
+}
+
+// hardwareSigner opens the first wallet found by hubs and derives the account
+// at path, answering the requests of the device with prompt
+func hardwareSigner(device string, hubs []*usbwallet.Hub, path accounts.DerivationPath, prompt func(request string) (string, error), chainID *big.Int) (*Signer, error) {
+	var wallet accounts.Wallet
+	for _, hub := range hubs {
+		if wallets := hub.Wallets(); len(wallets) > 0 {
+			wallet = wallets[0]
+			break
+		}
+	}
+	if wallet == nil {
+		return nil, fmt.Errorf("signer: no %s connected", device)
+	}
+	err := wallet.Open("")
+	for i := 0; err != nil && i < 2; i++ {
+		var request string
+		switch {
+		case errors.Is(err, usbwallet.ErrTrezorPINNeeded):
+			request = "pin"
+		case errors.Is(err, usbwallet.ErrTrezorPassphraseNeeded):
+			request = "passphrase"
+		default:
+			return nil, fmt.Errorf("signer: open %s: %w", device, err)
+		}
+		if prompt == nil {
+			return nil, fmt.Errorf("signer: open %s: %w", device, err)
+		}
+		answer, promptErr := prompt(request)
+		if promptErr != nil {
+			return nil, fmt.Errorf("signer: open %s: %w", device, promptErr)
+		}
+		err = wallet.Open(answer)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("signer: open %s: %w", device, err)
+	}
+	account, err := wallet.Derive(path, true)
+	if err != nil {
+		wallet.Close()
+		return nil, fmt.Errorf("signer: derive %s account %s: %w", device, path, err)
+	}
+	return walletSigner(wallet, account, chainID, wallet.Close), nil
+}
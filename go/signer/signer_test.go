@@ -0,0 +1,161 @@
+package signer_test
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/john-na4/multicall3/go/signer"
+)
+
+var chainID = big.NewInt(1337)
+
+// checkSigns checks that s signs transactions from its address, and only
+// from it
+func checkSigns(t *testing.T, s *signer.Signer) {
+	t.Helper()
+	opts := s.TransactOpts()
+	if opts.From != s.Address() {
+		t.Errorf("got transact options from %s, want %s", opts.From, s.Address())
+	}
+	to := common.Address{0xca}
+	tx, err := opts.Signer(opts.From, types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 3, To: &to, Gas: 21000, GasFeeCap: big.NewInt(2), GasTipCap: big.NewInt(1)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from, err := types.Sender(types.LatestSignerForChainID(chainID), tx); err != nil || from != s.Address() {
+		t.Errorf("got transaction signed by %s, %v, want %s", from, err, s.Address())
+	}
+	if tx.Nonce() != 3 || *tx.To() != to {
+		t.Errorf("got transaction with nonce %d to %s, want the one signed", tx.Nonce(), tx.To())
+	}
+	if _, err := opts.Signer(common.Address{0x01}, tx); !errors.Is(err, bind.ErrNotAuthorized) {
+		t.Errorf("got error %v signing for another account, want ErrNotAuthorized", err)
+	}
+}
+
+func TestKeystore(t *testing.T) {
+	dir := t.TempDir()
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.NewAccount("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signer.Keystore(dir, account.Address, "wrong", chainID); err == nil {
+		t.Error("unlocked the keystore with the wrong passphrase")
+	}
+	if _, err := signer.Keystore(dir, common.Address{0x01}, "secret", chainID); err == nil {
+		t.Error("found an account missing from the keystore")
+	}
+	s, err := signer.Keystore(dir, account.Address, "secret", chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Address() != account.Address {
+		t.Errorf("got signer for %s, want %s", s.Address(), account.Address)
+	}
+	checkSigns(t, s)
+
+	// Closing the signer locks the account again
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	opts := s.TransactOpts()
+	if _, err := opts.Signer(opts.From, types.NewTx(&types.DynamicFeeTx{ChainID: chainID})); !errors.Is(err, keystore.ErrLocked) {
+		t.Errorf("got error %v signing after closing, want ErrLocked", err)
+	}
+}
+
+func TestKeyFile(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{Address: crypto.PubkeyToAddress(key.PublicKey), PrivateKey: key}, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(path, keyJSON, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signer.KeyFile(path, "wrong", chainID); err == nil {
+		t.Error("decrypted the key file with the wrong passphrase")
+	}
+	s, err := signer.KeyFile(path, "secret", chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Address() != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("got signer for %s, want the address of the key", s.Address())
+	}
+	checkSigns(t, s)
+	if err := s.Close(); err != nil {
+		t.Errorf("got error %v closing a key file signer", err)
+	}
+}
+
+// clefService is the part of the clef API used by external signers, signing
+// with key
+type clefService struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *clefService) Version() (string, error) {
+	return "6.1.0", nil
+}
+
+func (s *clefService) SignTransaction(args apitypes.SendTxArgs) (map[string]interface{}, error) {
+	tx, err := types.SignTx(args.ToTransaction(), types.LatestSignerForChainID((*big.Int)(args.ChainID)), s.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": tx}, nil
+}
+
+func TestClef(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", &clefService{key: key}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	clef := httptest.NewServer(server)
+	t.Cleanup(clef.Close)
+	s, err := signer.Clef(clef.URL, crypto.PubkeyToAddress(key.PublicKey), chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSigns(t, s)
+}
+
+func TestNoChainID(t *testing.T) {
+	if _, err := signer.Keystore(t.TempDir(), common.Address{}, "", nil); !errors.Is(err, bind.ErrNoChainID) {
+		t.Errorf("got error %v from Keystore, want ErrNoChainID", err)
+	}
+	if _, err := signer.KeyFile("key.json", "", nil); !errors.Is(err, bind.ErrNoChainID) {
+		t.Errorf("got error %v from KeyFile, want ErrNoChainID", err)
+	}
+	if _, err := signer.Clef("http://127.0.0.1:1", common.Address{}, nil); !errors.Is(err, bind.ErrNoChainID) {
+		t.Errorf("got error %v from Clef, want ErrNoChainID", err)
+	}
+}
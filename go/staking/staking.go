@@ -0,0 +1,197 @@
+// Package staking reads the exchange rates of liquid staking tokens, Lido's
+// stETH and wstETH and Rocket Pool's rETH, along with the balances of their
+// holders, in a single batch, so that staking dashboards get snapshots of
+// the rates and balances at the same block:
+//
+//	snapshot, err := staking.Fetch(ctx, client, staking.Mainnet, holders)
+//	for _, holding := range snapshot.Holdings {
+//		fmt.Println(holding.Holder, snapshot.Value(holding))
+//	}
+package staking
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// StETHABI is the part of the Lido stETH ABI used by the helpers
+var StETHABI = helper.MustParseABI("staking", stETHABI)
+
+// WstETHABI is the part of the Lido wstETH ABI used by the helpers
+var WstETHABI = helper.MustParseABI("staking", wstETHABI)
+
+// RETHABI is the part of the Rocket Pool rETH ABI used by the helpers
+var RETHABI = helper.MustParseABI("staking", rETHABI)
+
+const stETHABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"sharesOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"getTotalPooledEther","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"getTotalShares","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"getPooledEthByShares","stateMutability":"view","inputs":[{"name":"sharesAmount","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+const wstETHABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"stEthPerToken","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"tokensPerStEth","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+const rETHABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"getExchangeRate","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// Contracts are the addresses of the tokens to read. Tokens left as the zero
+// address are skipped.
+type Contracts struct {
+	StETH  common.Address
+	WstETH common.Address
+	RETH   common.Address
+}
+
+// Mainnet are the addresses of the tokens on Ethereum mainnet
+var Mainnet = Contracts{
+	StETH:  common.HexToAddress("0xae7ab96520DE3A18E5e111B5EaAb095312D7fE84"),
+	WstETH: common.HexToAddress("0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0"),
+	RETH:   common.HexToAddress("0xae78736Cd615f374D3085123A210448E74Fc6393"),
+}
+
+// ether is 1e18, the scale of the rates
+var ether = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// Rates are the exchange rates of the tokens, all scaled by 1e18. Rates of
+// tokens that were skipped or failed are left nil, and Err reports why.
+type Rates struct {
+	// StETHPerShare is the amount of ETH, and so stETH, a Lido share is worth
+	StETHPerShare    *big.Int
+	TotalPooledEther *big.Int
+	TotalShares      *big.Int
+	// StETHPerWstETH is the amount of stETH a wstETH unwraps to
+	StETHPerWstETH *big.Int
+	// WstETHPerStETH is the amount of wstETH a stETH wraps to
+	WstETHPerStETH *big.Int
+	// ETHPerRETH is the amount of ETH a rETH is worth
+	ETHPerRETH *big.Int
+	// Err joins the errors of the rates that failed to be read, as on chains
+	// without one of the tokens
+	Err error
+}
+
+// Holding is the balances of a holder. Balances of tokens that were skipped
+// or failed are left nil, and Err reports why.
+type Holding struct {
+	Holder common.Address
+	StETH  *big.Int
+	// StETHShares are the Lido shares the stETH balance is made of
+	StETHShares *big.Int
+	WstETH      *big.Int
+	RETH        *big.Int
+	// Err joins the errors of the balances that failed to be read
+	Err error
+}
+
+// Snapshot is the rates of the tokens and the balances of their holders at a
+// single block
+type Snapshot struct {
+	Block    uint64
+	Rates    Rates
+	Holdings []Holding
+}
+
+// Value returns the amount of ETH the balances of a holding are worth at the
+// rates of the snapshot, counting stETH one for one. Balances that failed, or
+// whose rate failed, count as zero.
+func (s *Snapshot) Value(h Holding) *big.Int {
+	value := new(big.Int)
+	if h.StETH != nil {
+		value.Add(value, h.StETH)
+	}
+	value.Add(value, convert(h.WstETH, s.Rates.StETHPerWstETH))
+	value.Add(value, convert(h.RETH, s.Rates.ETHPerRETH))
+	return value
+}
+
+// convert converts amount at a rate scaled by 1e18, or returns zero if either
+// is missing
+func convert(amount, rate *big.Int) *big.Int {
+	if amount == nil || rate == nil {
+		return new(big.Int)
+	}
+	converted := new(big.Int).Mul(amount, rate)
+	return converted.Quo(converted, ether)
+}
+
+// Fetch fetches the rates of the tokens and the balances of every holder in a
+// single batch, along with the number of the block they are read at. Batches
+// split into chunks are pinned to a single block, so the snapshot is
+// consistent either way. The error is only set if the batch itself failed,
+// the failures of calls are reported by Rates.Err and Holding.Err.
+func Fetch(ctx context.Context, client *multicall.Client, contracts Contracts, holders []common.Address, opts ...multicall.CallOption) (*Snapshot, error) {
+	blockNumber := multicall.BlockNumber()
+	batch := client.NewBatch().AddSelf(blockNumber)
+	var reads []read
+	add := func(token common.Address, contractABI abi.ABI, method string, dst **big.Int, errs *[]error, args ...interface{}) {
+		if token == (common.Address{}) {
+			return
+		}
+		batch.Add(token, contractABI, method, args...).AllowFailure()
+		reads = append(reads, read{token: token, method: method, dst: dst, errs: errs})
+	}
+
+	snapshot := &Snapshot{Holdings: make([]Holding, len(holders))}
+	var rateErrs []error
+	rates := &snapshot.Rates
+	add(contracts.StETH, StETHABI, "getPooledEthByShares", &rates.StETHPerShare, &rateErrs, ether)
+	add(contracts.StETH, StETHABI, "getTotalPooledEther", &rates.TotalPooledEther, &rateErrs)
+	add(contracts.StETH, StETHABI, "getTotalShares", &rates.TotalShares, &rateErrs)
+	add(contracts.WstETH, WstETHABI, "stEthPerToken", &rates.StETHPerWstETH, &rateErrs)
+	add(contracts.WstETH, WstETHABI, "tokensPerStEth", &rates.WstETHPerStETH, &rateErrs)
+	add(contracts.RETH, RETHABI, "getExchangeRate", &rates.ETHPerRETH, &rateErrs)
+	holdingErrs := make([][]error, len(holders))
+	for i, holder := range holders {
+		holding := &snapshot.Holdings[i]
+		holding.Holder = holder
+		add(contracts.StETH, StETHABI, "balanceOf", &holding.StETH, &holdingErrs[i], holder)
+		add(contracts.StETH, StETHABI, "sharesOf", &holding.StETHShares, &holdingErrs[i], holder)
+		add(contracts.WstETH, WstETHABI, "balanceOf", &holding.WstETH, &holdingErrs[i], holder)
+		add(contracts.RETH, RETHABI, "balanceOf", &holding.RETH, &holdingErrs[i], holder)
+	}
+
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	block, err := blockNumber.Get()
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Block = block.Uint64()
+	for i, read := range reads {
+		result := results[1+i]
+		if err := result.Error(read.token, read.method); err != nil {
+			*read.errs = append(*read.errs, err)
+			continue
+		}
+		*read.dst = result.Values[0].(*big.Int)
+	}
+	rates.Err = errors.Join(rateErrs...)
+	for i := range snapshot.Holdings {
+		snapshot.Holdings[i].Err = errors.Join(holdingErrs[i]...)
+	}
+	return snapshot, nil
+}
+
+// read is a call of Fetch and where its result goes
+type read struct {
+	token  common.Address
+	method string
+	dst    **big.Int
+	errs   *[]error
+}
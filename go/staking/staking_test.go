@@ -0,0 +1,63 @@
+package staking_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+	"github.com/john-na4/multicall3/go/staking"
+)
+
+func TestFetch(t *testing.T) {
+	// rETH is skipped, and wstETH does not implement tokensPerStEth
+	contracts := staking.Contracts{StETH: staking.Mainnet.StETH, WstETH: staking.Mainnet.WstETH}
+	caller := fake.NewCaller(map[common.Address]fake.Contract{
+		multicall.Address: fake.Methods(multicall.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getBlockNumber": fake.Returns(big.NewInt(100)),
+		}),
+		contracts.StETH: fake.Methods(staking.StETHABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"balanceOf":            fake.Returns(big.NewInt(3e18)),
+			"sharesOf":             fake.Returns(big.NewInt(25e17)),
+			"getTotalPooledEther":  fake.Returns(big.NewInt(6e18)),
+			"getTotalShares":       fake.Returns(big.NewInt(5e18)),
+			"getPooledEthByShares": fake.Returns(big.NewInt(12e17)),
+		}),
+		contracts.WstETH: fake.Methods(staking.WstETHABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"balanceOf":     fake.Returns(big.NewInt(2e18)),
+			"stEthPerToken": fake.Returns(big.NewInt(15e17)),
+		}),
+	})
+	client, err := multicall.NewClient(caller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := staking.Fetch(context.Background(), client, contracts, []common.Address{{19: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Block != 100 {
+		t.Errorf("got block %d, want 100", snapshot.Block)
+	}
+	rates := snapshot.Rates
+	if rates.StETHPerShare.Int64() != 12e17 || rates.TotalPooledEther.Int64() != 6e18 || rates.TotalShares.Int64() != 5e18 || rates.StETHPerWstETH.Int64() != 15e17 {
+		t.Errorf("got rates %+v", rates)
+	}
+	if rates.WstETHPerStETH != nil || rates.ETHPerRETH != nil || !errors.Is(rates.Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got rates %+v, want the revert of tokensPerStEth and rETH skipped", rates)
+	}
+	holding := snapshot.Holdings[0]
+	if holding.Err != nil || holding.StETH.Int64() != 3e18 || holding.StETHShares.Int64() != 25e17 || holding.WstETH.Int64() != 2e18 || holding.RETH != nil {
+		t.Errorf("got holding %+v", holding)
+	}
+	// 3 stETH and 2 wstETH worth 1.5 stETH each
+	if value := snapshot.Value(holding); value.Int64() != 6e18 {
+		t.Errorf("got value %s, want 6 ETH", value)
+	}
+	if got := caller.Executed(); len(got) != 1 || got[0] != 9 {
+		t.Errorf("executed multicalls of %v calls, want a single one of 9", got)
+	}
+}
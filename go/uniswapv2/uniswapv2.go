@@ -0,0 +1,140 @@
+// Package uniswapv2 reads the state of Uniswap V2 pairs, and of the pairs of
+// its many forks, in bulk through Multicall3, for LP analytics and arbitrage
+// scanners:
+//
+//	pairs, err := uniswapv2.Pairs(ctx, client, []common.Address{usdcWETH, daiWETH})
+//	for _, pair := range pairs {
+//		fmt.Println(pair.Token0, pair.Token1, pair.Price0(6, 18))
+//	}
+package uniswapv2
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ABI is the part of the Uniswap V2 pair ABI used by the helpers
+var ABI = helper.MustParseABI("uniswapv2", pairABI)
+
+const pairABI = `[
+	{"type":"function","name":"token0","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"token1","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"totalSupply","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"getReserves","stateMutability":"view","inputs":[],"outputs":[{"name":"reserve0","type":"uint112"},{"name":"reserve1","type":"uint112"},{"name":"blockTimestampLast","type":"uint32"}]}
+]`
+
+// reserves are the outputs of getReserves
+type reserves struct {
+	Reserve0           *big.Int
+	Reserve1           *big.Int
+	BlockTimestampLast uint32
+}
+
+// Pair is the state of a pair. Fields the pair failed to return are left as
+// zero values, and Err reports why.
+type Pair struct {
+	Address  common.Address
+	Token0   common.Address
+	Token1   common.Address
+	Reserve0 *big.Int
+	Reserve1 *big.Int
+	// BlockTimestampLast is the timestamp, modulo 2^32, of the last block the
+	// reserves were updated in
+	BlockTimestampLast uint32
+	// TotalSupply is the total supply of liquidity tokens
+	TotalSupply *big.Int
+	// Err joins the errors of the pair getters that failed, whose fields are
+	// left zero
+	Err error
+}
+
+// Pairs fetches the tokens, reserves and total supply of liquidity tokens of
+// every pair in a single batch, returning them in the order of the pairs. The
+// error is only set if the batch itself failed, the failures of a pair are
+// reported by its Pair.Err.
+func Pairs(ctx context.Context, client *multicall.Client, pairs []common.Address, opts ...multicall.CallOption) ([]Pair, error) {
+	batch := client.NewBatch()
+	calls := make([]*multicall.CallOf[reserves], len(pairs))
+	for i, pair := range pairs {
+		calls[i] = multicall.View[reserves](ABI, "getReserves")
+		batch.AddCall(pair, calls[i]).AllowFailure().
+			Add(pair, ABI, "token0").AllowFailure().
+			Add(pair, ABI, "token1").AllowFailure().
+			Add(pair, ABI, "totalSupply").AllowFailure()
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	state := make([]Pair, len(pairs))
+	for i, pair := range pairs {
+		state[i] = Pair{Address: pair}
+		reservesErr := results[4*i].Error(pair, "getReserves")
+		if reservesErr == nil {
+			r := calls[i].Value()
+			state[i].Reserve0, state[i].Reserve1, state[i].BlockTimestampLast = r.Reserve0, r.Reserve1, r.BlockTimestampLast
+		}
+		token0Err := results[4*i+1].Error(pair, "token0")
+		if token0Err == nil {
+			state[i].Token0 = results[4*i+1].Values[0].(common.Address)
+		}
+		token1Err := results[4*i+2].Error(pair, "token1")
+		if token1Err == nil {
+			state[i].Token1 = results[4*i+2].Values[0].(common.Address)
+		}
+		totalSupplyErr := results[4*i+3].Error(pair, "totalSupply")
+		if totalSupplyErr == nil {
+			state[i].TotalSupply = results[4*i+3].Values[0].(*big.Int)
+		}
+		state[i].Err = errors.Join(reservesErr, token0Err, token1Err, totalSupplyErr)
+	}
+	return state, nil
+}
+
+// Price0 returns the spot price of token0 in units of token1, adjusted for
+// the decimals of the tokens, or nil if the pair has no reserves
+func (p Pair) Price0(decimals0, decimals1 uint8) *big.Float {
+	return price(p.Reserve0, p.Reserve1, decimals0, decimals1)
+}
+
+// Price1 returns the spot price of token1 in units of token0, like Price0
+func (p Pair) Price1(decimals0, decimals1 uint8) *big.Float {
+	return price(p.Reserve1, p.Reserve0, decimals1, decimals0)
+}
+
+// price returns the price of the base token in units of the quote token
+func price(base, quote *big.Int, baseDecimals, quoteDecimals uint8) *big.Float {
+	if base == nil || quote == nil || base.Sign() == 0 {
+		return nil
+	}
+	scaledQuote := new(big.Int).Mul(quote, pow10(baseDecimals))
+	scaledBase := new(big.Int).Mul(base, pow10(quoteDecimals))
+	return new(big.Float).Quo(new(big.Float).SetInt(scaledQuote), new(big.Float).SetInt(scaledBase))
+}
+
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// AmountOut returns the amount of the other token a swap of amountIn of
+// token0, or of token1 if zeroForOne is false, receives from the pair at its
+// current reserves, after the 0.3% fee of Uniswap V2. Forks with another fee
+// differ. It returns nil if the pair has no reserves.
+func (p Pair) AmountOut(amountIn *big.Int, zeroForOne bool) *big.Int {
+	reserveIn, reserveOut := p.Reserve0, p.Reserve1
+	if !zeroForOne {
+		reserveIn, reserveOut = p.Reserve1, p.Reserve0
+	}
+	if reserveIn == nil || reserveOut == nil || reserveIn.Sign() == 0 || reserveOut.Sign() == 0 {
+		return nil
+	}
+	amountInWithFee := new(big.Int).Mul(amountIn, big.NewInt(997))
+	numerator := new(big.Int).Mul(amountInWithFee, reserveOut)
+	denominator := new(big.Int).Add(new(big.Int).Mul(reserveIn, big.NewInt(1000)), amountInWithFee)
+	return numerator.Quo(numerator, denominator)
+}
@@ -0,0 +1,66 @@
+package uniswapv2_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+	"github.com/john-na4/multicall3/go/uniswapv2"
+)
+
+var (
+	pair    = common.HexToAddress("0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc")
+	notPair = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	usdc    = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	weth    = common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+)
+
+func TestPairs(t *testing.T) {
+	// 2,000,000 USDC and 1,000 WETH
+	reserve0, reserve1 := big.NewInt(2e12), new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		pair: fake.Methods(uniswapv2.ABI, map[string]func(args []interface{}) ([]interface{}, error){
+			"getReserves": fake.Returns(reserve0, reserve1, uint32(1700000000)),
+			"token0":      fake.Returns(usdc),
+			"token1":      fake.Returns(weth),
+			"totalSupply": fake.Returns(big.NewInt(5e15)),
+		}),
+		notPair: fake.Methods(uniswapv2.ABI, nil),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairs, err := uniswapv2.Pairs(context.Background(), client, []common.Address{pair, notPair})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := pairs[0]
+	if got.Err != nil {
+		t.Fatal(got.Err)
+	}
+	if got.Token0 != usdc || got.Token1 != weth || got.Reserve0.Cmp(reserve0) != 0 || got.Reserve1.Cmp(reserve1) != 0 ||
+		got.BlockTimestampLast != 1700000000 || got.TotalSupply.Int64() != 5e15 {
+		t.Errorf("got pair %+v", got)
+	}
+	if price, _ := got.Price1(6, 18).Float64(); price != 2000 {
+		t.Errorf("got WETH price %g USDC, want 2000", price)
+	}
+	if price, _ := got.Price0(6, 18).Float64(); price != 0.0005 {
+		t.Errorf("got USDC price %g WETH, want 0.0005", price)
+	}
+	// 1 WETH for USDC, after the fee and the price impact
+	if out := got.AmountOut(big.NewInt(1e18), false); out.Int64() != 1992013962 {
+		t.Errorf("got %s USDC out, want 1992013962", out)
+	}
+
+	if !errors.Is(pairs[1].Err, multicall.ErrExecutionReverted) || pairs[1].Reserve0 != nil {
+		t.Errorf("got pair %+v for a contract that is not a pair, want reverted calls", pairs[1])
+	}
+	if pairs[1].Price0(18, 18) != nil || pairs[1].AmountOut(big.NewInt(1), true) != nil {
+		t.Error("pair without reserves has a price")
+	}
+}
@@ -0,0 +1,218 @@
+// Package uniswapv3 reads the state of Uniswap V3 pools in bulk through
+// Multicall3, along with the oracle observations needed for time-weighted
+// average prices:
+//
+//	pools, err := uniswapv3.Pools(ctx, client, []common.Address{usdcWETH500}, 30*60)
+//	for _, pool := range pools {
+//		fmt.Println(pool.Price(6, 18), pool.TWAPPrice(6, 18))
+//	}
+package uniswapv3
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/helper"
+	"github.com/john-na4/multicall3/go/multicall"
+)
+
+// ABI is the part of the Uniswap V3 pool ABI used by the helpers
+var ABI = helper.MustParseABI("uniswapv3", poolABI)
+
+const poolABI = `[
+	{"type":"function","name":"token0","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"token1","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"fee","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint24"}]},
+	{"type":"function","name":"tickSpacing","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"int24"}]},
+	{"type":"function","name":"liquidity","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint128"}]},
+	{"type":"function","name":"slot0","stateMutability":"view","inputs":[],"outputs":[{"name":"sqrtPriceX96","type":"uint160"},{"name":"tick","type":"int24"},{"name":"observationIndex","type":"uint16"},{"name":"observationCardinality","type":"uint16"},{"name":"observationCardinalityNext","type":"uint16"},{"name":"feeProtocol","type":"uint8"},{"name":"unlocked","type":"bool"}]},
+	{"type":"function","name":"observe","stateMutability":"view","inputs":[{"name":"secondsAgos","type":"uint32[]"}],"outputs":[{"name":"tickCumulatives","type":"int56[]"},{"name":"secondsPerLiquidityCumulativeX128s","type":"uint160[]"}]}
+]`
+
+// slot0 are the outputs of slot0
+type slot0 struct {
+	SqrtPriceX96               *big.Int
+	Tick                       *big.Int
+	ObservationIndex           uint16
+	ObservationCardinality     uint16
+	ObservationCardinalityNext uint16
+	FeeProtocol                uint8
+	Unlocked                   bool
+}
+
+// observations are the outputs of observe
+type observations struct {
+	TickCumulatives                    []*big.Int
+	SecondsPerLiquidityCumulativeX128s []*big.Int
+}
+
+// Pool is the state of a pool. Fields the pool failed to return are left as
+// zero values, and Err reports why.
+type Pool struct {
+	Address common.Address
+	Token0  common.Address
+	Token1  common.Address
+	// Fee is the fee of the pool in hundredths of a basis point
+	Fee         uint32
+	TickSpacing int
+	// SqrtPriceX96 is the square root of the price of token0 in token1, as a
+	// Q64.96 fixed point number
+	SqrtPriceX96 *big.Int
+	Tick         int
+	// ObservationCardinality is the number of oracle observations the pool
+	// keeps, which bounds how far back a TWAP can reach
+	ObservationCardinality uint16
+	// Liquidity is the liquidity in range
+	Liquidity *big.Int
+	// Window is the TWAP window, in seconds, the observations were read for
+	Window uint32
+	// TickCumulatives are the tick accumulators Window seconds ago and now,
+	// nil if no window was asked for or observe failed
+	TickCumulatives []*big.Int
+	// Err joins the errors of the pool getters that failed, including
+	// observe when the window reaches further back than the pool's
+	// observations
+	Err error
+}
+
+// Pools fetches the tokens, fee, tick spacing, slot0 and liquidity of every
+// pool in a single batch, returning them in the order of the pools. If window
+// is not zero, the tick accumulators window seconds ago and now are fetched
+// with observe too, for TWAPs over the window. The error is only set if the
+// batch itself failed, the failures of a pool are reported by its Pool.Err.
+func Pools(ctx context.Context, client *multicall.Client, pools []common.Address, window uint32, opts ...multicall.CallOption) ([]Pool, error) {
+	calls := 6
+	if window > 0 {
+		calls++
+	}
+	batch := client.NewBatch()
+	slots := make([]*multicall.CallOf[slot0], len(pools))
+	observed := make([]*multicall.CallOf[observations], len(pools))
+	for i, pool := range pools {
+		slots[i] = multicall.View[slot0](ABI, "slot0")
+		batch.Add(pool, ABI, "token0").AllowFailure().
+			Add(pool, ABI, "token1").AllowFailure().
+			Add(pool, ABI, "fee").AllowFailure().
+			Add(pool, ABI, "tickSpacing").AllowFailure().
+			AddCall(pool, slots[i]).AllowFailure().
+			Add(pool, ABI, "liquidity").AllowFailure()
+		if window > 0 {
+			observed[i] = multicall.View[observations](ABI, "observe", []uint32{window, 0})
+			batch.AddCall(pool, observed[i]).AllowFailure()
+		}
+	}
+	results, err := batch.ExecuteResults(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	state := make([]Pool, len(pools))
+	for i, pool := range pools {
+		poolResults := results[i*calls : (i+1)*calls]
+		errs := make([]error, calls)
+		state[i] = Pool{Address: pool, Window: window}
+		if errs[0] = poolResults[0].Error(pool, "token0"); errs[0] == nil {
+			state[i].Token0 = poolResults[0].Values[0].(common.Address)
+		}
+		if errs[1] = poolResults[1].Error(pool, "token1"); errs[1] == nil {
+			state[i].Token1 = poolResults[1].Values[0].(common.Address)
+		}
+		if errs[2] = poolResults[2].Error(pool, "fee"); errs[2] == nil {
+			state[i].Fee = uint32(poolResults[2].Values[0].(*big.Int).Uint64())
+		}
+		if errs[3] = poolResults[3].Error(pool, "tickSpacing"); errs[3] == nil {
+			state[i].TickSpacing = int(poolResults[3].Values[0].(*big.Int).Int64())
+		}
+		if errs[4] = poolResults[4].Error(pool, "slot0"); errs[4] == nil {
+			slot := slots[i].Value()
+			state[i].SqrtPriceX96 = slot.SqrtPriceX96
+			state[i].Tick = int(slot.Tick.Int64())
+			state[i].ObservationCardinality = slot.ObservationCardinality
+		}
+		if errs[5] = poolResults[5].Error(pool, "liquidity"); errs[5] == nil {
+			state[i].Liquidity = poolResults[5].Values[0].(*big.Int)
+		}
+		if window > 0 {
+			if errs[6] = poolResults[6].Error(pool, "observe"); errs[6] == nil {
+				state[i].TickCumulatives = observed[i].Value().TickCumulatives
+			}
+		}
+		state[i].Err = errors.Join(errs...)
+	}
+	return state, nil
+}
+
+// Price returns the spot price of token0 in units of token1, adjusted for the
+// decimals of the tokens, or nil if the pool failed to return it
+func (p Pool) Price(decimals0, decimals1 uint8) *big.Float {
+	if p.SqrtPriceX96 == nil {
+		return nil
+	}
+	return SqrtPriceX96ToPrice(p.SqrtPriceX96, decimals0, decimals1)
+}
+
+// TWAPTick returns the arithmetic mean tick over the pool's Window, rounded
+// towards negative infinity like the Uniswap V3 OracleLibrary, and false if
+// the observations were not fetched
+func (p Pool) TWAPTick() (int, bool) {
+	if len(p.TickCumulatives) != 2 || p.Window == 0 {
+		return 0, false
+	}
+	delta := new(big.Int).Sub(p.TickCumulatives[1], p.TickCumulatives[0])
+	window := big.NewInt(int64(p.Window))
+	tick, rem := new(big.Int).QuoRem(delta, window, new(big.Int))
+	if delta.Sign() < 0 && rem.Sign() != 0 {
+		tick.Sub(tick, big.NewInt(1))
+	}
+	return int(tick.Int64()), true
+}
+
+// TWAPPrice returns the time-weighted average price of token0 in units of
+// token1 over the pool's Window, adjusted for the decimals of the tokens, or
+// nil if the observations were not fetched
+func (p Pool) TWAPPrice(decimals0, decimals1 uint8) *big.Float {
+	tick, ok := p.TWAPTick()
+	if !ok {
+		return nil
+	}
+	return TickToPrice(tick, decimals0, decimals1)
+}
+
+// q96 is 2^96, the scale of Q64.96 fixed point numbers
+var q96 = new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 96))
+
+// SqrtPriceX96ToPrice converts the square root of a price, as a Q64.96 fixed
+// point number, to the price of token0 in units of token1, adjusted for the
+// decimals of the tokens
+func SqrtPriceX96ToPrice(sqrtPriceX96 *big.Int, decimals0, decimals1 uint8) *big.Float {
+	sqrtPrice := new(big.Float).SetPrec(256).SetInt(sqrtPriceX96)
+	sqrtPrice.Quo(sqrtPrice, q96)
+	price := new(big.Float).SetPrec(256).Mul(sqrtPrice, sqrtPrice)
+	return adjust(price, decimals0, decimals1)
+}
+
+// TickToPrice converts a tick to the price of token0 in units of token1,
+// 1.0001^tick, adjusted for the decimals of the tokens, with the precision
+// of a float64
+func TickToPrice(tick int, decimals0, decimals1 uint8) *big.Float {
+	return adjust(big.NewFloat(math.Pow(1.0001, float64(tick))), decimals0, decimals1)
+}
+
+// adjust scales a raw price by 10^(decimals0-decimals1)
+func adjust(price *big.Float, decimals0, decimals1 uint8) *big.Float {
+	exponent := int64(decimals0) - int64(decimals1)
+	scale := new(big.Float).SetPrec(256).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(abs(exponent)), nil))
+	if exponent < 0 {
+		return price.Quo(price, scale)
+	}
+	return price.Mul(price, scale)
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,117 @@
+package uniswapv3_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/john-na4/multicall3/go/internal/fake"
+	"github.com/john-na4/multicall3/go/multicall"
+	"github.com/john-na4/multicall3/go/uniswapv3"
+)
+
+var (
+	pool  = common.HexToAddress("0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640")
+	young = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	usdc  = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	weth  = common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+)
+
+// q96 is 2^96, the scale of Q64.96 fixed point numbers
+var q96 = new(big.Int).Lsh(big.NewInt(1), 96)
+
+// poolMethods are those of a pool with a price of 4, and observations over
+// 1800 seconds if observe is set
+func poolMethods(observe bool) map[string]func(args []interface{}) ([]interface{}, error) {
+	methods := map[string]func(args []interface{}) ([]interface{}, error){
+		"token0":      fake.Returns(usdc),
+		"token1":      fake.Returns(weth),
+		"fee":         fake.Returns(big.NewInt(500)),
+		"tickSpacing": fake.Returns(big.NewInt(10)),
+		"liquidity":   fake.Returns(big.NewInt(1e18)),
+		"slot0":       fake.Returns(new(big.Int).Mul(big.NewInt(2), q96), big.NewInt(13863), uint16(7), uint16(720), uint16(720), uint8(0), true),
+	}
+	if observe {
+		methods["observe"] = func(args []interface{}) ([]interface{}, error) {
+			if !reflect.DeepEqual(args[0], []uint32{1800, 0}) {
+				return nil, &fake.Revert{}
+			}
+			return []interface{}{[]*big.Int{big.NewInt(1000), big.NewInt(1000 + 1800*200)}, []*big.Int{big.NewInt(0), big.NewInt(1)}}, nil
+		}
+	}
+	return methods
+}
+
+func TestPools(t *testing.T) {
+	client, err := multicall.NewClient(fake.NewCaller(map[common.Address]fake.Contract{
+		pool: fake.Methods(uniswapv3.ABI, poolMethods(true)),
+		// A pool too young for the window, without the observations
+		young: fake.Methods(uniswapv3.ABI, poolMethods(false)),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pools, err := uniswapv3.Pools(context.Background(), client, []common.Address{pool, young}, 1800)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := pools[0]
+	if got.Err != nil {
+		t.Fatal(got.Err)
+	}
+	if got.Token0 != usdc || got.Token1 != weth || got.Fee != 500 || got.TickSpacing != 10 || got.Tick != 13863 ||
+		got.ObservationCardinality != 720 || got.Liquidity.Int64() != 1e18 || got.Window != 1800 {
+		t.Errorf("got pool %+v", got)
+	}
+	if price, _ := got.Price(18, 18).Float64(); price != 4 {
+		t.Errorf("got price %g, want 4", price)
+	}
+	if tick, ok := got.TWAPTick(); !ok || tick != 200 {
+		t.Errorf("got TWAP tick %d, %t, want 200", tick, ok)
+	}
+
+	if got := pools[1]; got.Token0 != usdc || got.TickCumulatives != nil || !errors.Is(got.Err, multicall.ErrExecutionReverted) {
+		t.Errorf("got pool %+v, want the revert of observe", got)
+	}
+	if _, ok := pools[1].TWAPTick(); ok || pools[1].TWAPPrice(6, 18) != nil {
+		t.Error("got a TWAP without observations")
+	}
+
+	// Without a window, observe is not called
+	pools, err = uniswapv3.Pools(context.Background(), client, []common.Address{young}, 0)
+	if err != nil || pools[0].Err != nil {
+		t.Errorf("got error %v, %v without a window", err, pools[0].Err)
+	}
+}
+
+func TestTWAPTickRoundsDown(t *testing.T) {
+	// The mean tick is -1801/1800, rounded towards negative infinity
+	p := uniswapv3.Pool{Window: 1800, TickCumulatives: []*big.Int{big.NewInt(0), big.NewInt(-1801)}}
+	if tick, ok := p.TWAPTick(); !ok || tick != -2 {
+		t.Errorf("got TWAP tick %d, want -2", tick)
+	}
+	p.TickCumulatives[1] = big.NewInt(-1800)
+	if tick, _ := p.TWAPTick(); tick != -1 {
+		t.Errorf("got TWAP tick %d, want -1", tick)
+	}
+}
+
+func TestPrices(t *testing.T) {
+	// A raw price of 4 is scaled by 10^(decimals0-decimals1)
+	sqrtPrice := new(big.Int).Mul(big.NewInt(2), q96)
+	if price, _ := uniswapv3.SqrtPriceX96ToPrice(sqrtPrice, 6, 18).Float64(); price != 4e-12 {
+		t.Errorf("got price %g, want 4e-12", price)
+	}
+	if price, _ := uniswapv3.SqrtPriceX96ToPrice(sqrtPrice, 18, 6).Float64(); price != 4e12 {
+		t.Errorf("got price %g, want 4e12", price)
+	}
+	if price, _ := uniswapv3.TickToPrice(0, 18, 18).Float64(); price != 1 {
+		t.Errorf("got price %g at tick 0, want 1", price)
+	}
+	if price, _ := uniswapv3.TickToPrice(13863, 18, 18).Float64(); price < 3.999 || price > 4.001 {
+		t.Errorf("got price %g at tick 13863, want about 4", price)
+	}
+}